@@ -0,0 +1,577 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"maintainerd/api"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+const (
+	projectAuditLimit = 25
+	defaultAuditLimit = 100
+)
+
+// projectRow is a project placed in the project hierarchy, Depth is 0 for top level projects.
+type projectRow struct {
+	Project     model.Project
+	Depth       int
+	Maintainers int
+	Teams       []*model.ServiceTeam // the project's team on each service listed alongside, nil where it has none
+}
+
+// projectTree returns every project with its subprojects after it, ordered by name at each level.
+func projectTree(projects map[string]model.Project) []projectRow {
+	children := map[uint][]model.Project{}
+	var roots []model.Project
+	for _, p := range projects {
+		if p.ParentProjectID == nil {
+			roots = append(roots, p)
+			continue
+		}
+		children[*p.ParentProjectID] = append(children[*p.ParentProjectID], p)
+	}
+	byName := func(ps []model.Project) {
+		sort.Slice(ps, func(i, j int) bool { return ps[i].Name < ps[j].Name })
+	}
+	byName(roots)
+	var rows []projectRow
+	var walk func(p model.Project, depth int)
+	walk = func(p model.Project, depth int) {
+		rows = append(rows, projectRow{Project: p, Depth: depth})
+		byName(children[p.ID])
+		for _, c := range children[p.ID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, p := range roots {
+		walk(p, 0)
+	}
+	// Projects whose parent has gone are listed at the top level rather than lost
+	if len(rows) < len(projects) {
+		listed := make(map[uint]bool, len(rows))
+		for _, row := range rows {
+			listed[row.Project.ID] = true
+		}
+		var orphans []model.Project
+		for _, p := range projects {
+			if !listed[p.ID] {
+				orphans = append(orphans, p)
+			}
+		}
+		byName(orphans)
+		for _, p := range orphans {
+			rows = append(rows, projectRow{Project: p})
+		}
+	}
+	return rows
+}
+
+// project returns the project called name, matched exactly or else ignoring case.
+func (s *Server) project(ctx context.Context, name string) (model.Project, map[string]model.Project, error) {
+	projects, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		return model.Project{}, nil, err
+	}
+	if p, ok := projects[name]; ok {
+		return p, projects, nil
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			return p, projects, nil
+		}
+	}
+	return model.Project{}, nil, fmt.Errorf("%w: %s", db.ErrProjectNotFound, name)
+}
+
+// handleProjects lists every project in the hierarchy with its number of maintainers.
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	infos, err := s.Store.GetProjectMaintainersMap(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	projects := make(map[string]model.Project, len(infos))
+	for _, info := range infos {
+		projects[info.Project.Name] = info.Project
+	}
+	rows := projectTree(projects)
+	for i := range rows {
+		rows[i].Maintainers = len(infos[rows[i].Project.ID].Maintainers)
+	}
+	s.render(w, r, http.StatusOK, "projects", map[string]any{"Rows": rows})
+}
+
+// serviceTeam is a project's team on a service, Team is nil when the project has none.
+type serviceTeam struct {
+	Service model.Service
+	Team    *model.ServiceTeam
+}
+
+// handleProject shows a project's place in the hierarchy, its roster with forms to change it, its service teams with
+// onboarding buttons and its recent audit history.
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, projects, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	var parent string
+	var subprojects []string
+	for _, p := range projects {
+		if project.ParentProjectID != nil && p.ID == *project.ParentProjectID {
+			parent = p.Name
+		}
+		if p.ParentProjectID != nil && *p.ParentProjectID == project.ID {
+			subprojects = append(subprojects, p.Name)
+		}
+	}
+	sort.Strings(subprojects)
+
+	maintainers, err := s.Store.GetMaintainersByProject(ctx, project.ID)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	sort.Slice(maintainers, func(i, j int) bool {
+		return strings.ToLower(maintainers[i].Name) < strings.ToLower(maintainers[j].Name)
+	})
+
+	services, err := s.Store.ListServices(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	teams := make([]serviceTeam, 0, len(services))
+	for _, svc := range services {
+		st, err := s.Store.GetServiceTeamByProject(ctx, project.ID, svc.ID)
+		if err != nil {
+			s.renderError(w, r, err)
+			return
+		}
+		teams = append(teams, serviceTeam{Service: svc, Team: st})
+	}
+
+	audit, err := s.Store.ListAuditLogs(ctx, db.AuditFilter{ProjectID: &project.ID, Limit: projectAuditLimit})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	s.render(w, r, http.StatusOK, "project", map[string]any{
+		"Project":     project,
+		"Parent":      parent,
+		"Subprojects": subprojects,
+		"Maintainers": maintainers,
+		"Teams":       teams,
+		"Audit":       audit,
+		"Statuses":    []model.MaintainerStatus{model.ActiveMaintainer, model.EmeritusMaintainer, model.RetiredMaintainer},
+		"CanOnboard":  s.Onboarder != nil,
+	})
+}
+
+// handleServices shows which projects have a team on each service.
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projects, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	services, err := s.Store.ListServices(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	rows := projectTree(projects)
+	covered := make([]int, len(services))
+	for i, svc := range services {
+		teams, err := s.Store.GetProjectServiceTeamMap(ctx, svc.Name)
+		if err != nil {
+			s.renderError(w, r, err)
+			return
+		}
+		for j := range rows {
+			team := teams[rows[j].Project.ID]
+			rows[j].Teams = append(rows[j].Teams, team)
+			if team != nil {
+				covered[i]++
+			}
+		}
+	}
+	s.render(w, r, http.StatusOK, "services", map[string]any{"Services": services, "Rows": rows, "Covered": covered, "Total": len(rows)})
+}
+
+// drift is the difference between the maintainers expected in a project's team on a service and the team's members.
+type drift struct {
+	Project model.Project
+	Team    *model.ServiceTeam
+	Missing []string // maintainers who are not members of the team
+	Extra   []string // members of the team who are not maintainers of the project
+	Error   string
+}
+
+// handleDrift compares each project's roster with the members of its team on a service, FOSSA unless the service
+// query parameter names another.
+func (s *Server) handleDrift(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	service := r.URL.Query().Get("service")
+	if service == "" {
+		service = "FOSSA"
+	}
+	data := map[string]any{"Service": service, "Available": s.Teams != nil}
+	if s.Teams == nil {
+		s.render(w, r, http.StatusOK, "drift", data)
+		return
+	}
+	teams, err := s.Store.GetProjectServiceTeamMap(ctx, service)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	projects, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	var rows []drift
+	inSync := 0
+	for _, row := range projectTree(projects) {
+		team, ok := teams[row.Project.ID]
+		if !ok {
+			continue
+		}
+		d := s.teamDrift(ctx, service, row.Project, team, teams)
+		if d.Error == "" && len(d.Missing)+len(d.Extra) == 0 {
+			inSync++
+			continue
+		}
+		rows = append(rows, d)
+	}
+	data["Rows"], data["InSync"] = rows, inSync
+	s.render(w, r, http.StatusOK, "drift", data)
+}
+
+// teamDrift compares team, project's team on service, with the active maintainers expected in it: the project's own
+// and those of its subprojects that do not have a team of their own.
+func (s *Server) teamDrift(ctx context.Context, service string, project model.Project, team *model.ServiceTeam, teams map[uint]*model.ServiceTeam) drift {
+	d := drift{Project: project, Team: team}
+	expected, err := db.ExpectedTeamMembers(ctx, s.Store, project.ID, teams)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	members, err := s.Teams.TeamMemberEmails(ctx, service, team.ServiceTeamID)
+	if err != nil {
+		d.Error = err.Error()
+		return d
+	}
+	d.Missing, d.Extra = db.CompareTeam(expected, members)
+	return d
+}
+
+// handleAudit lists audit log entries, filtered like the /admin/audit endpoint.
+func (s *Server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := db.AuditQuery{
+		Project:       params.Get("project"),
+		Maintainer:    params.Get("maintainer"),
+		Service:       params.Get("service"),
+		Action:        params.Get("action"),
+		Actor:         params.Get("actor"),
+		CorrelationID: params.Get("correlation_id"),
+		Since:         params.Get("since"),
+		Until:         params.Get("until"),
+		Limit:         defaultAuditLimit,
+	}
+	data := map[string]any{"Query": query}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			data["Error"] = "limit must be a positive integer"
+			s.render(w, r, http.StatusBadRequest, "audit", data)
+			return
+		}
+		query.Limit = n
+		data["Query"] = query
+	}
+	filter, err := query.Filter(r.Context(), s.Store)
+	if err != nil {
+		data["Error"] = err.Error()
+		s.render(w, r, http.StatusBadRequest, "audit", data)
+		return
+	}
+	entries, err := s.Store.ListAuditLogs(r.Context(), filter)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	data["Entries"] = entries
+	s.render(w, r, http.StatusOK, "audit", data)
+}
+
+func projectPath(project model.Project) string {
+	return "/projects/" + url.PathEscape(project.Name)
+}
+
+// handleAddMaintainer adds the maintainer in the roster form to a project.
+func (s *Server) handleAddMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, _, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	github := strings.TrimPrefix(strings.TrimSpace(r.PostFormValue("github")), "@")
+	if github == "" {
+		redirect(w, r, projectPath(project), "github-required")
+		return
+	}
+	maintainer := model.Maintainer{
+		Name:          strings.TrimSpace(r.PostFormValue("name")),
+		GitHubAccount: github,
+		Email:         strings.TrimSpace(r.PostFormValue("email")),
+	}
+	if _, err := s.Store.AddMaintainerToProject(ctx, project.ID, maintainer, strings.TrimSpace(r.PostFormValue("company"))); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	redirect(w, r, projectPath(project), "maintainer-added")
+}
+
+// handleRemoveMaintainer removes a maintainer from a project, the maintainer stays registered.
+func (s *Server) handleRemoveMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, _, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, r.PathValue("github"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	if err := s.Store.RemoveMaintainerFromProject(ctx, project.ID, maintainer.ID); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	redirect(w, r, projectPath(project), "maintainer-removed")
+}
+
+// handleUpdateMaintainer sets the status and company chosen in a maintainer's row of a project's roster.
+func (s *Server) handleUpdateMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	project, _, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, r.PathValue("github"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	status := model.MaintainerStatus(r.PostFormValue("status"))
+	if !status.IsValid() {
+		redirect(w, r, projectPath(project), "invalid-status")
+		return
+	}
+	if err := s.Store.SetMaintainerStatus(ctx, maintainer.ID, status); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	if company := strings.TrimSpace(r.PostFormValue("company")); company != "" && company != maintainer.Company.Name {
+		if err := s.Store.SetMaintainerCompany(ctx, maintainer.ID, company); err != nil {
+			s.renderError(w, r, err)
+			return
+		}
+	}
+	redirect(w, r, projectPath(project), "maintainer-updated")
+}
+
+// handleOnboard onboards a project's maintainers to a service and shows the actions taken.
+func (s *Server) handleOnboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if s.Onboarder == nil {
+		s.render(w, r, http.StatusNotImplemented, "error", map[string]any{"Message": "service onboarding is not available"})
+		return
+	}
+	project, _, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	service := r.PathValue("service")
+	if err := s.Store.LogAuditEvent(ctx, s.Logger, model.AuditLog{
+		ProjectID: project.ID,
+		Action:    "ONBOARDING_REQUESTED",
+		Message:   fmt.Sprintf("onboarding of %s to %s requested", project.Name, service),
+	}); err != nil {
+		s.Logger.Warnf("admin: failed to audit onboarding of %s: %v", project.Name, err)
+	}
+	actions, err := s.Onboarder.Onboard(ctx, project, service)
+	if errors.Is(err, api.ErrUnsupportedService) {
+		redirect(w, r, projectPath(project), "unsupported-service")
+		return
+	}
+	data := map[string]any{"Project": project, "Service": service, "Actions": actions}
+	if err != nil {
+		data["Error"] = err.Error()
+	}
+	s.render(w, r, http.StatusOK, "onboarded", data)
+}
+
+// changeRequest is a queued change request with the names of its project and service.
+type changeRequest struct {
+	model.ChangeRequest
+	Project string
+	Service string
+}
+
+// handleRequests lists the change requests maintainers made through the portal, pending ones unless status is all,
+// approved or rejected.
+func (s *Server) handleRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status := r.URL.Query().Get("status")
+	filter := db.ChangeRequestFilter{Status: model.ChangePending, Limit: defaultAuditLimit}
+	switch status {
+	case "", string(model.ChangePending):
+		status = string(model.ChangePending)
+	case "all":
+		filter.Status = ""
+	case string(model.ChangeApproved), string(model.ChangeRejected):
+		filter.Status = model.ChangeRequestStatus(status)
+	default:
+		s.render(w, r, http.StatusBadRequest, "error", map[string]any{"Message": fmt.Sprintf("unknown status %q", status)})
+		return
+	}
+	requests, err := s.Store.ListChangeRequests(ctx, filter)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	projects, services, err := s.projectAndServiceNames(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	rows := make([]changeRequest, len(requests))
+	for i, req := range requests {
+		rows[i] = changeRequest{ChangeRequest: req}
+		if req.ProjectID != nil {
+			rows[i].Project = projects[*req.ProjectID].Name
+		}
+		if req.ServiceID != nil {
+			rows[i].Service = services[*req.ServiceID].Name
+		}
+	}
+	s.render(w, r, http.StatusOK, "requests", map[string]any{
+		"Requests": rows,
+		"Status":   status,
+		"Statuses": []string{"pending", "approved", "rejected", "all"},
+	})
+}
+
+func (s *Server) projectAndServiceNames(ctx context.Context) (map[uint]model.Project, map[uint]model.Service, error) {
+	byName, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	projects := make(map[uint]model.Project, len(byName))
+	for _, p := range byName {
+		projects[p.ID] = p
+	}
+	list, err := s.Store.ListServices(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	services := make(map[uint]model.Service, len(list))
+	for _, svc := range list {
+		services[svc.ID] = svc
+	}
+	return projects, services, nil
+}
+
+// handleReviewRequest approves or rejects a change request. Approving service access onboards the project to the
+// service, the actions taken are kept in the request's note.
+func (s *Server) handleReviewRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil {
+		s.render(w, r, http.StatusNotFound, "error", map[string]any{"Message": "no such request"})
+		return
+	}
+	approve := r.PathValue("decision") == "approve"
+	if !approve && r.PathValue("decision") != "reject" {
+		http.NotFound(w, r)
+		return
+	}
+	note := strings.TrimSpace(r.PostFormValue("note"))
+
+	var onboard func() ([]string, error)
+	if approve {
+		pending, err := s.Store.ListChangeRequests(ctx, db.ChangeRequestFilter{Status: model.ChangePending})
+		if err != nil {
+			s.renderError(w, r, err)
+			return
+		}
+		for _, req := range pending {
+			if req.ID != uint(id) || req.Kind != model.ChangeServiceAccess {
+				continue
+			}
+			if s.Onboarder == nil {
+				redirect(w, r, "/requests", "onboarding-unavailable")
+				return
+			}
+			projects, services, err := s.projectAndServiceNames(ctx)
+			if err != nil {
+				s.renderError(w, r, err)
+				return
+			}
+			project, okProject := projects[*req.ProjectID]
+			service, okService := services[*req.ServiceID]
+			if !okProject || !okService {
+				redirect(w, r, "/requests", "request-target-missing")
+				return
+			}
+			onboard = func() ([]string, error) { return s.Onboarder.Onboard(ctx, project, service.Name) }
+		}
+	}
+
+	if onboard != nil {
+		actions, err := onboard()
+		if err != nil {
+			if errors.Is(err, api.ErrUnsupportedService) {
+				redirect(w, r, "/requests", "unsupported-service")
+				return
+			}
+			actions = append(actions, "onboarding did not complete: "+err.Error())
+		}
+		note = strings.TrimSpace(note + "\n" + strings.Join(actions, "\n"))
+	}
+	req, err := s.Store.ReviewChangeRequest(ctx, uint(id), approve, note)
+	switch {
+	case errors.Is(err, db.ErrChangeRequestNotFound):
+		redirect(w, r, "/requests", "request-not-found")
+	case errors.Is(err, db.ErrChangeRequestReviewed):
+		redirect(w, r, "/requests", "request-reviewed")
+	case errors.Is(err, db.ErrEmailInUse):
+		redirect(w, r, "/requests", "email-in-use")
+	case err != nil:
+		s.renderError(w, r, err)
+	case req.Status == model.ChangeApproved:
+		redirect(w, r, "/requests", "request-approved")
+	default:
+		redirect(w, r, "/requests", "request-rejected")
+	}
+}
@@ -0,0 +1,315 @@
+// Package admin serves an HTML front end for the CNCF projects team under /admin/ui. It browses projects, rosters,
+// service coverage, reconciliation drift and the audit log, and makes the same audited changes as the API. Changes
+// maintainers ask for through the self-service portal are approved or rejected here. Like the
+// API's writes it needs a foundation officer's API token. The token entered on the login page stays on the server, the
+// browser's cookie only holds a random session ID.
+package admin
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"maintainerd/api"
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"go.uber.org/zap"
+)
+
+// Prefix is the path under which the UI is served.
+const Prefix = "/admin/ui"
+
+const (
+	sessionCookie = "maintainerd_session"
+	sessionMaxAge = 12 * time.Hour
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// TeamLister lists the email addresses of the members of a team on a service. It is used to find drift between a
+// project's roster and its service team.
+type TeamLister interface {
+	TeamMemberEmails(ctx context.Context, service string, teamID int) ([]string, error)
+}
+
+// Server serves the UI from Store.
+type Server struct {
+	Store     db.Store
+	Onboarder api.Onboarder // onboards projects to services, the onboard buttons are hidden when nil
+	Teams     TeamLister    // lists service team members, the drift page is unavailable when nil
+	Logger    *zap.SugaredLogger
+	pages     map[string]*template.Template
+
+	mu       sync.Mutex
+	sessions map[string]storedSession // by session ID
+}
+
+// storedSession is the API token an officer signed in with, until the session expires.
+type storedSession struct {
+	token   string
+	expires time.Time
+}
+
+// NewServer returns a Server reading from and writing to store.
+func NewServer(store db.Store) *Server {
+	return &Server{Store: store, Logger: zap.NewNop().Sugar(), pages: parsePages(), sessions: map[string]storedSession{}}
+}
+
+func parsePages() map[string]*template.Template {
+	funcs := template.FuncMap{
+		"missing": db.MissingValue,
+		"time":    func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+		"prefix":  func() string { return Prefix },
+	}
+	layout := template.Must(template.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html"))
+	pages := map[string]*template.Template{}
+	for _, name := range []string{"login", "projects", "project", "services", "drift", "audit", "requests", "onboarded", "error"} {
+		page := template.Must(layout.Clone())
+		pages[name] = template.Must(page.ParseFS(templateFS, "templates/"+name+".html"))
+	}
+	return pages
+}
+
+// Register mounts the UI on mux.
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+Prefix+"/{$}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, Prefix+"/projects", http.StatusSeeOther)
+	})
+	mux.HandleFunc("GET "+Prefix+"/login", s.handleLoginPage)
+	mux.HandleFunc("POST "+Prefix+"/login", s.handleLogin)
+	mux.HandleFunc("POST "+Prefix+"/logout", s.handleLogout)
+
+	mux.HandleFunc("GET "+Prefix+"/projects", s.requireSession(s.handleProjects))
+	mux.HandleFunc("GET "+Prefix+"/projects/{project}", s.requireSession(s.handleProject))
+	mux.HandleFunc("GET "+Prefix+"/services", s.requireSession(s.handleServices))
+	mux.HandleFunc("GET "+Prefix+"/drift", s.requireSession(s.handleDrift))
+	mux.HandleFunc("GET "+Prefix+"/audit", s.requireSession(s.handleAudit))
+	mux.HandleFunc("GET "+Prefix+"/requests", s.requireSession(s.handleRequests))
+
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/maintainers", s.requireSession(s.handleAddMaintainer))
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/maintainers/{github}/remove", s.requireSession(s.handleRemoveMaintainer))
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/maintainers/{github}", s.requireSession(s.handleUpdateMaintainer))
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/onboard/{service}", s.requireSession(s.handleOnboard))
+	mux.HandleFunc("POST "+Prefix+"/requests/{id}/{decision}", s.requireSession(s.handleReviewRequest))
+}
+
+// Handler returns an http.Handler serving only the UI.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.Register(mux)
+	return mux
+}
+
+type officerKey struct{}
+
+// session is what every page needs to know about the officer viewing it.
+type session struct {
+	Officer *model.FoundationOfficer
+	CSRF    string
+}
+
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(officerKey{}).(*session)
+	return sess
+}
+
+// csrfToken returns the value forms must send back with a session's ID, so that other sites cannot post them.
+func csrfToken(id string) string {
+	return db.HashAPIToken("csrf:" + id)[:32]
+}
+
+// newSession keeps token for a new session and returns the session's ID. Expired sessions are dropped.
+func (s *Server) newSession(token string, now time.Time) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("admin: generating a session ID: %w", err)
+	}
+	id := hex.EncodeToString(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, sess := range s.sessions {
+		if !now.Before(sess.expires) {
+			delete(s.sessions, k)
+		}
+	}
+	s.sessions[id] = storedSession{token: token, expires: now.Add(sessionMaxAge)}
+	return id, nil
+}
+
+// sessionToken returns the API token of the session with id, or false when there is no such session or it expired.
+func (s *Server) sessionToken(id string, now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok || !now.Before(sess.expires) {
+		delete(s.sessions, id)
+		return "", false
+	}
+	return sess.token, true
+}
+
+func (s *Server) endSession(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// requireSession only serves h to browsers holding the cookie of a session whose API token is still usable, others are
+// sent to the login page. POSTs must carry the session's CSRF token. Changes made by h are audited against the officer's GitHub
+// account.
+func (s *Server) requireSession(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil {
+			http.Redirect(w, r, Prefix+"/login", http.StatusSeeOther)
+			return
+		}
+		token, ok := s.sessionToken(cookie.Value, time.Now())
+		if !ok {
+			clearSession(w)
+			http.Redirect(w, r, Prefix+"/login", http.StatusSeeOther)
+			return
+		}
+		officer, err := s.Store.AuthenticateAPIToken(r.Context(), token)
+		if errors.Is(err, db.ErrInvalidAPIToken) {
+			s.endSession(cookie.Value)
+			clearSession(w)
+			http.Redirect(w, r, Prefix+"/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			s.renderError(w, r, err)
+			return
+		}
+		sess := &session{Officer: officer, CSRF: csrfToken(cookie.Value)}
+		if r.Method == http.MethodPost && subtle.ConstantTimeCompare([]byte(r.PostFormValue("csrf")), []byte(sess.CSRF)) != 1 {
+			s.render(w, r, http.StatusForbidden, "error", map[string]any{"Message": "the form has expired, go back and try again"})
+			return
+		}
+		ctx := context.WithValue(r.Context(), officerKey{}, sess)
+		ctx = db.WithCorrelationID(db.WithActor(ctx, officer.GitHubAccount), db.NewCorrelationID())
+		h(w, r.WithContext(ctx))
+	}
+}
+
+func (s *Server) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	s.render(w, r, http.StatusOK, "login", nil)
+}
+
+// handleLogin checks the API token typed into the login page and starts a session with it.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(r.PostFormValue("token"))
+	officer, err := s.Store.AuthenticateAPIToken(r.Context(), token)
+	if errors.Is(err, db.ErrInvalidAPIToken) {
+		s.render(w, r, http.StatusUnauthorized, "login", map[string]any{"Message": "That token is unknown, revoked or expired."})
+		return
+	}
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	id, err := s.newSession(token, time.Now())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     Prefix,
+		MaxAge:   int(sessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteStrictMode,
+	})
+	log.Printf("admin: INF, %s signed in", officer.GitHubAccount)
+	http.Redirect(w, r, Prefix+"/projects", http.StatusSeeOther)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil {
+		s.endSession(cookie.Value)
+	}
+	clearSession(w)
+	http.Redirect(w, r, Prefix+"/login", http.StatusSeeOther)
+}
+
+func clearSession(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: Prefix, MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteStrictMode})
+}
+
+// render writes the named page with data, which is made available as .Data next to the viewer's .Session. Forms on
+// the page find the session's CSRF token in .CSRF of data.
+func (s *Server) render(w http.ResponseWriter, r *http.Request, status int, name string, data map[string]any) {
+	sess := sessionFrom(r.Context())
+	if sess != nil {
+		if data == nil {
+			data = map[string]any{}
+		}
+		data["CSRF"] = sess.CSRF
+	}
+	var buf bytes.Buffer
+	err := s.pages[name].ExecuteTemplate(&buf, "layout.html", map[string]any{
+		"Session": sess,
+		"Message": messages[r.URL.Query().Get("msg")],
+		"Data":    data,
+	})
+	if err != nil {
+		log.Printf("admin: ERR, rendering %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("admin: WRN, failed to write response: %v", err)
+	}
+}
+
+// renderError answers a request whose Store query failed, not found errors are the officer's, others are logged.
+func (s *Server) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status, message := http.StatusInternalServerError, "internal error"
+	switch {
+	case errors.Is(err, db.ErrProjectNotFound), errors.Is(err, db.ErrMaintainerNotFound), errors.Is(err, db.ErrNotProjectMaintainer):
+		status, message = http.StatusNotFound, err.Error()
+	default:
+		log.Printf("admin: ERR, %s %s: %v", r.Method, r.URL.Path, err)
+	}
+	s.render(w, r, status, "error", map[string]any{"Message": message})
+}
+
+// messages are shown at the top of a page by their code in its msg parameter. Only these fixed texts are shown, so
+// that a link from elsewhere cannot put words in the UI's mouth.
+var messages = map[string]string{
+	"github-required":        "A GitHub handle is required to add a maintainer.",
+	"maintainer-added":       "The maintainer was added.",
+	"maintainer-removed":     "The maintainer was removed from the project.",
+	"maintainer-updated":     "The maintainer was updated.",
+	"invalid-status":         "The status must be one of Active, Emeritus or Retired.",
+	"unsupported-service":    "This maintainerd cannot onboard projects to that service.",
+	"onboarding-unavailable": "Service access cannot be approved here, this maintainerd does not onboard projects.",
+	"request-target-missing": "The request is for a project or service that no longer exists, reject it instead.",
+	"request-not-found":      "The request was not found.",
+	"request-reviewed":       "The request was already reviewed.",
+	"email-in-use":           "The requested email address belongs to another maintainer, reject the request instead.",
+	"request-approved":       "The request was approved.",
+	"request-rejected":       "The request was rejected.",
+}
+
+// redirect sends the browser back to path after a change, with the message of code shown at the top of the page.
+func redirect(w http.ResponseWriter, r *http.Request, path, code string) {
+	http.Redirect(w, r, fmt.Sprintf("%s%s?msg=%s", Prefix, path, template.URLQueryEscaper(code)), http.StatusSeeOther)
+}
@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "mdt_test"
+
+type fakeTeams map[int][]string
+
+func (f fakeTeams) TeamMemberEmails(ctx context.Context, service string, teamID int) ([]string, error) {
+	return f[teamID], nil
+}
+
+type fakeOnboarder struct{}
+
+func (fakeOnboarder) Onboard(ctx context.Context, project model.Project, service string) ([]string, error) {
+	return []string{"invited @janedoe to " + service}, nil
+}
+
+// browser is a client holding cookies, like the officer's browser.
+type browser struct {
+	t      *testing.T
+	srv    *httptest.Server
+	client *http.Client
+}
+
+func newBrowser(t *testing.T) (*browser, *db.MemoryStore) {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	store.AddAPIToken(model.FoundationOfficer{Name: "Octo Cat", GitHubAccount: "octocat"}, testToken, nil)
+
+	server := NewServer(store)
+	server.Onboarder = fakeOnboarder{}
+	server.Teams = fakeTeams{42: {"jane@example.org", "stranger@example.org"}}
+	srv := httptest.NewServer(server.Handler())
+	t.Cleanup(srv.Close)
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	return &browser{t: t, srv: srv, client: &http.Client{Jar: jar}}, store
+}
+
+// get fetches path, following redirects, and returns the final status, path and page.
+func (b *browser) get(path string) (int, string, string) {
+	b.t.Helper()
+	resp, err := b.client.Get(b.srv.URL + Prefix + path)
+	require.NoError(b.t, err)
+	return b.read(resp)
+}
+
+// post submits form to path, following redirects, and returns the final status, path and page.
+func (b *browser) post(path string, form url.Values) (int, string, string) {
+	b.t.Helper()
+	resp, err := b.client.PostForm(b.srv.URL+Prefix+path, form)
+	require.NoError(b.t, err)
+	return b.read(resp)
+}
+
+func (b *browser) read(resp *http.Response) (int, string, string) {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(b.t, err)
+	return resp.StatusCode, resp.Request.URL.Path, string(body)
+}
+
+var csrfField = regexp.MustCompile(`name="csrf" value="([0-9a-f]+)"`)
+
+func (b *browser) login() string {
+	b.t.Helper()
+	status, path, page := b.post("/login", url.Values{"token": {testToken}})
+	require.Equal(b.t, http.StatusOK, status)
+	require.Equal(b.t, Prefix+"/projects", path)
+	require.Contains(b.t, page, "@octocat")
+
+	_, _, page = b.get("/projects/Kubernetes")
+	match := csrfField.FindStringSubmatch(page)
+	require.NotNil(b.t, match)
+	return match[1]
+}
+
+func TestLogin(t *testing.T) {
+	b, _ := newBrowser(t)
+
+	status, path, _ := b.get("/projects")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, Prefix+"/login", path)
+
+	status, _, page := b.post("/login", url.Values{"token": {"mdt_nope"}})
+	require.Equal(t, http.StatusUnauthorized, status)
+	require.Contains(t, page, "unknown, revoked or expired")
+
+	b.login()
+	site, err := url.Parse(b.srv.URL + Prefix)
+	require.NoError(t, err)
+	cookies := b.client.Jar.Cookies(site)
+	require.Len(t, cookies, 1)
+	require.NotContains(t, cookies[0].Value, testToken, "the cookie holds a session ID, not the API token")
+
+	status, path, _ = b.post("/logout", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, Prefix+"/login", path)
+	_, path, _ = b.get("/projects")
+	require.Equal(t, Prefix+"/login", path)
+
+	// the session ended with the logout, a copy of its cookie no longer signs in
+	b.client.Jar.SetCookies(site, cookies)
+	_, path, _ = b.get("/projects")
+	require.Equal(t, Prefix+"/login", path)
+}
+
+func TestMessages(t *testing.T) {
+	b, _ := newBrowser(t)
+	b.login()
+
+	_, _, page := b.get("/requests?msg=request-approved")
+	require.Contains(t, page, `<div class="flash">The request was approved.</div>`)
+	_, _, page = b.get("/requests?msg=" + url.QueryEscape("Your token expired, paste it at evil.example.org"))
+	require.NotContains(t, page, "evil.example.org")
+	require.NotContains(t, page, `class="flash"`)
+}
+
+func TestBrowse(t *testing.T) {
+	b, _ := newBrowser(t)
+	b.login()
+
+	status, _, page := b.get("/")
+	require.Equal(t, http.StatusOK, status)
+	require.Regexp(t, `(?s)Kubernetes.*└ .*kubectl`, page)
+
+	status, _, page = b.get("/projects/kubernetes")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, page, "@janedoe")
+	require.Contains(t, page, "<title>Kubernetes · maintainerd</title>")
+	require.Contains(t, page, `Subprojects: <a href="/admin/ui/projects/kubectl">`)
+	require.Contains(t, page, "#42")
+	require.Contains(t, page, "Onboard to FOSSA")
+	status, _, _ = b.get("/projects/Nope")
+	require.Equal(t, http.StatusNotFound, status)
+
+	_, _, page = b.get("/services")
+	require.Contains(t, page, "1/3")
+
+	_, _, page = b.get("/drift")
+	require.Contains(t, page, "John Roe &lt;john@example.org&gt;")
+	require.Contains(t, page, "stranger@example.org")
+	require.NotContains(t, page, "Jane Doe &lt;")
+
+	status, _, page = b.get("/audit?project=Nope")
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Contains(t, page, "not found")
+}
+
+func TestEditRoster(t *testing.T) {
+	b, store := newBrowser(t)
+	csrf := b.login()
+
+	status, _, _ := b.post("/projects/Jaeger/maintainers", url.Values{"github": {"octo"}})
+	require.Equal(t, http.StatusForbidden, status)
+
+	status, path, page := b.post("/projects/Jaeger/maintainers", url.Values{
+		"csrf": {csrf}, "name": {"Octo"}, "github": {"@octo"}, "email": {"octo@example.org"}, "company": {"GitHub"},
+	})
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, Prefix+"/projects/Jaeger", path)
+	require.Contains(t, page, "The maintainer was added.")
+	require.Contains(t, page, `value="GitHub"`)
+
+	_, _, page = b.post("/projects/Jaeger/maintainers/octo", url.Values{"csrf": {csrf}, "status": {"Emeritus"}, "company": {"GitHub"}})
+	require.Contains(t, page, "The maintainer was updated.")
+	octo, err := store.GetMaintainerByGitHubAccount(context.Background(), "octo")
+	require.NoError(t, err)
+	require.Equal(t, model.EmeritusMaintainer, octo.MaintainerStatus)
+
+	_, _, page = b.post("/projects/Jaeger/maintainers/octo/remove", url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "The maintainer was removed from the project.")
+	status, _, _ = b.post("/projects/Jaeger/maintainers/octo/remove", url.Values{"csrf": {csrf}})
+	require.Equal(t, http.StatusNotFound, status)
+
+	entries, err := store.ListAuditLogs(context.Background(), db.AuditFilter{Actor: "octocat"})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	_, _, page = b.get("/audit?actor=octocat")
+	require.Contains(t, page, "DELETE_MAINTAINER_PROJECTS")
+
+	status, _, page = b.post("/projects/Jaeger/onboard/FOSSA", url.Values{"csrf": {csrf}})
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, page, "invited @janedoe to FOSSA")
+}
+
+func TestReviewRequests(t *testing.T) {
+	b, store := newBrowser(t)
+	csrf := b.login()
+
+	ctx := db.WithActor(context.Background(), "ada")
+	ada, err := store.GetMaintainerByGitHubAccount(ctx, "ada")
+	require.NoError(t, err)
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	fossa, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+	jaeger := projects["Jaeger"].ID
+	email, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: ada.ID, Kind: model.ChangeEmail, Value: "ada@example.org"})
+	require.NoError(t, err)
+	company, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: ada.ID, Kind: model.ChangeCompany, Value: "Analytical Engines"})
+	require.NoError(t, err)
+	access, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: ada.ID, Kind: model.ChangeServiceAccess, ProjectID: &jaeger, ServiceID: &fossa.ID})
+	require.NoError(t, err)
+
+	_, _, page := b.get("/requests")
+	require.Contains(t, page, "to <strong>ada@example.org</strong>")
+	require.Contains(t, page, "Approve and onboard")
+
+	path := func(id uint, decision string) string {
+		return "/requests/" + strconv.FormatUint(uint64(id), 10) + "/" + decision
+	}
+	taken, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: ada.ID, Kind: model.ChangeEmail, Value: "jane@example.org"})
+	require.NoError(t, err)
+	_, _, page = b.post(path(taken.ID, "approve"), url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "belongs to another maintainer")
+	_, _, page = b.post(path(taken.ID, "reject"), url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "was rejected.")
+
+	_, _, page = b.post(path(email.ID, "approve"), url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "was approved.")
+	_, _, page = b.post(path(email.ID, "reject"), url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "already reviewed")
+	_, _, page = b.post(path(company.ID, "reject"), url.Values{"csrf": {csrf}, "note": {"use your employer's legal name"}})
+	require.Contains(t, page, "was rejected.")
+	_, _, page = b.post(path(access.ID, "approve"), url.Values{"csrf": {csrf}})
+	require.Contains(t, page, "was approved.")
+
+	ada, err = store.GetMaintainerByGitHubAccount(ctx, "ada")
+	require.NoError(t, err)
+	require.Equal(t, "ada@example.org", ada.Email)
+	reviewed, err := store.ListChangeRequests(ctx, db.ChangeRequestFilter{Status: model.ChangeApproved})
+	require.NoError(t, err)
+	require.Len(t, reviewed, 2)
+	require.Equal(t, "octocat", reviewed[0].ReviewedBy)
+	require.Equal(t, "invited @janedoe to FOSSA", reviewed[0].Note)
+
+	_, _, page = b.get("/requests?status=rejected")
+	require.Contains(t, page, "use your employer&#39;s legal name")
+}
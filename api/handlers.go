@@ -0,0 +1,266 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// handleListProjects lists projects ordered by name. They can be filtered with the maturity (comma separated),
+// parent (a project name), top_level=true, service (a service name) and q (part of the name, any case) query
+// parameters.
+func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+	projects, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	var usingService map[uint]bool
+	if name := params.Get("service"); name != "" {
+		svc, err := s.Store.GetServiceByName(ctx, name)
+		if err != nil || svc == nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown service %q", name))
+			return
+		}
+		using, err := s.Store.GetProjectsUsingService(ctx, svc.ID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		usingService = make(map[uint]bool, len(using))
+		for _, p := range using {
+			usingService[p.ID] = true
+		}
+	}
+	var maturities []string
+	if v := params.Get("maturity"); v != "" {
+		for _, m := range strings.Split(v, ",") {
+			maturities = append(maturities, strings.TrimSpace(m))
+		}
+	}
+	parent, topLevel, q := params.Get("parent"), params.Get("top_level") == "true", strings.ToLower(params.Get("q"))
+
+	index := newProjectIndex(projects)
+	items := []v1.Project{}
+	for _, p := range index.sorted() {
+		switch {
+		case len(maturities) > 0 && !p.Maturity.In(maturities):
+			continue
+		case parent != "" && index.parentName(p) != parent:
+			continue
+		case topLevel && p.ParentProjectID != nil:
+			continue
+		case usingService != nil && !usingService[p.ID]:
+			continue
+		case q != "" && !strings.Contains(strings.ToLower(p.Name), q):
+			continue
+		}
+		items = append(items, index.wire(p))
+	}
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// handleGetProject serves a project by name.
+func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request) {
+	index, project, err := s.project(r.Context(), r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, index.wire(project))
+}
+
+// handleProjectMaintainers lists a project's maintainers ordered by name. rollup=true adds the maintainers of its
+// subprojects and they can be filtered with the status and company query parameters.
+func (s *Server) handleProjectMaintainers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := r.URL.Query()
+	_, project, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	var maintainers []model.Maintainer
+	if params.Get("rollup") == "true" {
+		maintainers, err = s.Store.GetRolledUpMaintainers(ctx, project.ID)
+	} else {
+		maintainers, err = s.Store.GetMaintainersByProject(ctx, project.ID)
+	}
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	status, company := params.Get("status"), params.Get("company")
+	items := []v1.Maintainer{}
+	for _, m := range maintainers {
+		wire := wireMaintainer(m)
+		if (status != "" && !strings.EqualFold(wire.Status, status)) || (company != "" && !strings.EqualFold(wire.Company, company)) {
+			continue
+		}
+		items = append(items, wire)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].GitHub < items[j].GitHub
+	})
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// handleProjectServices lists the teams that hold a project's maintainers on each service.
+func (s *Server) handleProjectServices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, project, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	services, err := s.Store.ListServices(ctx)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	items := []v1.ServiceTeam{}
+	for _, svc := range services {
+		st, err := s.Store.GetServiceTeamByProject(ctx, project.ID, svc.ID)
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		if st == nil {
+			continue
+		}
+		team := v1.ServiceTeam{Service: svc.Name, Project: project.Name, TeamID: st.ServiceTeamID}
+		if st.ServiceTeamName != nil {
+			team.TeamName = *st.ServiceTeamName
+		}
+		items = append(items, team)
+	}
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// handleGetMaintainer serves a maintainer, and the projects they maintain, by GitHub handle.
+func (s *Server) handleGetMaintainer(w http.ResponseWriter, r *http.Request) {
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(r.Context(), r.PathValue("github"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wireMaintainer(*maintainer))
+}
+
+// handleListServices lists services ordered by name.
+func (s *Server) handleListServices(w http.ResponseWriter, r *http.Request) {
+	services, err := s.Store.ListServices(r.Context())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	items := make([]v1.Service, len(services))
+	for i, svc := range services {
+		items[i] = v1.Service{Name: svc.Name, Description: svc.Description}
+	}
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// project returns the project called name, matched exactly or else ignoring case, and an index of every project.
+func (s *Server) project(ctx context.Context, name string) (*projectIndex, model.Project, error) {
+	projects, err := s.Store.GetProjectMapByName(ctx)
+	if err != nil {
+		return nil, model.Project{}, err
+	}
+	index := newProjectIndex(projects)
+	if p, ok := projects[name]; ok {
+		return index, p, nil
+	}
+	for _, p := range index.sorted() {
+		if strings.EqualFold(p.Name, name) {
+			return index, p, nil
+		}
+	}
+	return nil, model.Project{}, fmt.Errorf("%w: %s", db.ErrProjectNotFound, name)
+}
+
+// projectIndex resolves the parent and subprojects of projects.
+type projectIndex struct {
+	byName      map[string]model.Project
+	byID        map[uint]model.Project
+	subprojects map[uint][]string
+}
+
+func newProjectIndex(byName map[string]model.Project) *projectIndex {
+	index := &projectIndex{byName: byName, byID: make(map[uint]model.Project, len(byName)), subprojects: map[uint][]string{}}
+	for _, p := range byName {
+		index.byID[p.ID] = p
+		if p.ParentProjectID != nil {
+			index.subprojects[*p.ParentProjectID] = append(index.subprojects[*p.ParentProjectID], p.Name)
+		}
+	}
+	for _, names := range index.subprojects {
+		sort.Strings(names)
+	}
+	return index
+}
+
+func (x *projectIndex) sorted() []model.Project {
+	projects := make([]model.Project, 0, len(x.byName))
+	for _, p := range x.byName {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
+
+func (x *projectIndex) parentName(p model.Project) string {
+	if p.ParentProjectID == nil {
+		return ""
+	}
+	return x.byID[*p.ParentProjectID].Name
+}
+
+func (x *projectIndex) wire(p model.Project) v1.Project {
+	wire := v1.Project{
+		Name:          p.Name,
+		Maturity:      string(p.Maturity),
+		ParentProject: x.parentName(p),
+		Subprojects:   x.subprojects[p.ID],
+		MaintainerRef: p.MaintainerRef,
+	}
+	if p.MailingList != nil && !db.MissingValue(*p.MailingList) {
+		wire.MailingList = *p.MailingList
+	}
+	return wire
+}
+
+func wireMaintainer(m model.Maintainer) v1.Maintainer {
+	wire := v1.Maintainer{Name: m.Name, Company: m.Company.Name, Status: string(m.MaintainerStatus)}
+	if !db.MissingValue(m.GitHubAccount) {
+		wire.GitHub = m.GitHubAccount
+	}
+	if wire.Status == "" {
+		wire.Status = string(model.ActiveMaintainer)
+	}
+	for _, p := range m.Projects {
+		wire.Projects = append(wire.Projects, p.Name)
+	}
+	return wire
+}
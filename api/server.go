@@ -0,0 +1,116 @@
+// Package api serves the maintainerd registry as versioned JSON under /api/v1 so that other CNCF tools can consume
+// it. Reads are open, writes need a foundation officer's API token and are audited. Every endpoint is answered from a
+// db.Store.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Prefix is the path under which version 1 of the API is served.
+	Prefix = "/api/v1"
+
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Server answers API requests from Store.
+type Server struct {
+	Store     db.Store
+	Onboarder Onboarder // onboards projects to services, the onboard endpoint answers 501 when nil
+	Logger    *zap.SugaredLogger
+}
+
+// NewServer returns a Server reading from and writing to store.
+func NewServer(store db.Store) *Server {
+	return &Server{Store: store, Logger: zap.NewNop().Sugar()}
+}
+
+// Mux is where Register mounts the API's endpoints, such as an *http.ServeMux.
+type Mux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Register mounts the API's endpoints on mux.
+func (s *Server) Register(mux Mux) {
+	mux.HandleFunc("GET "+Prefix+"/projects", s.handleListProjects)
+	mux.HandleFunc("GET "+Prefix+"/projects/{project}", s.handleGetProject)
+	mux.HandleFunc("GET "+Prefix+"/projects/{project}/maintainers", s.handleProjectMaintainers)
+	mux.HandleFunc("GET "+Prefix+"/projects/{project}/services", s.handleProjectServices)
+	mux.HandleFunc("GET "+Prefix+"/maintainers/{github}", s.handleGetMaintainer)
+	mux.HandleFunc("GET "+Prefix+"/services", s.handleListServices)
+	mux.HandleFunc("GET "+Prefix+"/openapi.yaml", handleOpenAPI)
+	s.registerWrites(mux)
+	s.registerWebhooks(mux)
+}
+
+// Handler returns an http.Handler serving only the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.Register(mux)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("api: failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, v1.Error{Error: message})
+}
+
+// writeStoreError answers a request whose Store query failed, not found errors are the client's, others are logged.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, db.ErrProjectNotFound) || errors.Is(err, db.ErrMaintainerNotFound) || errors.Is(err, db.ErrNotProjectMaintainer) ||
+		errors.Is(err, db.ErrWebhookSubscriptionNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	log.Printf("api: %s %s: %v", r.Method, r.URL.Path, err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
+
+// page reads the limit and offset query parameters of r.
+func page(r *http.Request) (limit, offset int, err error) {
+	limit, offset = defaultLimit, 0
+	params := r.URL.Query()
+	if v := params.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxLimit {
+			return 0, 0, errors.New("limit must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+	}
+	if v := params.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// paginate returns the page of items chosen by the limit and offset of r.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) (v1.List[T], bool) {
+	limit, offset, err := page(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return v1.List[T]{}, false
+	}
+	list := v1.List[T]{Items: []T{}, Total: len(items), Limit: limit, Offset: offset}
+	if offset < len(items) {
+		list.Items = items[offset:min(offset+limit, len(items))]
+	}
+	return list, true
+}
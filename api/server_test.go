@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	srv := httptest.NewServer(NewServer(store).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// get fetches path from srv, checks the status and decodes the body into out.
+func get(t *testing.T, srv *httptest.Server, path string, status int, out any) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, status, resp.StatusCode, path)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+}
+
+func TestListProjects(t *testing.T) {
+	srv := newTestServer(t)
+
+	var all v1.List[v1.Project]
+	get(t, srv, "/api/v1/projects", http.StatusOK, &all)
+	require.Equal(t, 3, all.Total)
+	require.Equal(t, []string{"Jaeger", "Kubernetes", "kubectl"}, projectNames(all.Items))
+	require.Equal(t, []string{"kubectl"}, all.Items[1].Subprojects)
+	require.Equal(t, "Kubernetes", all.Items[2].ParentProject)
+
+	var paged v1.List[v1.Project]
+	get(t, srv, "/api/v1/projects?limit=1&offset=1", http.StatusOK, &paged)
+	require.Equal(t, 3, paged.Total)
+	require.Equal(t, []string{"Kubernetes"}, projectNames(paged.Items))
+
+	var filtered v1.List[v1.Project]
+	get(t, srv, "/api/v1/projects?service=FOSSA&maturity=graduated", http.StatusOK, &filtered)
+	require.Equal(t, []string{"Kubernetes"}, projectNames(filtered.Items))
+	get(t, srv, "/api/v1/projects?top_level=true&q=jae", http.StatusOK, &filtered)
+	require.Equal(t, []string{"Jaeger"}, projectNames(filtered.Items))
+	get(t, srv, "/api/v1/projects?parent=Kubernetes", http.StatusOK, &filtered)
+	require.Equal(t, []string{"kubectl"}, projectNames(filtered.Items))
+
+	var apiErr v1.Error
+	get(t, srv, "/api/v1/projects?limit=0", http.StatusBadRequest, &apiErr)
+	get(t, srv, "/api/v1/projects?service=Nope", http.StatusBadRequest, &apiErr)
+	require.Contains(t, apiErr.Error, "Nope")
+}
+
+func TestProjectMaintainersAndServices(t *testing.T) {
+	srv := newTestServer(t)
+
+	var project v1.Project
+	get(t, srv, "/api/v1/projects/kubernetes", http.StatusOK, &project)
+	require.Equal(t, "Kubernetes", project.Name)
+	var apiErr v1.Error
+	get(t, srv, "/api/v1/projects/Nope", http.StatusNotFound, &apiErr)
+
+	var maintainers v1.List[v1.Maintainer]
+	get(t, srv, "/api/v1/projects/Kubernetes/maintainers", http.StatusOK, &maintainers)
+	require.Equal(t, 1, maintainers.Total)
+	require.Equal(t, "janedoe", maintainers.Items[0].GitHub)
+	require.Equal(t, "Example Inc", maintainers.Items[0].Company)
+	get(t, srv, "/api/v1/projects/Kubernetes/maintainers?rollup=true&company=example%20inc", http.StatusOK, &maintainers)
+	require.Equal(t, 2, maintainers.Total)
+	get(t, srv, "/api/v1/projects/Kubernetes/maintainers?rollup=true&status=Emeritus", http.StatusOK, &maintainers)
+	require.Zero(t, maintainers.Total)
+
+	var teams v1.List[v1.ServiceTeam]
+	get(t, srv, "/api/v1/projects/Kubernetes/services", http.StatusOK, &teams)
+	require.Len(t, teams.Items, 1)
+	require.Equal(t, v1.ServiceTeam{Service: "FOSSA", Project: "Kubernetes", TeamID: 42, TeamName: teams.Items[0].TeamName}, teams.Items[0])
+
+	var services v1.List[v1.Service]
+	get(t, srv, "/api/v1/services", http.StatusOK, &services)
+	require.Equal(t, "FOSSA", services.Items[0].Name)
+}
+
+func TestGetMaintainer(t *testing.T) {
+	srv := newTestServer(t)
+
+	var jane v1.Maintainer
+	get(t, srv, "/api/v1/maintainers/JaneDoe", http.StatusOK, &jane)
+	require.Equal(t, "janedoe", jane.GitHub)
+	require.Equal(t, []string{"Kubernetes"}, jane.Projects)
+	require.Equal(t, "Active", jane.Status)
+
+	var apiErr v1.Error
+	get(t, srv, "/api/v1/maintainers/nobody", http.StatusNotFound, &apiErr)
+
+	resp, err := http.Post(srv.URL+"/api/v1/services", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func projectNames(projects []v1.Project) []string {
+	names := make([]string, len(projects))
+	for i, p := range projects {
+		names[i] = p.Name
+	}
+	return names
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	_ "embed"
+	"log"
+	"net/http"
+)
+
+// OpenAPI is the OpenAPI document describing the API, served at /api/v1/openapi.yaml. Change it with the handlers.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(OpenAPI); err != nil {
+		log.Printf("api: failed to write response: %v", err)
+	}
+}
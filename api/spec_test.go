@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestOpenAPIMatchesRoutes checks that every operation in the OpenAPI document is served by the handler registered
+// for exactly that path and method.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(OpenAPI, &spec))
+	require.NotEmpty(t, spec.Paths)
+
+	mux := http.NewServeMux()
+	NewServer(nil).Register(mux)
+	operations := 0
+	for path, item := range spec.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			method = strings.ToUpper(method)
+			url := strings.NewReplacer("{project}", "Kubernetes", "{github}", "janedoe", "{service}", "FOSSA", "{id}", "1").Replace(path)
+			_, pattern := mux.Handler(httptest.NewRequest(method, Prefix+url, nil))
+			require.Equal(t, method+" "+Prefix+path, pattern, "%s %s", method, path)
+			operations++
+		}
+	}
+	require.Equal(t, 15, operations)
+
+	// and every route is in the spec
+	routes := routeRecorder{}
+	NewServer(nil).Register(routes)
+	require.Len(t, routes, operations)
+	for pattern := range routes {
+		method, path, _ := strings.Cut(pattern, " ")
+		item, ok := spec.Paths[strings.TrimPrefix(path, Prefix)]
+		require.True(t, ok, "%s is not in the spec", pattern)
+		require.Contains(t, item, strings.ToLower(method), "%s is not in the spec", pattern)
+	}
+}
+
+// routeRecorder collects the patterns registered on it.
+type routeRecorder map[string]bool
+
+func (r routeRecorder) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r[pattern] = true
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	srv := newTestServer(t)
+	resp, err := http.Get(srv.URL + "/api/v1/openapi.yaml")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+}
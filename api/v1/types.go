@@ -0,0 +1,134 @@
+// Package v1 holds the JSON types served under /api/v1. Fields are only ever added to them, so that clients built
+// against one maintainerd keep working with the next.
+package v1
+
+import "time"
+
+// Headers of the requests that deliver a Notification to a webhook subscription. The signature is "sha256=" followed
+// by the hex HMAC-SHA256 of the body keyed with the subscription's secret, as GitHub signs X-Hub-Signature-256.
+const (
+	SignatureHeader = "X-Maintainerd-Signature-256"
+	EventHeader     = "X-Maintainerd-Event"
+	DeliveryHeader  = "X-Maintainerd-Delivery"
+)
+
+// List is a page of a collection. Total counts every item that matched the request's filters, Limit and Offset are
+// those used for the page.
+type List[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// Project is a CNCF project or a subproject of one.
+type Project struct {
+	Name          string   `json:"name"`
+	Maturity      string   `json:"maturity"`
+	ParentProject string   `json:"parent_project,omitempty"`
+	Subprojects   []string `json:"subprojects,omitempty"`
+	MaintainerRef string   `json:"maintainer_ref,omitempty"` // link to the project's OWNERS or MAINTAINERS file
+	MailingList   string   `json:"mailing_list,omitempty"`
+}
+
+// Maintainer is a maintainer of one or more projects. Email addresses are not served.
+type Maintainer struct {
+	Name     string   `json:"name"`
+	GitHub   string   `json:"github,omitempty"`
+	Company  string   `json:"company,omitempty"`
+	Status   string   `json:"status"`
+	Projects []string `json:"projects,omitempty"`
+}
+
+// Service is a service, such as FOSSA, that maintainers are onboarded to.
+type Service struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ServiceTeam is the team that holds a project's maintainers on a service.
+type ServiceTeam struct {
+	Service  string `json:"service"`
+	Project  string `json:"project"`
+	TeamID   int    `json:"team_id"` // the team's id on the service
+	TeamName string `json:"team_name,omitempty"`
+}
+
+// Error is the body of every response with a 4xx or 5xx status.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// AddMaintainer is the body of a request adding a maintainer to a project, GitHub is required. A maintainer already
+// registered with the GitHub account, or else the email, is added rather than a new one.
+type AddMaintainer struct {
+	Name    string `json:"name"`
+	GitHub  string `json:"github"`
+	Email   string `json:"email"` // needed for service invitations, it is never served
+	Company string `json:"company,omitempty"`
+}
+
+// UpdateMaintainer is the body of a request changing a maintainer, fields left out are not changed.
+type UpdateMaintainer struct {
+	Status  *string `json:"status,omitempty"`
+	Company *string `json:"company,omitempty"`
+}
+
+// Onboarding is the outcome of onboarding a project's maintainers to a service. Actions describe what was done, Error
+// is set when onboarding did not complete.
+type Onboarding struct {
+	Project string   `json:"project"`
+	Service string   `json:"service"`
+	Actions []string `json:"actions"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// CreateWebhookSubscription is the body of a request registering a webhook subscription. A secret is generated when
+// Secret is left out.
+type CreateWebhookSubscription struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// WebhookSubscription is an endpoint that is sent Notifications of its events. Secret is only served when the
+// subscription is created.
+type WebhookSubscription struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is a Notification sent, or still to be sent, to a webhook subscription.
+type WebhookDelivery struct {
+	ID             uint       `json:"id"`
+	Event          string     `json:"event"`
+	Notification   uint       `json:"notification"` // the id of the Notification delivered
+	Status         string     `json:"status"`       // pending, delivered or failed
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// Notification is the body POSTed to a webhook subscription when one of its events happens. ID is the same for
+// every subscription sent the change, and for every attempt to send it. From and To are the old and new status or
+// maturity.
+type Notification struct {
+	ID             uint      `json:"id"`
+	Event          string    `json:"event"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	Actor          string    `json:"actor"`
+	CorrelationID  string    `json:"correlation_id,omitempty"`
+	Project        string    `json:"project,omitempty"`
+	Maintainer     string    `json:"maintainer,omitempty"` // GitHub handle
+	MaintainerName string    `json:"maintainer_name,omitempty"`
+	Service        string    `json:"service,omitempty"`
+	From           string    `json:"from,omitempty"`
+	To             string    `json:"to,omitempty"`
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// registerWebhooks mounts the endpoints that manage webhook subscriptions, each needs a foundation officer's API
+// token as subscriptions hold the endpoints of other systems.
+func (s *Server) registerWebhooks(mux Mux) {
+	mux.HandleFunc("POST "+Prefix+"/webhooks", s.requireOfficer(s.handleCreateWebhook))
+	mux.HandleFunc("GET "+Prefix+"/webhooks", s.requireOfficer(s.handleListWebhooks))
+	mux.HandleFunc("DELETE "+Prefix+"/webhooks/{id}", s.requireOfficer(s.handleDeleteWebhook))
+	mux.HandleFunc("GET "+Prefix+"/webhooks/{id}/deliveries", s.requireOfficer(s.handleListDeliveries))
+}
+
+// handleCreateWebhook registers a webhook subscription, answering with its secret.
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req v1.CreateWebhookSubscription
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		req.Secret = secret
+	}
+	sub, err := s.Store.CreateWebhookSubscription(r.Context(), model.WebhookSubscription{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: strings.Join(req.Events, ","),
+	})
+	if errors.Is(err, db.ErrInvalidWebhookSubscription) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	created := wireWebhook(*sub)
+	created.Secret = sub.Secret
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// handleListWebhooks lists the webhook subscriptions, oldest first.
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := s.Store.ListWebhookSubscriptions(r.Context())
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	items := make([]v1.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		items = append(items, wireWebhook(sub))
+	}
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// handleDeleteWebhook deletes a webhook subscription, its pending deliveries are not sent.
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Store.DeleteWebhookSubscription(r.Context(), id); err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListDeliveries lists a webhook subscription's deliveries, newest first.
+func (s *Server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+	subs, err := s.Store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	found := false
+	for _, sub := range subs {
+		found = found || sub.ID == id
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, db.ErrWebhookSubscriptionNotFound.Error())
+		return
+	}
+	deliveries, err := s.Store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{SubscriptionID: &id})
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	items := make([]v1.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		items = append(items, v1.WebhookDelivery{
+			ID:             d.ID,
+			Event:          d.Event,
+			Notification:   d.AuditLogID,
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt.UTC(),
+			NextAttemptAt:  d.NextAttemptAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+	if list, ok := paginate(w, r, items); ok {
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+// webhookID reads the subscription id in the path of r, answering the request itself when it is not a number.
+func webhookID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 0)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusNotFound, db.ErrWebhookSubscriptionNotFound.Error())
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// wireWebhook returns sub as served, without its secret.
+func wireWebhook(sub model.WebhookSubscription) v1.WebhookSubscription {
+	events := []string{}
+	for _, e := range sub.EventList() {
+		events = append(events, string(e))
+	}
+	return v1.WebhookSubscription{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    events,
+		CreatedBy: sub.CreatedBy,
+		CreatedAt: sub.CreatedAt.UTC(),
+	}
+}
+
+// newWebhookSecret returns a random secret for signing deliveries.
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	srv, store, _ := newWriteServer(t)
+
+	resp := send(t, srv, http.MethodGet, "/api/v1/webhooks", "", nil, nil)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var e v1.Error
+	resp = send(t, srv, http.MethodPost, "/api/v1/webhooks", testToken,
+		v1.CreateWebhookSubscription{URL: "https://example.org/hook", Events: []string{"maintainer.renamed"}}, &e)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Contains(t, e.Error, "maintainer.renamed")
+
+	var created v1.WebhookSubscription
+	resp = send(t, srv, http.MethodPost, "/api/v1/webhooks", testToken,
+		v1.CreateWebhookSubscription{URL: "https://example.org/hook", Events: []string{"maintainer.added", "project.maturity_changed"}}, &created)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Len(t, created.Secret, 64, "a secret is generated")
+	require.Equal(t, "octocat", created.CreatedBy)
+	require.Equal(t, []string{"maintainer.added", "project.maturity_changed"}, created.Events)
+
+	var chosen v1.WebhookSubscription
+	send(t, srv, http.MethodPost, "/api/v1/webhooks", testToken,
+		v1.CreateWebhookSubscription{URL: "https://example.com/hook", Events: []string{"maintainer.removed"}, Secret: "s3cret"}, &chosen)
+	require.Equal(t, "s3cret", chosen.Secret)
+
+	var list v1.List[v1.WebhookSubscription]
+	resp = send(t, srv, http.MethodGet, "/api/v1/webhooks", testToken, nil, &list)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, list.Total)
+	require.Equal(t, created.ID, list.Items[0].ID)
+	require.Empty(t, list.Items[0].Secret, "secrets are only served on creation")
+
+	require.NoError(t, store.EnqueueWebhookDeliveries(context.Background(), 0, []model.WebhookDelivery{
+		{SubscriptionID: created.ID, AuditLogID: 7, Event: "maintainer.added", Payload: "{}"},
+	}))
+	var deliveries v1.List[v1.WebhookDelivery]
+	resp = send(t, srv, http.MethodGet, webhookPath(created.ID)+"/deliveries", testToken, nil, &deliveries)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, deliveries.Items, 1)
+	require.Equal(t, uint(7), deliveries.Items[0].Notification)
+	require.Equal(t, "pending", deliveries.Items[0].Status)
+
+	resp = send(t, srv, http.MethodDelete, webhookPath(created.ID), testToken, nil, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.NotEmpty(t, resp.Header.Get("X-Correlation-ID"))
+	for _, path := range []string{webhookPath(created.ID), "/api/v1/webhooks/nope"} {
+		resp = send(t, srv, http.MethodDelete, path, testToken, nil, nil)
+		require.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+	resp = send(t, srv, http.MethodGet, webhookPath(created.ID)+"/deliveries", testToken, nil, nil)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	failed, err := store.ListWebhookDeliveries(context.Background(), db.WebhookDeliveryFilter{Status: model.DeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+}
+
+func webhookPath(id uint) string {
+	return "/api/v1/webhooks/" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+const maxBodyBytes = 1 << 20
+
+// ErrUnsupportedService is returned by an Onboarder asked to onboard to a service it does not know.
+var ErrUnsupportedService = errors.New("service onboarding is not supported")
+
+// Onboarder onboards the maintainers of a project to a service, returning the actions taken. It is implemented by the
+// onboarding server.
+type Onboarder interface {
+	Onboard(ctx context.Context, project model.Project, service string) ([]string, error)
+}
+
+// registerWrites mounts the endpoints that change the registry, each needs a foundation officer's API token.
+func (s *Server) registerWrites(mux Mux) {
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/maintainers", s.requireOfficer(s.handleAddMaintainer))
+	mux.HandleFunc("DELETE "+Prefix+"/projects/{project}/maintainers/{github}", s.requireOfficer(s.handleRemoveMaintainer))
+	mux.HandleFunc("PATCH "+Prefix+"/maintainers/{github}", s.requireOfficer(s.handleUpdateMaintainer))
+	mux.HandleFunc("POST "+Prefix+"/projects/{project}/services/{service}/onboard", s.requireOfficer(s.handleOnboard))
+}
+
+// requireOfficer only serves h to requests carrying a foundation officer's API token as a bearer token. Changes made
+// by h are audited against the officer's GitHub account and a correlation id returned in X-Correlation-ID.
+func (s *Server) requireOfficer(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="maintainerd"`)
+			writeError(w, http.StatusUnauthorized, "an API token is required")
+			return
+		}
+		officer, err := s.Store.AuthenticateAPIToken(r.Context(), token)
+		if errors.Is(err, db.ErrInvalidAPIToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="maintainerd", error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+		correlationID := db.NewCorrelationID()
+		w.Header().Set("X-Correlation-ID", correlationID)
+		ctx := db.WithCorrelationID(db.WithActor(r.Context(), officer.GitHubAccount), correlationID)
+		h(w, r.WithContext(ctx))
+	}
+}
+
+// handleAddMaintainer adds a maintainer to a project.
+func (s *Server) handleAddMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req v1.AddMaintainer
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.GitHub == "" {
+		writeError(w, http.StatusBadRequest, "github is required")
+		return
+	}
+	_, project, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	maintainer := model.Maintainer{Name: req.Name, GitHubAccount: req.GitHub, Email: req.Email}
+	added, err := s.Store.AddMaintainerToProject(ctx, project.ID, maintainer, req.Company)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	s.writeMaintainer(w, r, added.GitHubAccount, http.StatusCreated)
+}
+
+// handleRemoveMaintainer removes a maintainer from a project, the maintainer stays registered.
+func (s *Server) handleRemoveMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_, project, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, r.PathValue("github"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	if err := s.Store.RemoveMaintainerFromProject(ctx, project.ID, maintainer.ID); err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUpdateMaintainer changes a maintainer's status or company.
+func (s *Server) handleUpdateMaintainer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req v1.UpdateMaintainer
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Status != nil && !model.MaintainerStatus(*req.Status).IsValid() {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("status %q is not one of Active, Emeritus or Retired", *req.Status))
+		return
+	}
+	if req.Company != nil && *req.Company == "" {
+		writeError(w, http.StatusBadRequest, "company cannot be empty")
+		return
+	}
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, r.PathValue("github"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	if req.Status != nil {
+		if err := s.Store.SetMaintainerStatus(ctx, maintainer.ID, model.MaintainerStatus(*req.Status)); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+	}
+	if req.Company != nil {
+		if err := s.Store.SetMaintainerCompany(ctx, maintainer.ID, *req.Company); err != nil {
+			writeStoreError(w, r, err)
+			return
+		}
+	}
+	s.writeMaintainer(w, r, maintainer.GitHubAccount, http.StatusOK)
+}
+
+// handleOnboard onboards a project's maintainers to a service and reports the actions taken.
+func (s *Server) handleOnboard(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if s.Onboarder == nil {
+		writeError(w, http.StatusNotImplemented, "service onboarding is not available")
+		return
+	}
+	_, project, err := s.project(ctx, r.PathValue("project"))
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	service := r.PathValue("service")
+	s.auditEvent(ctx, model.AuditLog{
+		ProjectID: project.ID,
+		Action:    "ONBOARDING_REQUESTED",
+		Message:   fmt.Sprintf("onboarding of %s to %s requested", project.Name, service),
+	})
+
+	actions, err := s.Onboarder.Onboard(ctx, project, service)
+	if errors.Is(err, ErrUnsupportedService) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	result := v1.Onboarding{Project: project.Name, Service: service, Actions: actions}
+	if result.Actions == nil {
+		result.Actions = []string{}
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// writeMaintainer answers with the maintainer registered with github as it is after a write.
+func (s *Server) writeMaintainer(w http.ResponseWriter, r *http.Request, github string, status int) {
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(r.Context(), github)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, status, wireMaintainer(*maintainer))
+}
+
+// decodeBody decodes the JSON body of r into v, answering the request itself when the body is not valid.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// auditEvent records an action that is not itself a database write. Failures are logged, the action goes ahead.
+func (s *Server) auditEvent(ctx context.Context, entry model.AuditLog) {
+	if err := s.Store.LogAuditEvent(ctx, s.Logger, entry); err != nil {
+		log.Printf("api: failed to audit %s: %v", entry.Action, err)
+	}
+}
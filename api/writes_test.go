@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "mdt_test"
+
+type fakeOnboarder struct{ projects []string }
+
+func (f *fakeOnboarder) Onboard(ctx context.Context, project model.Project, service string) ([]string, error) {
+	if service != "FOSSA" {
+		return nil, ErrUnsupportedService
+	}
+	f.projects = append(f.projects, project.Name)
+	return []string{"invited @janedoe"}, nil
+}
+
+func newWriteServer(t *testing.T) (*httptest.Server, *db.MemoryStore, *fakeOnboarder) {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	store.AddAPIToken(model.FoundationOfficer{Name: "Octo Cat", GitHubAccount: "octocat"}, testToken, nil)
+	expired := time.Now().Add(-time.Hour)
+	store.AddAPIToken(model.FoundationOfficer{GitHubAccount: "octocat"}, "mdt_expired", &expired)
+
+	onboarder := &fakeOnboarder{}
+	server := NewServer(store)
+	server.Onboarder = onboarder
+	srv := httptest.NewServer(server.Handler())
+	t.Cleanup(srv.Close)
+	return srv, store, onboarder
+}
+
+// send makes a request with token, returning the response with its body decoded into out when out is not nil.
+func send(t *testing.T, srv *httptest.Server, method, path, token string, body, out any) *http.Response {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, srv.URL+path, &buf)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp
+}
+
+func TestWritesNeedAToken(t *testing.T) {
+	srv, _, _ := newWriteServer(t)
+	body := v1.AddMaintainer{GitHub: "grace"}
+	for _, token := range []string{"", "mdt_wrong", "mdt_expired"} {
+		var apiErr v1.Error
+		resp := send(t, srv, http.MethodPost, "/api/v1/projects/Jaeger/maintainers", token, body, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode, token)
+		require.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+	}
+}
+
+func TestMaintainerWrites(t *testing.T) {
+	srv, store, _ := newWriteServer(t)
+
+	var grace v1.Maintainer
+	resp := send(t, srv, http.MethodPost, "/api/v1/projects/jaeger/maintainers", testToken,
+		v1.AddMaintainer{Name: "Grace Hopper", GitHub: "grace", Email: "grace@example.org", Company: "Navy"}, &grace)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, v1.Maintainer{Name: "Grace Hopper", GitHub: "grace", Company: "Navy", Status: "Active", Projects: []string{"Jaeger"}}, grace)
+	correlationID := resp.Header.Get("X-Correlation-ID")
+	require.NotEmpty(t, correlationID)
+
+	status, company := "Emeritus", "Example Inc"
+	resp = send(t, srv, http.MethodPatch, "/api/v1/maintainers/grace", testToken, v1.UpdateMaintainer{Status: &status, Company: &company}, &grace)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "Emeritus", grace.Status)
+	require.Equal(t, "Example Inc", grace.Company)
+
+	bad := "Bored"
+	resp = send(t, srv, http.MethodPatch, "/api/v1/maintainers/grace", testToken, v1.UpdateMaintainer{Status: &bad}, &v1.Error{})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp = send(t, srv, http.MethodPatch, "/api/v1/maintainers/grace", testToken, map[string]string{"email": "x"}, &v1.Error{})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "unknown fields are rejected")
+
+	resp = send(t, srv, http.MethodDelete, "/api/v1/projects/Jaeger/maintainers/grace", testToken, nil, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = send(t, srv, http.MethodDelete, "/api/v1/projects/Jaeger/maintainers/grace", testToken, nil, &v1.Error{})
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	entries, err := store.ListAuditLogs(context.Background(), db.AuditFilter{Actor: "octocat"})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	shared := 0
+	for _, e := range entries {
+		if e.CorrelationID == correlationID {
+			shared++
+		}
+	}
+	require.GreaterOrEqual(t, shared, 3, "adding grace created her, her company and her membership")
+}
+
+func TestOnboard(t *testing.T) {
+	srv, store, onboarder := newWriteServer(t)
+
+	var result v1.Onboarding
+	resp := send(t, srv, http.MethodPost, "/api/v1/projects/Kubernetes/services/FOSSA/onboard", testToken, nil, &result)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []string{"invited @janedoe"}, result.Actions)
+	require.Equal(t, []string{"Kubernetes"}, onboarder.projects)
+
+	resp = send(t, srv, http.MethodPost, "/api/v1/projects/Kubernetes/services/Snyk/onboard", testToken, nil, &v1.Error{})
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	entries, err := store.ListAuditLogs(context.Background(), db.AuditFilter{Action: "ONBOARDING_REQUESTED"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "octocat", entries[0].Actor)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maintainerd/db"
+	"maintainerd/model"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newAuditCmd(dbPath *string) *cobra.Command {
+	var query db.AuditQuery
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query the audit log",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := db.OpenSQLiteReadOnly(*dbPath)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(conn)
+
+			filter, err := query.Filter(cmd.Context(), store)
+			if err != nil {
+				return err
+			}
+			entries, err := store.ListAuditLogs(cmd.Context(), filter)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			return printAuditLogs(entries)
+		},
+	}
+
+	cmd.Flags().StringVar(&query.Project, "project", "", "Only show entries for this project name")
+	cmd.Flags().StringVar(&query.Maintainer, "maintainer", "", "Only show entries for the maintainer with this GitHub account")
+	cmd.Flags().StringVar(&query.Service, "service", "", "Only show entries for this service name")
+	cmd.Flags().StringVar(&query.Action, "action", "", "Only show entries with this action, e.g. INVITE_SENT")
+	cmd.Flags().StringVar(&query.Actor, "actor", "", "Only show entries made by this actor")
+	cmd.Flags().StringVar(&query.CorrelationID, "correlation-id", "", "Only show entries with this correlation id")
+	cmd.Flags().StringVar(&query.Since, "since", "", "Only show entries at or after this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&query.Until, "until", "", "Only show entries before this time (RFC 3339 or YYYY-MM-DD)")
+	cmd.Flags().IntVar(&query.Limit, "limit", 100, "Maximum number of entries to show, 0 for all")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print entries as JSON, including their metadata")
+	return cmd
+}
+
+func printAuditLogs(entries []model.AuditLog) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tACTOR\tPROJECT\tMAINTAINER\tSERVICE\tCORRELATION\tMESSAGE")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			e.CreatedAt.Format(time.RFC3339),
+			e.Action,
+			e.Actor,
+			e.ProjectID,
+			optionalID(e.MaintainerID),
+			optionalID(e.ServiceID),
+			e.CorrelationID,
+			e.Message)
+	}
+	return w.Flush()
+}
+
+func optionalID(id *uint) string {
+	if id == nil {
+		return "-"
+	}
+	return fmt.Sprint(*id)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maintainerd/db"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultMaxBackups = 5
+
+// sheetFlags are the flags locating the worksheet, shared by bootstrap and its subcommands.
+type sheetFlags struct {
+	mappingPath string
+	worksheet   string
+	credentials string
+}
+
+// check returns an error unless the worksheet and the credentials to read it were given.
+func (s *sheetFlags) check() error {
+	if s.worksheet == "" {
+		return errors.New("--worksheet is not set, nor is MD_WORKSHEET")
+	}
+	if s.credentials == "" {
+		return errors.New("--credentials is not set, nor is WORKSPACE_CREDENTIALS_FILE")
+	}
+	return nil
+}
+
+// rows returns a RowReader for the worksheet read with mapping.
+func (s *sheetFlags) rows(mapping *db.SheetMapping) (db.RowReader, error) {
+	if err := s.check(); err != nil {
+		return nil, err
+	}
+	return db.SheetRows(s.worksheet, s.credentials, mapping), nil
+}
+
+func newBootstrapCmd(dbPath *string) *cobra.Command {
+	var readRange string
+	var sheet sheetFlags
+	var fossaToken string
+	var seed bool
+	var doBackup bool
+	var maxBackups int
+	var maxBackupAge time.Duration
+	var importFile string
+	var sheetName string
+	var reportPath string
+	var prune string
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Bootstrap the database schema and optionally seed it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// A CSV or XLSX export of the worksheet needs no Google credentials, and FOSSA is optional
+			mapping, err := sheetMapping(sheet.mappingPath, readRange, sheetName)
+			if err != nil {
+				return err
+			}
+			prunePolicy, err := db.ParsePrunePolicy(prune)
+			if err != nil {
+				return err
+			}
+			// Reading part of the worksheet would make everyone on the other tabs look as if they had left it
+			if prunePolicy != db.PruneNone && (readRange != "" || sheetName != "") {
+				return fmt.Errorf("--prune %s needs the whole worksheet, it cannot be used with --range or --sheet", prunePolicy)
+			}
+			var readRows db.RowReader
+			if importFile != "" {
+				readRows = db.FileRows(importFile, mapping)
+				if fossaToken == "" {
+					log.Printf("WARNING: --fossa-token is not set, nor is FOSSA_API_TOKEN, FOSSA will not be loaded")
+				}
+			} else {
+				if readRows, err = sheet.rows(mapping); err != nil {
+					return err
+				}
+				if fossaToken == "" {
+					return errors.New("--fossa-token is not set, nor is FOSSA_API_TOKEN")
+				}
+			}
+			if doBackup {
+				if err := backupDatabase(cmd.Context(), *dbPath, maxBackups, maxBackupAge); err != nil {
+					return fmt.Errorf("failed to create DB backup: %w", err)
+				}
+			}
+			_, report, err := db.BootstrapSQLite(cmd.Context(), *dbPath, readRows, fossaToken, seed, prunePolicy)
+			if err != nil {
+				return fmt.Errorf("bootstrap failed: %w", err)
+			}
+			if report != nil && reportPath != "" {
+				if err := writeImportReport(reportPath, report); err != nil {
+					return fmt.Errorf("failed to write import report: %w", err)
+				}
+				log.Printf("import report written to %s", reportPath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&readRange, "range", "", "Google Sheet range to read, e.g. Active!A1:J2100, instead of the mapping's tabs")
+	cmd.PersistentFlags().StringVar(&sheet.mappingPath, "mapping", "", "YAML file mapping worksheet columns to fields and listing the tabs to read")
+	cmd.PersistentFlags().StringVar(&sheet.worksheet, "worksheet", "", "ID of the Google Sheet listing the maintainers")
+	cmd.PersistentFlags().StringVar(&sheet.credentials, "credentials", "", "Google Workspace service account credentials file for reading the worksheet")
+	cmd.Flags().StringVar(&fossaToken, "fossa-token", "", "FOSSA API token (raw string)")
+	cmd.Flags().BoolVar(&seed, "seed", true, "Whether to load seed data into the database")
+	cmd.Flags().BoolVar(&doBackup, "backup", true, "Whether to create a backup of the database if it exists")
+	cmd.Flags().StringVar(&importFile, "file", "", "Seed from a CSV or XLSX export of the worksheet instead of Google Sheets")
+	cmd.Flags().StringVar(&sheetName, "sheet", "", "Only read this tab of the mapping, or of an XLSX --file")
+	cmd.Flags().StringVar(&reportPath, "report", "", "Write the import report, skipped rows and warnings, to this file (.json for JSON, else text)")
+	cmd.Flags().StringVar(&prune, "prune", string(db.PruneNone), "What to do with maintainers and memberships no longer in the worksheet: none, emeritus or remove-memberships")
+	cmd.PersistentFlags().IntVar(&maxBackups, "max-backups", defaultMaxBackups, "Maximum number of backups to retain, 0 for no limit")
+	cmd.PersistentFlags().DurationVar(&maxBackupAge, "max-backup-age", 0, "Remove backups older than this, e.g. 720h, the newest is always kept; 0 for no limit")
+
+	bindEnv(cmd.PersistentFlags(), "worksheet", "MD_WORKSHEET")
+	bindEnv(cmd.PersistentFlags(), "credentials", "WORKSPACE_CREDENTIALS_FILE")
+	bindEnv(cmd.Flags(), "fossa-token", "FOSSA_API_TOKEN")
+
+	cmd.AddCommand(newAuditCmd(dbPath))
+	cmd.AddCommand(newDiffCmd(dbPath, &sheet))
+	cmd.AddCommand(newSyncSheetCmd(dbPath, &sheet))
+	cmd.AddCommand(newLandscapeCmd(dbPath))
+	cmd.AddCommand(newRestoreCmd(dbPath))
+	cmd.AddCommand(newOfficerCmd(dbPath))
+	cmd.AddCommand(newTokenCmd(dbPath))
+	return cmd
+}
+
+// sheetMapping returns the mapping in mappingPath, or the default mapping when it is empty, narrowed to readRange or
+// to the tab called sheet when either is given.
+func sheetMapping(mappingPath, readRange, sheet string) (*db.SheetMapping, error) {
+	mapping := db.DefaultSheetMapping()
+	if mappingPath != "" {
+		var err error
+		if mapping, err = db.LoadSheetMapping(mappingPath); err != nil {
+			return nil, err
+		}
+	}
+	if readRange != "" {
+		tab := db.SheetTabFromRange(readRange)
+		mapping = mapping.OnlyTab(tab.Name)
+		mapping.Tabs[0].Range = tab.Range
+	} else if sheet != "" {
+		mapping = mapping.OnlyTab(sheet)
+	}
+	return mapping, nil
+}
+
+// writeImportReport writes report to path as JSON when path ends in .json, and as text otherwise.
+func writeImportReport(path string, report *db.ImportReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, ".json") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// backupDatabase takes a verified backup of the database at dbPath, if it exists, and then removes the backups beyond
+// maxBackups or older than maxAge.
+func backupDatabase(ctx context.Context, dbPath string, maxBackups int, maxAge time.Duration) error {
+	info, err := os.Stat(dbPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("existing database file size: %d bytes", info.Size())
+	backupPath := db.BackupPath(dbPath, time.Now())
+	if err := db.BackupSQLite(ctx, dbPath, backupPath); err != nil {
+		return err
+	}
+	log.Printf("existing database backed up to %s", backupPath)
+
+	removed, err := db.PruneBackups(dbPath, maxBackups, maxAge, time.Now())
+	for _, path := range removed {
+		log.Printf("removed old backup: %s", path)
+	}
+	if err != nil {
+		log.Printf("warning: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	envPrefix    = "MAINTAINERD_"
+	configEnvVar = "MAINTAINERD_CONFIG"
+	// envAnnotation lists the other environment variables a flag is read from
+	envAnnotation = "maintainerd_env"
+)
+
+// bindEnv lets the flag called name also be set by the environment variables envs, the names the value was read from
+// before every flag could be set as a MAINTAINERD_ variable.
+func bindEnv(flags *pflag.FlagSet, name string, envs ...string) {
+	if err := flags.SetAnnotation(name, envAnnotation, envs); err != nil {
+		panic(err)
+	}
+	flags.Lookup(name).Usage += ", also $" + strings.Join(envs, ", $")
+}
+
+// envName returns the environment variable setting the flag called name: MAINTAINERD_ and the name in upper case
+// with dashes as underscores, e.g. MAINTAINERD_SHUTDOWN_TIMEOUT.
+func envName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// loadConfig sets the flags of cmd that were not given on the command line from the environment or, failing that,
+// from the file given with --config. The file is looked up under the subcommand first, so that
+//
+//	db: /data/maintainers.db
+//	serve:
+//	  addr: :8080
+//	bootstrap:
+//	  landscape:
+//	    file: landscape.yml
+//
+// sets --db for every subcommand and --addr only for serve.
+func loadConfig(cmd *cobra.Command) error {
+	v := viper.New()
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = os.Getenv(configEnvVar)
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("reading config %s: %w", path, err)
+		}
+	}
+
+	sections := strings.Fields(cmd.CommandPath())[1:]
+	var err error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed || f.Name == "config" || f.Name == "help" {
+			return
+		}
+		value, source, ok := configValue(v, sections, f)
+		if !ok {
+			return
+		}
+		if setErr := f.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid --%s in %s: %w", f.Name, source, setErr)
+		}
+	})
+	return err
+}
+
+// configValue returns the value of f from the environment or v, and where it was found.
+func configValue(v *viper.Viper, sections []string, f *pflag.Flag) (string, string, bool) {
+	for _, env := range append([]string{envName(f.Name)}, f.Annotations[envAnnotation]...) {
+		if value := os.Getenv(env); value != "" {
+			return value, "$" + env, true
+		}
+	}
+	for i := len(sections); i >= 0; i-- {
+		key := strings.Join(append(sections[:i:i], f.Name), ".")
+		if !v.IsSet(key) {
+			continue
+		}
+		value := v.Get(key)
+		if list, ok := value.([]any); ok {
+			parts := make([]string, len(list))
+			for j, part := range list {
+				parts[j] = fmt.Sprint(part)
+			}
+			return strings.Join(parts, ","), v.ConfigFileUsed(), true
+		}
+		return fmt.Sprint(value), v.ConfigFileUsed(), true
+	}
+	return "", "", false
+}
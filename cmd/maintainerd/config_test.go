@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+// newConfigTestCmd returns a root command with a "seed file" subcommand recording the flags it ran with.
+func newConfigTestCmd(got map[string]string) *cobra.Command {
+	root := &cobra.Command{
+		Use: "maintainerd",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return loadConfig(cmd)
+		},
+	}
+	root.PersistentFlags().String("config", "", "")
+	root.PersistentFlags().String("db", defaultDBPath, "")
+	root.SetGlobalNormalizationFunc(normalizeFlag)
+
+	seed := &cobra.Command{Use: "seed"}
+	seed.PersistentFlags().String("worksheet", "", "")
+	bindEnv(seed.PersistentFlags(), "worksheet", "MD_WORKSHEET")
+	file := &cobra.Command{
+		Use: "file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Flags().VisitAll(func(f *pflag.Flag) { got[f.Name] = f.Value.String() })
+			return nil
+		},
+	}
+	file.Flags().String("file", "", "")
+	file.Flags().StringSlice("maturity", nil, "")
+	file.Flags().Duration("max-age", 0, "")
+	seed.AddCommand(file)
+	root.AddCommand(seed)
+	return root
+}
+
+func TestLoadConfig(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "maintainerd.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(`
+db: /data/top.db
+worksheet: top-sheet
+max-age: 720h
+maturity: [Graduated, Incubating]
+seed:
+  worksheet: seed-sheet
+  file:
+    file: rows.csv
+`), 0o600))
+
+	run := func(args ...string) map[string]string {
+		t.Helper()
+		got := map[string]string{}
+		cmd := newConfigTestCmd(got)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		return got
+	}
+
+	got := run("seed", "file")
+	require.Equal(t, defaultDBPath, got["db"], "defaults without a config file")
+	require.Empty(t, got["worksheet"])
+
+	got = run("seed", "file", "--config", config)
+	require.Equal(t, "/data/top.db", got["db"])
+	require.Equal(t, "seed-sheet", got["worksheet"], "the subcommand's section is read first")
+	require.Equal(t, "rows.csv", got["file"])
+	require.Equal(t, "720h0m0s", got["max-age"])
+	require.Equal(t, "[Graduated,Incubating]", got["maturity"])
+
+	t.Setenv(configEnvVar, config)
+	t.Setenv("MD_WORKSHEET", "legacy-sheet")
+	got = run("seed", "file")
+	require.Equal(t, "/data/top.db", got["db"], "the config file is found from the environment")
+	require.Equal(t, "legacy-sheet", got["worksheet"], "the environment overrides the file")
+
+	t.Setenv("MAINTAINERD_WORKSHEET", "env-sheet")
+	t.Setenv("MAINTAINERD_DB", "/data/env.db")
+	got = run("seed", "file")
+	require.Equal(t, "env-sheet", got["worksheet"], "MAINTAINERD_ variables come before the names they replace")
+	require.Equal(t, "/data/env.db", got["db"])
+
+	got = run("seed", "file", "--worksheet", "flag-sheet", "--db-path", "/data/flag.db")
+	require.Equal(t, "flag-sheet", got["worksheet"], "flags override the environment")
+	require.Equal(t, "/data/flag.db", got["db"], "--db-path is --db")
+
+	t.Setenv("MAINTAINERD_MAX_AGE", "a month")
+	cmd := newConfigTestCmd(map[string]string{})
+	cmd.SetArgs([]string{"seed", "file"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	require.ErrorContains(t, cmd.Execute(), "invalid --max-age in $MAINTAINERD_MAX_AGE")
+}
+
+func TestMigrateAndExport(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "maintainers.db")
+	config := filepath.Join(dir, "maintainerd.json")
+	require.NoError(t, os.WriteFile(config, []byte(`{"db": "`+dbPath+`", "export": {"format": "csv", "table": "projects"}}`), 0o600))
+
+	var out bytes.Buffer
+	cmd := newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"migrate", "--config", config})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), dbPath)
+	require.FileExists(t, dbPath)
+
+	out.Reset()
+	cmd = newRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"export", "--config", config})
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, out.String(), "name", "projects are written as csv with a header")
+	require.NotContains(t, out.String(), "{")
+
+	// commands that only read the database do not create or migrate one
+	missing := filepath.Join(dir, "missing.db")
+	for _, args := range [][]string{{"export", "--db", missing}, {"bootstrap", "audit", "--db", missing}} {
+		cmd = newRootCmd()
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		require.ErrorContains(t, err, "failed to open DB", args)
+		require.NoFileExists(t, missing, args)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maintainerd/db"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd(dbPath *string, sheet *sheetFlags) *cobra.Command {
+	var readRange string
+	var importFile string
+	var sheetName string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show what seeding from the worksheet would change, without writing to the database",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mapping, err := sheetMapping(sheet.mappingPath, readRange, sheetName)
+			if err != nil {
+				return err
+			}
+			readRows := db.FileRows(importFile, mapping)
+			if importFile == "" {
+				if readRows, err = sheet.rows(mapping); err != nil {
+					return err
+				}
+			}
+			if _, err := os.Stat(*dbPath); err != nil {
+				return fmt.Errorf("database %s: %w", *dbPath, err)
+			}
+
+			conn, err := db.OpenSQLiteReadOnly(*dbPath)
+			if err != nil {
+				return err
+			}
+			rows, err := readRows(cmd.Context())
+			if err != nil {
+				return err
+			}
+			diff, err := db.DiffSheet(cmd.Context(), conn, rows)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(diff)
+			}
+			if diff.Empty() {
+				fmt.Println("The database matches the worksheet")
+				return nil
+			}
+			return diff.WriteText(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&readRange, "range", "", "Google Sheet range to read, e.g. Active!A1:J2100, instead of the mapping's tabs")
+	cmd.Flags().StringVar(&importFile, "file", "", "Compare a CSV or XLSX export of the worksheet instead of Google Sheets")
+	cmd.Flags().StringVar(&sheetName, "sheet", "", "Only read this tab of the mapping, or of an XLSX --file")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the differences as JSON")
+	return cmd
+}
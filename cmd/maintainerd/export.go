@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+func newExportCmd(dbPath *string) *cobra.Command {
+	var format string
+	var table string
+	var output string
+	var maturity string
+	var service string
+	var withEmails bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the registry as JSON, YAML or CSV",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts := db.ExportOptions{RedactEmails: !withEmails, Service: service}
+			if maturity != "" {
+				for _, level := range strings.Split(maturity, ",") {
+					m := model.Maturity(strings.TrimSpace(level))
+					if !m.IsValid() {
+						return fmt.Errorf("export: invalid maturity %q", level)
+					}
+					opts.Maturities = append(opts.Maturities, m)
+				}
+			}
+
+			conn, err := db.OpenSQLiteReadOnly(*dbPath)
+			if err != nil {
+				return err
+			}
+			export, err := db.BuildExport(cmd.Context(), conn, opts)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = cmd.OutOrStdout()
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("export: %w", err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			switch format {
+			case "json":
+				return export.WriteJSON(w)
+			case "yaml":
+				return export.WriteYAML(w)
+			case "csv":
+				return export.WriteCSV(w, table)
+			default:
+				return fmt.Errorf("export: unknown format %q, use json, yaml or csv", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "json", "Output format: json, yaml or csv")
+	cmd.Flags().StringVar(&table, "table", "maintainers", "Table to write as csv: "+strings.Join(db.ExportTables, ", "))
+	cmd.Flags().StringVar(&output, "output", "", "File to write to, standard output when empty")
+	cmd.Flags().StringVar(&maturity, "maturity", "", "Comma separated maturity levels to export, e.g. Graduated,Incubating")
+	cmd.Flags().StringVar(&service, "service", "", "Only export projects with a team on this service, e.g. FOSSA")
+	cmd.Flags().BoolVar(&withEmails, "include-emails", false, "Include maintainers' email addresses, they are redacted by default")
+	return cmd
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maintainerd/plugins/fossa"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newFossaCmd() *cobra.Command {
+	var token string
+
+	cmd := &cobra.Command{
+		Use:   "fossa [team]",
+		Short: "List the FOSSA teams, or the email addresses of the members of one",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				return errors.New("--fossa-token is not set, nor is FOSSA_API_TOKEN")
+			}
+			fossaClient := fossa.NewClient(token)
+			teams, err := fossaClient.FetchTeams(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("error fetching teams: %w", err)
+			}
+			out := cmd.OutOrStdout()
+			if len(args) == 0 {
+				fmt.Fprintln(out, "Your teams:")
+				for _, t := range teams {
+					fmt.Fprintf(out, "  %3d  %-20s  users:%3d  projects:%3d  releases:%3d\n",
+						t.ID, t.Name,
+						len(t.TeamUsers),
+						t.TeamProjectsCount,
+						t.TeamReleaseGroupsCount,
+					)
+				}
+				return nil
+			}
+
+			teamID, err := fossaClient.GetTeamId(teams, args[0])
+			if err != nil {
+				return fmt.Errorf("error fetching team: %w", err)
+			}
+			emails, err := fossaClient.FetchTeamUserEmails(cmd.Context(), teamID)
+			if err != nil {
+				return fmt.Errorf("error fetching users: %w", err)
+			}
+			fmt.Fprintln(out, "Team members’ emails:")
+			fmt.Fprintln(out, strings.Join(emails, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&token, "fossa-token", "", "FOSSA API token (raw string)")
+	bindEnv(cmd.Flags(), "fossa-token", "FOSSA_API_TOKEN")
+	return cmd
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maintainerd/db"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newLandscapeCmd(dbPath *string) *cobra.Command {
+	var landscapePath string
+	var dryRun bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "landscape",
+		Short: "Set project maturity and repos from a local copy of the CNCF landscape.yml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if landscapePath == "" {
+				return fmt.Errorf("--file is required")
+			}
+			landscape, err := db.LoadLandscape(landscapePath)
+			if err != nil {
+				return err
+			}
+
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			ctx := db.WithCorrelationID(db.WithActor(cmd.Context(), "landscape"), db.NewCorrelationID())
+			report, err := db.ImportLandscape(ctx, conn, landscape, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			if dryRun {
+				fmt.Println("dry run, nothing was written")
+			}
+			return report.WriteText(os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&landscapePath, "file", "", "Path to landscape.yml, e.g. a checkout of github.com/cncf/landscape")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without writing them")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
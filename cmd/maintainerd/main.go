@@ -0,0 +1,62 @@
+// Command maintainerd runs the onboarding server and the tools that maintain its database, each as a subcommand.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const defaultDBPath = "maintainers.db"
+
+func main() {
+	// Interrupting a command cancels outstanding Sheets, FOSSA and database calls, and shuts the server down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		stop()
+		log.Fatalf("maintainerd: ERR, %v", err)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	var configPath string
+	var dbPath string
+
+	rootCmd := &cobra.Command{
+		Use:           "maintainerd",
+		Short:         "Onboard CNCF project maintainers to the services the foundation provides",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return loadConfig(cmd)
+		},
+	}
+
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "YAML, JSON or TOML file setting flags by name, also read from $"+configEnvVar)
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", defaultDBPath, "Path to SQLite database file")
+	rootCmd.SetGlobalNormalizationFunc(normalizeFlag)
+
+	rootCmd.AddCommand(newServeCmd(&dbPath))
+	rootCmd.AddCommand(newBootstrapCmd(&dbPath))
+	rootCmd.AddCommand(newFossaCmd())
+	rootCmd.AddCommand(newReconcileCmd(&dbPath))
+	rootCmd.AddCommand(newExportCmd(&dbPath))
+	rootCmd.AddCommand(newMigrateCmd(&dbPath))
+	rootCmd.AddCommand(newWhoCmd(&dbPath))
+	return rootCmd
+}
+
+// normalizeFlag maps the names flags had before the subcommands shared them to their current ones.
+func normalizeFlag(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	if name == "db-path" {
+		name = "db"
+	}
+	return pflag.NormalizedName(name)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"maintainerd/db"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd(dbPath *string) *cobra.Command {
+	var doBackup bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Create the database, or bring its schema up to date, without seeding it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Backups taken here are never pruned, that is left to bootstrap's --max-backups
+			if doBackup {
+				if err := backupDatabase(cmd.Context(), *dbPath, 0, 0); err != nil {
+					return fmt.Errorf("failed to create DB backup: %w", err)
+				}
+			}
+			if _, err := db.OpenSQLite(*dbPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "schema of %s is up to date\n", *dbPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&doBackup, "backup", true, "Whether to create a backup of the database if it exists")
+	return cmd
+}
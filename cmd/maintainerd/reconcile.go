@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maintainerd/db"
+	"maintainerd/plugins/fossa"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// teamDrift is the difference between the maintainers expected in a project's team on a service and its members.
+type teamDrift struct {
+	Project string   `json:"project"`
+	Team    string   `json:"team"`
+	Missing []string `json:"missing,omitempty"`
+	Extra   []string `json:"extra,omitempty"`
+}
+
+func newReconcileCmd(dbPath *string) *cobra.Command {
+	var service string
+	var project string
+	var token string
+	var asJSON bool
+	var exitCode bool
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compare each project's maintainers with the members of its team on a service, without changing either",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// FOSSA is the only service whose teams can be listed
+			if !strings.EqualFold(service, "FOSSA") {
+				return fmt.Errorf("reconcile: unsupported service %s", service)
+			}
+			if token == "" {
+				return errors.New("--fossa-token is not set, nor is FOSSA_API_TOKEN")
+			}
+			ctx := cmd.Context()
+			conn, err := db.OpenSQLiteReadOnly(*dbPath)
+			if err != nil {
+				return err
+			}
+			store := db.NewSQLStore(conn)
+			teams, err := store.GetProjectServiceTeamMap(ctx, service)
+			if err != nil {
+				return err
+			}
+			projects, err := store.GetProjectMapByName(ctx)
+			if err != nil {
+				return err
+			}
+			if project != "" {
+				if _, ok := projects[project]; !ok {
+					return fmt.Errorf("%w: %s", db.ErrProjectNotFound, project)
+				}
+			}
+			names := make([]string, 0, len(projects))
+			for name := range projects {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			fossaClient := fossa.NewClient(token)
+			drifts := []teamDrift{}
+			checked := 0
+			for _, name := range names {
+				p := projects[name]
+				team, ok := teams[p.ID]
+				if !ok || (project != "" && name != project) {
+					continue
+				}
+				checked++
+				expected, err := db.ExpectedTeamMembers(ctx, store, p.ID, teams)
+				if err != nil {
+					return err
+				}
+				members, err := fossaClient.FetchTeamUserEmails(ctx, team.ServiceTeamID)
+				if err != nil {
+					return fmt.Errorf("reconcile: members of %s's team: %w", name, err)
+				}
+				d := teamDrift{Project: name, Team: fmt.Sprint(team.ServiceTeamID)}
+				if team.ServiceTeamName != nil {
+					d.Team = *team.ServiceTeamName
+				}
+				if d.Missing, d.Extra = db.CompareTeam(expected, members); len(d.Missing)+len(d.Extra) > 0 {
+					drifts = append(drifts, d)
+				}
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(drifts); err != nil {
+					return err
+				}
+			} else if err := printDrift(drifts, checked, service); err != nil {
+				return err
+			}
+			if exitCode && len(drifts) > 0 {
+				return fmt.Errorf("%d of %d %s teams are out of step with the registry", len(drifts), checked, service)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&service, "service", "FOSSA", "Service whose teams are compared")
+	cmd.Flags().StringVar(&project, "project", "", "Only compare the team of this project")
+	cmd.Flags().StringVar(&token, "fossa-token", "", "FOSSA API token (raw string)")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the teams that are out of step as JSON")
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Fail when a team is out of step, for scheduled checks")
+	bindEnv(cmd.Flags(), "fossa-token", "FOSSA_API_TOKEN")
+	return cmd
+}
+
+func printDrift(drifts []teamDrift, checked int, service string) error {
+	fmt.Printf("%d of %d %s teams match the registry\n", checked-len(drifts), checked, service)
+	if len(drifts) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROJECT\tTEAM\t\tMAINTAINER")
+	for _, d := range drifts {
+		for _, m := range d.Missing {
+			fmt.Fprintf(w, "%s\t%s\tmissing\t%s\n", d.Project, d.Team, m)
+		}
+		for _, m := range d.Extra {
+			fmt.Fprintf(w, "%s\t%s\textra\t%s\n", d.Project, d.Team, m)
+		}
+	}
+	return w.Flush()
+}
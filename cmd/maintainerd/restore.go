@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maintainerd/db"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCmd(dbPath *string) *cobra.Command {
+	var from string
+	var doBackup bool
+	var verifyOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Verify a backup and restore the database from it, maintainerd must be stopped",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				backups, err := db.Backups(*dbPath)
+				if err != nil {
+					return err
+				}
+				if len(backups) == 0 {
+					return fmt.Errorf("no backups of %s found, use --from", *dbPath)
+				}
+				from = backups[len(backups)-1]
+			}
+
+			if err := db.VerifySQLite(cmd.Context(), from); err != nil {
+				return err
+			}
+			log.Printf("%s passed integrity_check", from)
+			if verifyOnly {
+				return nil
+			}
+
+			// The database being replaced is kept as a backup of its own so that a restore can be undone. Old backups
+			// are not pruned here, which could remove the one being restored.
+			if _, err := os.Stat(*dbPath); err == nil && doBackup {
+				backupPath := db.BackupPath(*dbPath, time.Now())
+				if err := db.BackupSQLite(cmd.Context(), *dbPath, backupPath); err != nil {
+					return fmt.Errorf("failed to back up %s before restoring: %w", *dbPath, err)
+				}
+				log.Printf("%s backed up to %s", *dbPath, backupPath)
+			}
+			if err := db.RestoreSQLite(cmd.Context(), from, *dbPath); err != nil {
+				return err
+			}
+			log.Printf("%s restored from %s", *dbPath, from)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Backup to restore, the newest backup of --db when empty")
+	cmd.Flags().BoolVar(&doBackup, "backup", true, "Whether to back up the database being replaced first")
+	cmd.Flags().BoolVar(&verifyOnly, "verify-only", false, "Only check the backup with PRAGMA integrity_check")
+	return cmd
+}
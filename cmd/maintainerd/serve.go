@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+	githuboauth "golang.org/x/oauth2/github"
+
+	"maintainerd/db"
+	"maintainerd/onboarding"
+	"maintainerd/portal"
+)
+
+func newServeCmd(dbPath *string) *cobra.Command {
+	var (
+		fossaEnvVar   string
+		webhookSecret string
+		addr          string
+		ghRep         string
+		ghOrg         string
+		ghToken       string
+		adminToken    string
+		subprojects   string
+		projectTTL    time.Duration
+		oauthID       string
+		oauthSecret   string
+		publicURL     string
+		portalKey     string
+		shutdownWait  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the onboarding server: GitHub webhooks, the API, the admin UI and the maintainer portal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if webhookSecret == "" {
+				return errors.New("must provide --webhook-secret or set GITHUB_WEBHOOK_SECRET")
+			}
+			subprojectTeams, err := onboarding.ParseSubprojectTeams(subprojects)
+			if err != nil {
+				return err
+			}
+			if err := onboarding.ValidateAddr(addr); err != nil {
+				return fmt.Errorf("--addr: %w", err)
+			}
+			if err := onboarding.ValidateGitHubRepo(ghOrg, ghRep); err != nil {
+				return fmt.Errorf("--org and --repo: %w", err)
+			}
+			if shutdownWait <= 0 {
+				return errors.New("--shutdown-timeout must be positive")
+			}
+
+			// instantiate and initialize listener
+			listener := &onboarding.EventListener{
+				Secret:          []byte(webhookSecret),
+				AdminToken:      []byte(adminToken),
+				ProjectTTL:      projectTTL,
+				SubprojectTeams: subprojectTeams,
+				ShutdownTimeout: shutdownWait,
+			}
+			if oauthID != "" {
+				if oauthSecret == "" || publicURL == "" {
+					return errors.New("the maintainer portal needs --github-oauth-client-secret and --public-url as well as --github-oauth-client-id")
+				}
+				listener.PortalOAuth = &oauth2.Config{
+					ClientID:     oauthID,
+					ClientSecret: oauthSecret,
+					Endpoint:     githuboauth.Endpoint,
+					RedirectURL:  strings.TrimSuffix(publicURL, "/") + portal.Prefix + "/callback",
+				}
+				listener.PortalSessionKey = []byte(portalKey)
+			}
+			if err := listener.Init(*dbPath, fossaEnvVar, ghToken, ghRep, ghOrg); err != nil {
+				return fmt.Errorf("failed to init EventListener: %w", err)
+			}
+
+			log.Printf("maintainerd: DBG, Starting onboarding server on %s…", addr)
+			if err := listener.Run(cmd.Context(), addr); err != nil {
+				return fmt.Errorf("server error: %w", err)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&fossaEnvVar, "fossa-token-env", "FOSSA_API_TOKEN", "Name of the env var holding the FOSSA API token")
+	flags.StringVar(&webhookSecret, "webhook-secret", "", "GitHub webhook secret (raw string)")
+	flags.StringVar(&addr, "addr", ":2525", "Address to listen on (e.g. :2525)")
+	flags.StringVar(&ghRep, "repo", "sandbox", "Name of the repository (e.g. sandbox)")
+	flags.StringVar(&ghOrg, "org", "cncf", "Name of the GitHub org (e.g. cncf)")
+	flags.StringVar(&ghToken, "gh-api", "", "GitHub API token (raw string)")
+	flags.StringVar(&adminToken, "admin-token", "", "Bearer token for the /admin endpoints (raw string)")
+	flags.StringVar(&subprojects, "subproject-teams", string(onboarding.TeamPerSubproject), "Service teams for subprojects: per-subproject or inherit-parent")
+	flags.DurationVar(&projectTTL, "project-cache-ttl", db.DefaultProjectCacheTTL, "How long projects are cached before being reloaded from the database")
+	flags.StringVar(&oauthID, "github-oauth-client-id", "", "Client ID of the GitHub OAuth app maintainers sign in to the portal with")
+	flags.StringVar(&oauthSecret, "github-oauth-client-secret", "", "Client secret of the GitHub OAuth app (raw string)")
+	flags.StringVar(&publicURL, "public-url", "", "URL maintainerd is reached at, e.g. https://maintainerd.cncf.io, for the portal's OAuth callback")
+	flags.StringVar(&portalKey, "portal-session-key", "", "Key signing portal sessions (raw string), random when empty")
+	flags.DurationVar(&shutdownWait, "shutdown-timeout", onboarding.DefaultShutdownTimeout, "How long to wait for requests and webhook jobs to finish on SIGTERM")
+
+	bindEnv(flags, "webhook-secret", "GITHUB_WEBHOOK_SECRET")
+	bindEnv(flags, "gh-api", "GITHUB_API_TOKEN")
+	bindEnv(flags, "github-oauth-client-id", "GITHUB_OAUTH_CLIENT_ID")
+	bindEnv(flags, "github-oauth-client-secret", "GITHUB_OAUTH_CLIENT_SECRET")
+	return cmd
+}
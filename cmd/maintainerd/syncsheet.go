@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maintainerd/db"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newSyncSheetCmd(dbPath *string, sheet *sheetFlags) *cobra.Command {
+	var readRange string
+	var dryRun bool
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "sync-sheet",
+		Short: "Write GitHub handles, maintainer statuses and import warnings back to the worksheet",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := sheet.check(); err != nil {
+				return err
+			}
+			mapping, err := sheetMapping(sheet.mappingPath, readRange, "")
+			if err != nil {
+				return err
+			}
+
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			ctx := db.WithCorrelationID(db.WithActor(cmd.Context(), "sync-sheet"), db.NewCorrelationID())
+			report, err := db.SyncSheet(ctx, conn, sheet.worksheet, sheet.credentials, mapping, dryRun)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			verb := "wrote"
+			if dryRun {
+				verb = "would write"
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "%s %d cells, %d conflicts left alone\n", verb, len(report.Updates), len(report.Conflicts))
+			fmt.Fprintln(w, "\tCELL\tCOLUMN\tEMAIL\tSHEET\tMAINTAINERD")
+			for _, u := range report.Updates {
+				fmt.Fprintf(w, "update\t%s\t%s\t%s\t%q\t%q\n", u.Cell, u.Column, u.Email, u.Sheet, u.Value)
+			}
+			for _, u := range report.Conflicts {
+				fmt.Fprintf(w, "conflict\t%s\t%s\t%s\t%q\t%q\n", u.Cell, u.Column, u.Email, u.Sheet, u.Value)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&readRange, "range", "", "Google Sheet range holding the maintainer rows, instead of the mapping's tabs")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the cells that would be written without writing them")
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the report as JSON")
+	return cmd
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"maintainerd/db"
+	"maintainerd/model"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newOfficerCmd(dbPath *string) *cobra.Command {
+	var name, github, email string
+
+	cmd := &cobra.Command{
+		Use:   "officer",
+		Short: "Manage the foundation officers allowed to use the write API",
+	}
+	add := &cobra.Command{
+		Use:   "add",
+		Short: "Register a foundation officer, or update one with the same GitHub account",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			ctx := db.WithCorrelationID(db.WithActor(cmd.Context(), "bootstrap"), db.NewCorrelationID())
+			officer := model.FoundationOfficer{Name: name, GitHubAccount: github, Email: email}
+			if err := db.AddFoundationOfficer(ctx, conn, &officer); err != nil {
+				return err
+			}
+			fmt.Printf("foundation officer %s (@%s) registered\n", officer.Name, officer.GitHubAccount)
+			return nil
+		},
+	}
+	add.Flags().StringVar(&name, "name", "", "Officer's name")
+	add.Flags().StringVar(&github, "github", "", "Officer's GitHub account, required")
+	add.Flags().StringVar(&email, "email", "", "Officer's email address")
+	cmd.AddCommand(add)
+	return cmd
+}
+
+func newTokenCmd(dbPath *string) *cobra.Command {
+	var officer, name string
+	var ttl time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Issue, list and revoke API tokens for foundation officers",
+	}
+	issue := &cobra.Command{
+		Use:   "issue",
+		Short: "Issue an API token, it is printed once and only its hash is stored",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			ctx := db.WithCorrelationID(db.WithActor(cmd.Context(), "bootstrap"), db.NewCorrelationID())
+			token, record, err := db.IssueAPIToken(ctx, conn, officer, name, ttl)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "token %d issued to @%s\n", record.ID, record.FoundationOfficer.GitHubAccount)
+			fmt.Println(token)
+			return nil
+		},
+	}
+	issue.Flags().StringVar(&officer, "officer", "", "GitHub account of the foundation officer, required")
+	issue.Flags().StringVar(&name, "name", "", "What the token is for, e.g. the machine it is used on")
+	issue.Flags().DurationVar(&ttl, "ttl", 90*24*time.Hour, "How long the token is valid for, 0 for no expiry")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List API tokens",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			tokens, err := db.ListAPITokens(cmd.Context(), conn)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tOFFICER\tNAME\tCREATED\tEXPIRES\tREVOKED")
+			for _, t := range tokens {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.FoundationOfficer.GitHubAccount, t.Name,
+					t.CreatedAt.Format(time.RFC3339), formatTime(t.ExpiresAt), formatTime(t.RevokedAt))
+			}
+			return w.Flush()
+		},
+	}
+
+	revoke := &cobra.Command{
+		Use:   "revoke ID",
+		Short: "Revoke an API token",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid token id %q", args[0])
+			}
+			conn, err := db.OpenSQLite(*dbPath)
+			if err != nil {
+				return err
+			}
+			ctx := db.WithCorrelationID(db.WithActor(cmd.Context(), "bootstrap"), db.NewCorrelationID())
+			if err := db.RevokeAPIToken(ctx, conn, uint(id)); err != nil {
+				return err
+			}
+			fmt.Printf("token %d revoked\n", id)
+			return nil
+		},
+	}
+
+	cmd.AddCommand(issue, list, revoke)
+	return cmd
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maintainerd/db"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newWhoCmd(dbPath *string) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "who <email|github|name>",
+		Short: "Show who a person is and what they have access to, by email address, GitHub account or name",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := strings.Join(args, " ")
+			conn, err := db.OpenSQLiteReadOnly(*dbPath)
+			if err != nil {
+				return err
+			}
+			people, err := db.Who(cmd.Context(), conn, query)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if asJSON {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(people)
+			}
+			if len(people) == 0 {
+				return fmt.Errorf("no maintainer or collaborator is known as %q", query)
+			}
+			for i, p := range people {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				if err := printPerson(out, p); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the people found as JSON, an empty list when there are none")
+	return cmd
+}
+
+func printPerson(out io.Writer, p db.Person) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s (%s %d, matched by %s)\n", p.Name, p.Kind, p.ID, p.MatchedBy)
+	fmt.Fprintf(w, "  Email:\t%s\n", orDash(p.Email))
+	fmt.Fprintf(w, "  GitHub:\t%s\n", orDash(p.GitHubAccount))
+	if p.GitHubEmail != "" && !strings.EqualFold(p.GitHubEmail, p.Email) {
+		fmt.Fprintf(w, "  GitHub email:\t%s\n", p.GitHubEmail)
+	}
+	if p.Kind == "maintainer" {
+		fmt.Fprintf(w, "  Company:\t%s\n", orDash(p.Company))
+		fmt.Fprintf(w, "  Status:\t%s\n", orDash(p.Status))
+	}
+	fmt.Fprintf(w, "  Projects:\t%s\n", orDash(strings.Join(p.Projects, ", ")))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(p.ServiceUsers) == 0 {
+		fmt.Fprintln(w, "  No service accounts")
+	} else {
+		fmt.Fprintln(w, "  SERVICE\tUSER ID\tEMAIL\tGITHUB\tREF")
+		for _, su := range p.ServiceUsers {
+			fmt.Fprintf(w, "  %s\t%d\t%s\t%s\t%s\n", su.Service, su.ServiceUserID, orDash(su.Email), orDash(su.GitHubName), orDash(su.Ref))
+		}
+	}
+	if len(p.Teams) == 0 {
+		fmt.Fprintln(w, "  No service teams")
+	} else {
+		fmt.Fprintln(w, "  SERVICE\tPROJECT\tTEAM ID\tTEAM\tUSER ID")
+		for _, t := range p.Teams {
+			fmt.Fprintf(w, "  %s\t%s\t%d\t%s\t%d\n", t.Service, t.Project, t.TeamID, orDash(t.TeamName), t.ServiceUserID)
+		}
+	}
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
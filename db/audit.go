@@ -0,0 +1,462 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maintainerd/model"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// SystemActor is recorded against changes made without an actor in their context, e.g. a bootstrap run.
+	SystemActor = "system"
+
+	AuditActionCreate = "CREATE"
+	AuditActionUpdate = "UPDATE"
+	AuditActionDelete = "DELETE"
+
+	auditLogTable        = "audit_logs"
+	sheetSyncStateTable  = "sheet_sync_states"
+	webhookDeliveryTable = "webhook_deliveries"
+	webhookCursorTable   = "webhook_cursors"
+	auditBeforeKey       = "maintainerd:audit_before"
+)
+
+type auditContextKey int
+
+const (
+	actorKey auditContextKey = iota
+	correlationIDKey
+)
+
+// WithActor returns a copy of ctx that attributes audited changes to actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx or SystemActor if there is none.
+func ActorFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+			return actor
+		}
+	}
+	return SystemActor
+}
+
+// WithCorrelationID returns a copy of ctx whose audited changes share the correlation id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationIDFromContext returns the correlation id carried by ctx, or "" if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if id, ok := ctx.Value(correlationIDKey).(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+// NewCorrelationID returns a random id suitable for WithCorrelationID.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// AuditFilter narrows the entries returned by ListAuditLogs, zero valued fields are not used to filter.
+type AuditFilter struct {
+	ProjectID     *uint
+	MaintainerID  *uint
+	ServiceID     *uint
+	Action        string
+	Actor         string
+	CorrelationID string
+	Since         time.Time
+	Until         time.Time
+	AfterID       uint // only entries with a greater id, which are listed oldest first by id
+	Limit         int
+}
+
+// RegisterAuditCallbacks installs GORM callbacks on db so that every create, update and delete, apart from those on
+// the audit log itself, writes an AuditLog entry in the same transaction as the change. Each entry records the actor
+// and correlation id found in the statement's context and holds the row before and after the change in Metadata.
+func RegisterAuditCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").
+		Register("maintainerd:audit_create", auditRecord(AuditActionCreate)); err != nil {
+		return fmt.Errorf("RegisterAuditCallbacks: create: %w", err)
+	}
+	if err := cb.Update().After("gorm:setup_reflect_value").Before("gorm:update").
+		Register("maintainerd:audit_capture_update", auditCapture); err != nil {
+		return fmt.Errorf("RegisterAuditCallbacks: capture update: %w", err)
+	}
+	if err := cb.Update().After("gorm:update").
+		Register("maintainerd:audit_update", auditRecord(AuditActionUpdate)); err != nil {
+		return fmt.Errorf("RegisterAuditCallbacks: update: %w", err)
+	}
+	if err := cb.Delete().Before("gorm:delete").
+		Register("maintainerd:audit_capture_delete", auditCapture); err != nil {
+		return fmt.Errorf("RegisterAuditCallbacks: capture delete: %w", err)
+	}
+	if err := cb.Delete().After("gorm:delete").
+		Register("maintainerd:audit_delete", auditRecord(AuditActionDelete)); err != nil {
+		return fmt.Errorf("RegisterAuditCallbacks: delete: %w", err)
+	}
+	return nil
+}
+
+func auditable(db *gorm.DB) bool {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return false
+	}
+	// Sheet sync bookkeeping is audited as the sheet writes it stands for, and webhook deliveries are logged in their
+	// own table
+	switch db.Statement.Schema.Table {
+	case auditLogTable, sheetSyncStateTable, webhookDeliveryTable, webhookCursorTable:
+		return false
+	}
+	return true
+}
+
+// redactedColumns hold secrets that are never written to the audit log.
+var redactedColumns = map[string]bool{"secret": true}
+
+const redacted = "[redacted]"
+
+// auditRows returns the struct values a statement is working on.
+func auditRows(rv reflect.Value) []reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		rows := make([]reflect.Value, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, reflect.Indirect(rv.Index(i)))
+		}
+		return rows
+	case reflect.Struct:
+		return []reflect.Value{rv}
+	}
+	return nil
+}
+
+// auditCapture loads the rows an update or delete is about to change so that auditRecord can store them as "before".
+func auditCapture(db *gorm.DB) {
+	if !auditable(db) || db.Statement.Schema.PrioritizedPrimaryField == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	pkField := db.Statement.Schema.PrioritizedPrimaryField
+	rows := auditRows(db.Statement.ReflectValue)
+	before := make([]any, len(rows))
+	for i, rv := range rows {
+		pk, zero := pkField.ValueOf(ctx, rv)
+		if zero {
+			continue
+		}
+		prev := reflect.New(db.Statement.Schema.ModelType).Interface()
+		if err := db.Session(&gorm.Session{NewDB: true}).Unscoped().Take(prev, pk).Error; err == nil {
+			before[i] = prev
+		}
+	}
+	db.InstanceSet(auditBeforeKey, before)
+}
+
+// auditRecord returns a callback that writes one AuditLog entry for every row changed by a statement.
+func auditRecord(action string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !auditable(db) || db.Statement.RowsAffected == 0 {
+			return
+		}
+		ctx := db.Statement.Context
+		s := db.Statement.Schema
+
+		var before []any
+		if v, ok := db.InstanceGet(auditBeforeKey); ok {
+			before, _ = v.([]any)
+		}
+
+		tx := db.Session(&gorm.Session{NewDB: true})
+		for i, rv := range auditRows(db.Statement.ReflectValue) {
+			var prev, next map[string]any
+			subject := rv
+			if i < len(before) && before[i] != nil {
+				subject = reflect.Indirect(reflect.ValueOf(before[i]))
+				prev = auditColumns(ctx, s, subject)
+			}
+			if action == AuditActionCreate || (action == AuditActionUpdate && prev != nil) {
+				next = auditColumns(ctx, s, rv)
+			}
+			metadata := map[string]any{
+				"table":  s.Table,
+				"before": prev,
+				"after":  next,
+			}
+			if prev != nil && next != nil {
+				changed := changedColumns(prev, next)
+				if len(changed) == 0 {
+					// e.g. an association append that only touched updated_at
+					continue
+				}
+				metadata["changed"] = changed
+			}
+			if changes, ok := db.Statement.Dest.(map[string]interface{}); ok {
+				metadata["changes"] = changes
+			}
+			if prev == nil && action != AuditActionCreate {
+				// Without a primary key we cannot say which rows changed, so keep the statement itself.
+				metadata["statement"] = db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...)
+				metadata["rows_affected"] = db.Statement.RowsAffected
+			}
+			blob, err := json.Marshal(metadata)
+			if err != nil {
+				db.AddError(fmt.Errorf("audit: failed to encode %s on %s: %w", action, s.Table, err))
+				return
+			}
+
+			entry := model.AuditLog{
+				Action:        action + "_" + strings.ToUpper(s.Table),
+				Actor:         ActorFromContext(ctx),
+				CorrelationID: CorrelationIDFromContext(ctx),
+				Message:       fmt.Sprintf("%s %s", strings.ToLower(action), s.Table),
+				Metadata:      string(blob),
+			}
+			setAuditSubjects(ctx, s, subject, &entry)
+
+			if err := tx.Create(&entry).Error; err != nil {
+				db.AddError(fmt.Errorf("audit: failed to record %s on %s: %w", action, s.Table, err))
+				return
+			}
+		}
+	}
+}
+
+// auditColumns returns the column values of rv keyed by column name, leaving out associations.
+func auditColumns(ctx context.Context, s *schema.Schema, rv reflect.Value) map[string]any {
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	columns := make(map[string]any, len(s.DBNames))
+	for _, name := range s.DBNames {
+		f := s.FieldsByDBName[name]
+		v, _ := f.ValueOf(ctx, rv)
+		if redactedColumns[name] {
+			v = redacted
+		}
+		columns[name] = v
+	}
+	return columns
+}
+
+// changedColumns lists the columns, other than updated_at, whose values differ between before and after.
+func changedColumns(before, after map[string]any) []string {
+	var changed []string
+	for name, next := range after {
+		if name == "updated_at" {
+			continue
+		}
+		prev, _ := json.Marshal(before[name])
+		cur, _ := json.Marshal(next)
+		if string(prev) != string(cur) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// setAuditSubjects fills in the project, maintainer and service that a row refers to so that entries can be queried
+// by them.
+func setAuditSubjects(ctx context.Context, s *schema.Schema, rv reflect.Value, entry *model.AuditLog) {
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	value := func(name string) (uint, bool) {
+		f := s.LookUpField(name)
+		if f == nil {
+			return 0, false
+		}
+		v, zero := f.ValueOf(ctx, rv)
+		if zero {
+			return 0, false
+		}
+		return toUint(v)
+	}
+
+	if id, ok := value("ProjectID"); ok {
+		entry.ProjectID = id
+	}
+	if id, ok := value("MaintainerID"); ok {
+		entry.MaintainerID = &id
+	}
+	if id, ok := value("ServiceID"); ok {
+		entry.ServiceID = &id
+	}
+	if id, ok := value("ID"); ok {
+		switch s.Table {
+		case "projects":
+			entry.ProjectID = id
+		case "maintainers":
+			entry.MaintainerID = &id
+		case "services":
+			entry.ServiceID = &id
+		}
+	}
+}
+
+func toUint(v any) (uint, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return 0, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(rv.Uint()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, false
+		}
+		return uint(rv.Int()), true
+	}
+	return 0, false
+}
+
+// ListAuditLogs returns the audit entries that match filter, newest first, or oldest first by id when filter.AfterID
+// is set.
+func (s *SQLStore) ListAuditLogs(ctx context.Context, filter AuditFilter) ([]model.AuditLog, error) {
+	q := s.db.WithContext(ctx).Model(&model.AuditLog{})
+	if filter.ProjectID != nil {
+		q = q.Where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.MaintainerID != nil {
+		q = q.Where("maintainer_id = ?", *filter.MaintainerID)
+	}
+	if filter.ServiceID != nil {
+		q = q.Where("service_id = ?", *filter.ServiceID)
+	}
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.Actor != "" {
+		q = q.Where("actor = ?", filter.Actor)
+	}
+	if filter.CorrelationID != "" {
+		q = q.Where("correlation_id = ?", filter.CorrelationID)
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		q = q.Where("created_at < ?", filter.Until)
+	}
+	if filter.AfterID > 0 {
+		q = q.Where("id > ?", filter.AfterID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	// Entries after an id are read in id order, as created_at need not follow it, so that a reader can resume from
+	// the greatest id it has seen
+	order := "created_at DESC, id DESC"
+	if filter.AfterID > 0 {
+		order = "id"
+	}
+	var entries []model.AuditLog
+	if err := q.Order(order).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("ListAuditLogs: %w", err)
+	}
+	return entries, nil
+}
+
+// AuditQuery holds audit log filters as people type them: a project name, a maintainer's GitHub account, a service
+// name and RFC 3339 timestamps or dates for the time range.
+type AuditQuery struct {
+	Project       string
+	Maintainer    string
+	Service       string
+	Action        string
+	Actor         string
+	CorrelationID string
+	Since         string
+	Until         string
+	Limit         int
+}
+
+// Filter resolves the names in q against store and returns the equivalent AuditFilter.
+func (q AuditQuery) Filter(ctx context.Context, store Store) (AuditFilter, error) {
+	filter := AuditFilter{
+		Action:        strings.ToUpper(q.Action),
+		Actor:         q.Actor,
+		CorrelationID: q.CorrelationID,
+		Limit:         q.Limit,
+	}
+	if q.Project != "" {
+		projects, err := store.GetProjectMapByName(ctx)
+		if err != nil {
+			return filter, fmt.Errorf("AuditQuery: loading projects: %w", err)
+		}
+		project, ok := projects[q.Project]
+		if !ok {
+			return filter, fmt.Errorf("AuditQuery: project %q not found", q.Project)
+		}
+		filter.ProjectID = &project.ID
+	}
+	if q.Maintainer != "" {
+		maintainers, err := store.GetMaintainerMapByGitHubAccount(ctx)
+		if err != nil {
+			return filter, fmt.Errorf("AuditQuery: loading maintainers: %w", err)
+		}
+		maintainer, ok := maintainers[strings.TrimPrefix(q.Maintainer, "@")]
+		if !ok {
+			return filter, fmt.Errorf("AuditQuery: maintainer %q not found", q.Maintainer)
+		}
+		filter.MaintainerID = &maintainer.ID
+	}
+	if q.Service != "" {
+		service, err := store.GetServiceByName(ctx, q.Service)
+		if err != nil {
+			return filter, fmt.Errorf("AuditQuery: service %q not found: %w", q.Service, err)
+		}
+		filter.ServiceID = &service.ID
+	}
+	var err error
+	if filter.Since, err = parseAuditTime(q.Since); err != nil {
+		return filter, fmt.Errorf("AuditQuery: since: %w", err)
+	}
+	if filter.Until, err = parseAuditTime(q.Until); err != nil {
+		return filter, fmt.Errorf("AuditQuery: until: %w", err)
+	}
+	return filter, nil
+}
+
+func parseAuditTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
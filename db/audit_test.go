@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestAuditCallbacks(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:audit?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, RegisterAuditCallbacks(conn))
+	ctx := WithCorrelationID(WithActor(context.Background(), "octocat"), "delivery-1")
+	conn = conn.WithContext(ctx)
+
+	// metadata decodes the before and after of entry
+	metadata := func(entry model.AuditLog) (before, after map[string]any, changed []string) {
+		var m struct {
+			Table   string         `json:"table"`
+			Before  map[string]any `json:"before"`
+			After   map[string]any `json:"after"`
+			Changed []string       `json:"changed"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(entry.Metadata), &m))
+		require.Equal(t, "maintainers", m.Table)
+		return m.Before, m.After, m.Changed
+	}
+	entries := func() []model.AuditLog {
+		var entries []model.AuditLog
+		require.NoError(t, conn.Order("id").Find(&entries).Error)
+		return entries
+	}
+
+	grace := model.Maintainer{Name: "Grace Hopper", Email: "grace@example.org", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, conn.Create(&grace).Error)
+	created := entries()
+	require.Len(t, created, 1)
+	require.Equal(t, "CREATE_MAINTAINERS", created[0].Action)
+	require.Equal(t, "octocat", created[0].Actor)
+	require.Equal(t, "delivery-1", created[0].CorrelationID)
+	require.Equal(t, grace.ID, *created[0].MaintainerID)
+	before, after, _ := metadata(created[0])
+	require.Nil(t, before)
+	require.Equal(t, "Grace Hopper", after["name"])
+
+	require.NoError(t, conn.Model(&grace).Update("maintainer_status", model.EmeritusMaintainer).Error)
+	// an update that changes nothing but updated_at is not recorded
+	require.NoError(t, conn.Model(&grace).Update("name", "Grace Hopper").Error)
+	updated := entries()[1:]
+	require.Len(t, updated, 1)
+	require.Equal(t, "UPDATE_MAINTAINERS", updated[0].Action)
+	require.Equal(t, "octocat", updated[0].Actor)
+	before, after, changed := metadata(updated[0])
+	require.Equal(t, "Active", before["maintainer_status"])
+	require.Equal(t, "Emeritus", after["maintainer_status"])
+	require.Equal(t, []string{"maintainer_status"}, changed)
+
+	require.NoError(t, conn.WithContext(WithActor(context.Background(), "officer")).Delete(&grace).Error)
+	deleted := entries()[2:]
+	require.Len(t, deleted, 1)
+	require.Equal(t, "DELETE_MAINTAINERS", deleted[0].Action)
+	require.Equal(t, "officer", deleted[0].Actor)
+	require.Equal(t, grace.ID, *deleted[0].MaintainerID)
+	before, after, _ = metadata(deleted[0])
+	require.Equal(t, "Grace Hopper", before["name"])
+	require.Nil(t, after)
+}
+
+func TestOpenSQLiteReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "maintainers.db")
+	_, err := OpenSQLiteReadOnly(dbPath)
+	require.Error(t, err, "a missing database is not created")
+	require.NoFileExists(t, dbPath)
+
+	// a database from before the audit log
+	old, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, old.AutoMigrate(&model.Project{}))
+	require.NoError(t, old.Create(&model.Project{Name: "Jaeger", Maturity: model.Graduated}).Error)
+	closeTestDB(t, old)
+
+	conn, err := OpenSQLiteReadOnly(dbPath)
+	require.NoError(t, err)
+	defer closeTestDB(t, conn)
+	var names []string
+	require.NoError(t, conn.Model(&model.Project{}).Pluck("name", &names).Error)
+	require.Equal(t, []string{"Jaeger"}, names)
+	require.False(t, conn.Migrator().HasTable(&model.AuditLog{}), "the database is not migrated")
+	require.Error(t, conn.Create(&model.Project{Name: "Kubernetes", Maturity: model.Graduated}).Error)
+}
+
+func closeTestDB(t *testing.T, conn *gorm.DB) {
+	sqlDB, err := conn.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	// BackupFileExt ends the name of every backup, which is the database's name, a timestamp and BackupFileExt, e.g.
+	// maintainers.db.20250102-150405.123.bak.
+	BackupFileExt = ".bak"
+	// BackupTimeFormat is the layout of the timestamp in a backup's name. It has milliseconds so that backups taken
+	// in the same second, such as the one restore takes just after bootstrap took one, do not collide.
+	BackupTimeFormat = "20060102-150405.000"
+	// legacyBackupTimeFormat is the timestamp of backups named before BackupTimeFormat had milliseconds.
+	legacyBackupTimeFormat = "20060102-150405"
+)
+
+// BackupPath returns the path of a backup of the database at dbPath taken at t.
+func BackupPath(dbPath string, t time.Time) string {
+	return fmt.Sprintf("%s.%s%s", dbPath, t.Format(BackupTimeFormat), BackupFileExt)
+}
+
+// BackupSQLite writes a consistent copy of the database at dbPath to backupPath using VACUUM INTO, which reads the
+// database in a single transaction and so is safe while a server is writing to it. The copy is then checked with
+// VerifySQLite. backupPath must not exist.
+func BackupSQLite(ctx context.Context, dbPath, backupPath string) error {
+	if _, err := os.Stat(backupPath); err == nil {
+		return fmt.Errorf("BackupSQLite: %s already exists", backupPath)
+	}
+	if err := vacuumInto(ctx, dbPath, backupPath); err != nil {
+		return fmt.Errorf("BackupSQLite: %w", err)
+	}
+	if err := VerifySQLite(ctx, backupPath); err != nil {
+		os.Remove(backupPath)
+		return fmt.Errorf("BackupSQLite: backup failed verification: %w", err)
+	}
+	return nil
+}
+
+// VerifySQLite runs PRAGMA integrity_check on the database at path and returns the problems it reports.
+func VerifySQLite(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("VerifySQLite: %w", err)
+	}
+	conn, err := openQuiet(path)
+	if err != nil {
+		return fmt.Errorf("VerifySQLite: %w", err)
+	}
+	defer closeQuiet(conn)
+
+	var results []string
+	if err := conn.WithContext(ctx).Raw("PRAGMA integrity_check").Scan(&results).Error; err != nil {
+		return fmt.Errorf("VerifySQLite: %s: %w", path, err)
+	}
+	if len(results) != 1 || results[0] != "ok" {
+		return fmt.Errorf("VerifySQLite: %s failed integrity_check: %s", path, strings.Join(results, "; "))
+	}
+	return nil
+}
+
+// RestoreSQLite replaces the database at dbPath with the backup at backupPath once the backup has passed
+// VerifySQLite. The backup is copied next to dbPath, checked again and renamed over it, so dbPath is never left half
+// written. maintainerd must not have dbPath open while it is restored.
+func RestoreSQLite(ctx context.Context, backupPath, dbPath string) error {
+	if err := VerifySQLite(ctx, backupPath); err != nil {
+		return fmt.Errorf("RestoreSQLite: %w", err)
+	}
+	tmp := dbPath + ".restore"
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("RestoreSQLite: %w", err)
+	}
+	if err := vacuumInto(ctx, backupPath, tmp); err != nil {
+		return fmt.Errorf("RestoreSQLite: %w", err)
+	}
+	if err := VerifySQLite(ctx, tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("RestoreSQLite: restored copy failed verification: %w", err)
+	}
+	// A write-ahead log left by the replaced database would be replayed into the restored one
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("RestoreSQLite: %w", err)
+		}
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return fmt.Errorf("RestoreSQLite: %w", err)
+	}
+	return nil
+}
+
+// Backups returns the paths of the backups of the database at dbPath, oldest first.
+func Backups(dbPath string) ([]string, error) {
+	dir := filepath.Dir(dbPath)
+	prefix := filepath.Base(dbPath) + "."
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("Backups: %w", err)
+	}
+	var backups []string
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), prefix) && strings.HasSuffix(f.Name(), BackupFileExt) {
+			backups = append(backups, filepath.Join(dir, f.Name()))
+		}
+	}
+	// The timestamp in the name sorts oldest first
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// PruneBackups removes the backups of the database at dbPath beyond the newest maxCount and those taken more than
+// maxAge before now. A maxCount or maxAge of zero disables that limit. The newest backup is always kept. It returns
+// the paths removed.
+func PruneBackups(dbPath string, maxCount int, maxAge time.Duration, now time.Time) ([]string, error) {
+	backups, err := Backups(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	var errs []error
+	for i, path := range backups {
+		newer := len(backups) - 1 - i
+		if newer == 0 {
+			break
+		}
+		tooMany := maxCount > 0 && newer >= maxCount
+		tooOld := maxAge > 0 && now.Sub(backupTime(path, dbPath)) > maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, path)
+	}
+	if len(errs) > 0 {
+		return removed, fmt.Errorf("PruneBackups: %w", errors.Join(errs...))
+	}
+	return removed, nil
+}
+
+// backupTime returns when the backup at path was taken, from its name or, failing that, its modification time.
+func backupTime(path, dbPath string) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), filepath.Base(dbPath)+"."), BackupFileExt)
+	for _, layout := range []string{BackupTimeFormat, legacyBackupTimeFormat} {
+		if t, err := time.ParseInLocation(layout, stamp, time.Local); err == nil {
+			return t
+		}
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+func vacuumInto(ctx context.Context, src, dst string) error {
+	if _, err := os.Stat(src); err != nil {
+		return err
+	}
+	conn, err := openQuiet(src)
+	if err != nil {
+		return err
+	}
+	defer closeQuiet(conn)
+	if err := conn.WithContext(ctx).Exec("VACUUM INTO ?", dst).Error; err != nil {
+		return fmt.Errorf("VACUUM INTO %s: %w", dst, err)
+	}
+	return nil
+}
+
+// openQuiet opens the SQLite database at path without migrating it or logging its statements.
+func openQuiet(path string) (*gorm.DB, error) {
+	conn, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return conn, nil
+}
+
+func closeQuiet(conn *gorm.DB) {
+	if sqlDB, err := conn.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
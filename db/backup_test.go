@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"maintainerd/model"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupAndRestoreSQLite(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "maintainers.db")
+	conn, err := OpenSQLite(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, conn.Create(&model.Project{Name: "Kubernetes", Maturity: model.Graduated}).Error)
+
+	// The backup is taken while conn still has the database open
+	taken := time.Date(2025, 1, 2, 15, 4, 5, 0, time.Local)
+	backupPath := BackupPath(dbPath, taken)
+	require.Equal(t, dbPath+".20250102-150405.000.bak", backupPath)
+	require.NoError(t, BackupSQLite(ctx, dbPath, backupPath))
+	require.Error(t, BackupSQLite(ctx, dbPath, backupPath), "an existing backup is not overwritten")
+	later := BackupPath(dbPath, taken.Add(250*time.Millisecond))
+	require.Equal(t, dbPath+".20250102-150405.250.bak", later)
+	require.NoError(t, BackupSQLite(ctx, dbPath, later), "a second backup in the same second gets its own name")
+	require.NoError(t, VerifySQLite(ctx, backupPath))
+
+	require.NoError(t, conn.Create(&model.Project{Name: "Jaeger", Maturity: model.Graduated}).Error)
+	closeQuiet(conn)
+
+	require.NoError(t, RestoreSQLite(ctx, backupPath, dbPath))
+	restored, err := OpenSQLite(dbPath)
+	require.NoError(t, err)
+	defer closeQuiet(restored)
+	var names []string
+	require.NoError(t, restored.Model(&model.Project{}).Pluck("name", &names).Error)
+	require.Equal(t, []string{"Kubernetes"}, names)
+
+	corrupt := filepath.Join(t.TempDir(), "corrupt.db")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not a database"), 0o600))
+	require.Error(t, VerifySQLite(ctx, corrupt))
+	require.Error(t, RestoreSQLite(ctx, corrupt, dbPath))
+}
+
+func TestPruneBackups(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "maintainers.db")
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	var paths []string
+	for _, daysAgo := range []int{60, 40, 20, 10, 1} {
+		path := BackupPath(dbPath, now.AddDate(0, 0, -daysAgo))
+		if daysAgo == 40 {
+			// named before backups had milliseconds
+			path = dbPath + "." + now.AddDate(0, 0, -daysAgo).Format(legacyBackupTimeFormat) + BackupFileExt
+		}
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		paths = append(paths, path)
+	}
+
+	removed, err := PruneBackups(dbPath, 4, 0, now)
+	require.NoError(t, err)
+	require.Equal(t, paths[:1], removed)
+
+	removed, err = PruneBackups(dbPath, 0, 30*24*time.Hour, now)
+	require.NoError(t, err)
+	require.Equal(t, paths[1:2], removed)
+
+	removed, err = PruneBackups(dbPath, 0, time.Hour, now)
+	require.NoError(t, err)
+	require.Equal(t, paths[2:4], removed)
+	backups, err := Backups(dbPath)
+	require.NoError(t, err)
+	require.Equal(t, paths[4:], backups, "the newest backup is always kept")
+}
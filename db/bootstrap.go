@@ -8,6 +8,7 @@ import (
 	"maintainerd/model"
 	"maintainerd/plugins/fossa"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -30,9 +31,15 @@ const (
 	ParentProjectHdr     string = "Parent Project"
 	MaintainerFileRefHdr string = "OWNERS/MAINTAINERS"
 	MailingListAddrHdr   string = "Mailing List Address"
+	MaintainerStatusHdr  string = "Maintainer Status"
+	ImportWarningsHdr    string = "Import Warnings"
 )
 
-func BootstrapSQLite(dbPath, spreadsheetID, readRange, worksheetCredentialsPath, fossaToken string, seed bool) (*gorm.DB, error) {
+// BootstrapSQLite creates or migrates the database at dbPath and, when seed is set, loads the maintainers and projects
+// returned by readRows followed by the users and teams in FOSSA. FOSSA is skipped when fossaToken is empty. The
+// ImportReport describes the rows that were read, it is nil when seed is not set. Maintainers and memberships that are
+// no longer in the sheet are handled according to prune.
+func BootstrapSQLite(ctx context.Context, dbPath string, readRows RowReader, fossaToken string, seed bool, prune PrunePolicy) (*gorm.DB, *ImportReport, error) {
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
@@ -48,26 +55,24 @@ func BootstrapSQLite(dbPath, spreadsheetID, readRange, worksheetCredentialsPath,
 	})
 	// var s Store = NewSQLStore(db)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open DB: %w", err)
+		return nil, nil, fmt.Errorf("failed to open DB: %w", err)
 	}
 
-	if err := db.AutoMigrate(
-		&model.Company{},
-		&model.Project{},
-		&model.Maintainer{},
-		&model.Collaborator{},
-		&model.MaintainerProject{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.ServiceUser{},
-		&model.ServiceUserTeams{},
-	); err != nil {
-		return nil, fmt.Errorf("auto-migration failed: %w", err)
+	if err := Migrate(db); err != nil {
+		return nil, nil, err
+	}
+
+	if err := RegisterAuditCallbacks(db); err != nil {
+		return nil, nil, fmt.Errorf("failed to register audit callbacks: %w", err)
 	}
+	// Every write made by this run is audited against the bootstrap actor and one correlation id
+	correlationID := NewCorrelationID()
+	db = db.WithContext(WithCorrelationID(WithActor(ctx, "bootstrap"), correlationID))
+	log.Printf("bootstrap: audit correlation id %s", correlationID)
 
 	if !seed {
 		log.Println("bootstrap: database schema created but no seed data loaded")
-		return db, nil
+		return db, nil, nil
 	}
 
 	services := []model.Service{
@@ -84,119 +89,188 @@ func BootstrapSQLite(dbPath, spreadsheetID, readRange, worksheetCredentialsPath,
 		}
 		return nil
 	}); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if err := loadMaintainersAndProjects(db, spreadsheetID, readRange, worksheetCredentialsPath); err != nil {
-		return nil, fmt.Errorf("bootstrap: failed to load maintainers and projects: %w", err)
+	rows, err := readRows(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bootstrap: failed to read maintainers and projects: %w", err)
+	}
+	report, err := loadMaintainersAndProjects(db, rows, prune)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bootstrap: failed to load maintainers and projects: %w", err)
 	}
+	log.Printf("bootstrap: %s", report.Summary())
 
-	//fossaService := model.Service{Model: gorm.Model{ID: 1}, Name: "FOSSA"}
-	if err := loadFOSSA(db, fossaToken); err != nil {
-		return nil, fmt.Errorf("bootstrap: failed to load FOSSA projects: %w", err)
+	if fossaToken == "" {
+		log.Println("bootstrap: no FOSSA token, FOSSA users and teams not loaded")
+	} else if err := loadFOSSA(ctx, db, fossaToken); err != nil {
+		return nil, nil, fmt.Errorf("bootstrap: failed to load FOSSA projects: %w", err)
 	}
 
 	log.Printf("bootstrap: completed and loaded seed data into %s", dbPath)
-	return db, nil
+	return db, report, nil
 }
 
-// Reads the readRange data from spreadsheetID inserts it into db
-// The readRange from the worksheet MUST include the header row
-func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, readRange, credentialsPath string) error {
-	ctx := context.Background()
-
-	srv, err := sheets.NewService(
-		ctx,
-		option.WithCredentialsFile(credentialsPath),
-		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
-	)
+// Migrate creates or updates the maintainerd schema in db.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&model.Company{},
+		&model.Project{},
+		&model.ProjectRepo{},
+		&model.Maintainer{},
+		&model.Collaborator{},
+		&model.MaintainerProject{},
+		&model.Service{},
+		&model.ServiceTeam{},
+		&model.ServiceUser{},
+		&model.ServiceUserTeams{},
+		&model.AuditLog{},
+		&model.SheetSyncState{},
+		&model.FoundationOfficer{},
+		&model.APIToken{},
+		&model.ChangeRequest{},
+		&model.WebhookSubscription{},
+		&model.WebhookDelivery{},
+		&model.WebhookCursor{},
+	); err != nil {
+		return fmt.Errorf("auto-migration failed: %w", err)
+	}
+	return nil
+}
 
+// OpenSQLite opens the existing database at dbPath with auditing enabled. Databases created by an older maintainerd
+// are migrated, gaining tables such as audit_logs.
+func OpenSQLite(dbPath string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(dbPath))
 	if err != nil {
-		log.Fatalf("maintainerd: backend: loadMaintainersAndProjects: unable to retrieve Sheets client: %v", err)
+		return nil, fmt.Errorf("failed to open DB: %w", err)
 	}
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+	if err := RegisterAuditCallbacks(db); err != nil {
+		return nil, fmt.Errorf("failed to register audit callbacks: %w", err)
+	}
+	return db, nil
+}
 
-	rows, err := readSheetRows(ctx, srv, spreadsheetID, readRange)
-
+// OpenSQLiteReadOnly opens the existing database at dbPath for reading, for commands that only query it. Unlike
+// OpenSQLite it neither creates a missing database nor migrates an older one, and writes fail.
+func OpenSQLiteReadOnly(dbPath string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file:" + dbPath + "?mode=ro"))
 	if err != nil {
-		log.Fatalf("maintainerd-backend: loadMaintainersAndProjects - readSheetRows: %v", err)
+		return nil, fmt.Errorf("failed to open DB %s: %w", dbPath, err)
 	}
+	return db, nil
+}
 
-	var currentMaintainerRef string
-	var currentMailingList string
-
-	for _, row := range rows {
-		var missingMaintainerFields []string
+// Upserts the worksheet rows, as returned by a RowReader, into db and reports the rows that were skipped and the
+// problems found in the others. Problems with a maintainer's rows are stored in their ImportWarnings.
+//
+// Projects and maintainers already in db take the fields of their first row in the sheet, blank cells leave the
+// stored value alone. Maintainers are matched by email and, failing that, by GitHub account so that a changed email
+// updates the maintainer rather than adding another. Active maintainers and memberships that are no longer in the
+// sheet are reported and handled according to prune. Nothing is pruned when no row could be imported.
+func loadMaintainersAndProjects(db *gorm.DB, rows []map[string]string, prune PrunePolicy) (*ImportReport, error) {
+	report := newImportReport(len(rows))
+	warned := map[string][]string{} // maintainer email -> warnings from all of their rows
+	upserted := map[string]bool{}   // projects and maintainers whose fields were set by an earlier row
+	claimed := map[uint]string{}    // maintainer id -> the email it was matched by in this run
+	seen := newSheetSeen()
+
+	for i, row := range rows {
+		tab, rowNum := rowPosition(row, i+2) // the header is row 1
 		log.Printf("TRACE, reading row, %v\n", row)
-		name := row[MaintainerNameHdr]
-
-		if name == "" {
-			missingMaintainerFields = append(missingMaintainerFields, ":"+MaintainerNameHdr)
+		if blankRow(row) {
+			report.BlankRows++
+			continue
 		}
 
+		projectName := row[ProjectHdr]
+		name := row[MaintainerNameHdr]
 		company := row[CompanyNameHdr]
-		if company == "" {
-			missingMaintainerFields = append(missingMaintainerFields, ":"+CompanyNameHdr)
-		}
-
 		email := row[EmailHdr]
-		if email == "" {
-			missingMaintainerFields = append(missingMaintainerFields, ":"+EmailHdr)
-		}
-
 		github := row[GitHubHdr]
-		if github == "" {
-			missingMaintainerFields = append(missingMaintainerFields, ":"+GitHubHdr)
-		}
 		githubEmail := row[GitHubEmail]
-		if github == "" {
-			missingMaintainerFields = append(missingMaintainerFields, ":"+GitHubHdr)
+
+		var warnings []string
+		for _, field := range []struct{ hdr, value string }{
+			{MaintainerNameHdr, name},
+			{CompanyNameHdr, company},
+			{GitHubHdr, github},
+			{GitHubEmail, githubEmail},
+		} {
+			if field.value == "" {
+				warnings = append(warnings, "missing "+field.hdr)
+			}
+		}
+		if email != "" && !strings.Contains(email, "@") {
+			warnings = append(warnings, fmt.Sprintf("%s %q is not an email address", EmailHdr, email))
+		}
+		if githubEmail != "" && !strings.Contains(githubEmail, "@") {
+			warnings = append(warnings, fmt.Sprintf("%s %q is not an email address", GitHubEmail, githubEmail))
+		}
+
+		seen.add(email, projectName)
+
+		switch {
+		case projectName == "":
+			report.skip(tab, rowNum, row, "missing "+ProjectHdr)
+			continue
+		case email == "":
+			report.skip(tab, rowNum, row, "missing "+EmailHdr)
+			continue
 		}
 
 		var parent model.Project
 		if parentName := row[ParentProjectHdr]; parentName != "" {
-			parent = model.Project{}
-			if err := db.Where("name = ?", parentName).
-				First(&parent).Error; err != nil {
+			if err := db.Where("name = ?", parentName).First(&parent).Error; err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s %q is not registered, imported as a top level project", ParentProjectHdr, parentName))
+				parent = model.Project{}
 			}
 		}
-		currentMaintainerRef = row[MaintainerFileRefHdr]
-		currentMailingList = row[MailingListAddrHdr]
+		maturity := model.Maturity(row[StatusHdr])
+		if parent.Name != "" {
+			maturity = parent.Maturity
+		}
+		if !maturity.IsValid() {
+			report.skip(tab, rowNum, row, fmt.Sprintf("%s %q is not one of Sandbox, Incubating, Graduated or Archived", StatusHdr, row[StatusHdr]))
+			continue
+		}
+		// a blank status leaves a stored maintainer's alone, new maintainers are created Active
+		status := model.MaintainerStatus(row[MaintainerStatusHdr])
+		if status != "" && !status.IsValid() {
+			warnings = append(warnings, fmt.Sprintf("%s %q is not one of Active, Emeritus or Retired, imported as Active", MaintainerStatusHdr, status))
+			status = model.ActiveMaintainer
+		}
+		currentMaintainerRef := row[MaintainerFileRefHdr]
+		currentMailingList := row[MailingListAddrHdr]
 
+		warned[email] = appendUnique(warned[email], warnings...)
 		if err := db.Transaction(func(tx *gorm.DB) error {
-			var project model.Project
-			if parent.Name == "" {
-				project = model.Project{
-					Name:          row[ProjectHdr],
-					Maturity:      model.Maturity(row[StatusHdr]),
-					MaintainerRef: currentMaintainerRef,
-					MailingList:   &currentMailingList,
-				}
-			} else {
-				project = model.Project{
-					Name:            row[ProjectHdr],
-					Maturity:        parent.Maturity,
-					MaintainerRef:   currentMaintainerRef,
-					MailingList:     &currentMailingList,
-					ParentProjectID: &parent.ID,
-				}
+			project := model.Project{
+				Name:          projectName,
+				Maturity:      maturity,
+				MaintainerRef: currentMaintainerRef,
+				MailingList:   &currentMailingList,
 			}
-			if err := tx.FirstOrCreate(&project, model.Project{Name: project.Name}).Error; err != nil {
-				return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - failed calling FirstOrCreate on project %v: error %v", project, err)
+			if parent.Name != "" {
+				project.ParentProjectID = &parent.ID
 			}
-			company := model.Company{Name: company}
-			if err := tx.FirstOrCreate(&company, model.Company{Name: company.Name}).Error; err != nil {
-				return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - failed calling FirstOrCreate on company %v: error %v", company, err)
+			if err := upsertProject(tx, &project, !upserted["project:"+projectName]); err != nil {
+				return err
 			}
 			maintainer := model.Maintainer{
 				Name:             name,
 				GitHubAccount:    github,
 				GitHubEmail:      githubEmail,
 				Email:            email,
-				CompanyID:        &company.ID,
-				MaintainerStatus: model.ActiveMaintainer,
+				MaintainerStatus: status,
+				ImportWarnings:   strings.Join(warned[email], "; "),
 			}
-			if err := tx.FirstOrCreate(&maintainer, model.Maintainer{Email: maintainer.Email}).Error; err != nil {
-				return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - failed calling FirstOrCreate on maintainer %v: error %v", maintainer, err)
+			if err := upsertMaintainer(tx, &maintainer, company, claimed, !upserted["maintainer:"+email]); err != nil {
+				return err
 			}
 			// Ensure the association (in case the maintainer existed already)
 			return tx.Model(&maintainer).
@@ -204,14 +278,190 @@ func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, readRange, credentia
 				Append(&project)
 		}); err != nil {
 			log.Printf("TX not committed, row skipped %v : error %v ", row, err)
+			report.skip(tab, rowNum, row, err.Error())
+			continue
+		}
+		report.Imported++
+		upserted["project:"+projectName] = true
+		upserted["maintainer:"+email] = true
+		if len(warnings) > 0 {
+			report.Warnings = append(report.Warnings, RowWarning{Tab: tab, Row: rowNum, Project: projectName, Email: email, Warnings: warnings})
 		}
 	}
+
+	if report.Imported == 0 {
+		log.Println("bootstrap: no rows imported, nothing pruned")
+		return report, nil
+	}
+	if err := pruneMissing(db, seen, prune, report); err != nil {
+		return nil, fmt.Errorf("loadMaintainersAndProjects: prune: %w", err)
+	}
+	return report, nil
+}
+
+// upsertProject creates project, or finds it by name, setting project's ID. When update is set the stored project
+// takes project's maturity, parent, maintainer file and mailing list where they are not blank.
+func upsertProject(tx *gorm.DB, project *model.Project, update bool) error {
+	var existing model.Project
+	err := tx.Where("name = ?", project.Name).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := tx.Create(project).Error; err != nil {
+			return fmt.Errorf("maintainerd-backend: upsertProject - failed to create project %s: error %v", project.Name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("maintainerd-backend: upsertProject - failed to read project %s: error %v", project.Name, err)
+	}
+
+	changes := map[string]any{}
+	if update {
+		if existing.Maturity != project.Maturity {
+			changes["maturity"] = project.Maturity
+		}
+		if project.ParentProjectID != nil && (existing.ParentProjectID == nil || *existing.ParentProjectID != *project.ParentProjectID) {
+			changes["parent_project_id"] = *project.ParentProjectID
+		}
+		if project.MaintainerRef != "" && existing.MaintainerRef != project.MaintainerRef {
+			changes["maintainer_ref"] = project.MaintainerRef
+		}
+		if ml := *project.MailingList; ml != "" && (existing.MailingList == nil || *existing.MailingList != ml) {
+			changes["mailing_list"] = ml
+		}
+	}
+	if len(changes) > 0 {
+		if err := tx.Model(&existing).Updates(changes).Error; err != nil {
+			return fmt.Errorf("maintainerd-backend: upsertProject - failed to update project %s: error %v", project.Name, err)
+		}
+	}
+	*project = existing
+	return nil
+}
+
+// upsertMaintainer creates maintainer, or finds it by email or by GitHub account, setting maintainer's ID. A
+// maintainer already claimed by another email in this run is not matched by GitHub account. When update is set the
+// stored maintainer takes maintainer's fields and company where they are not blank, its ImportWarnings are always
+// brought up to date. A maintainer created without a status is Active.
+func upsertMaintainer(tx *gorm.DB, maintainer *model.Maintainer, companyName string, claimed map[uint]string, update bool) error {
+	var companyID *uint
+	if companyName != "" {
+		company := model.Company{Name: companyName}
+		if err := tx.FirstOrCreate(&company, model.Company{Name: company.Name}).Error; err != nil {
+			return fmt.Errorf("maintainerd-backend: upsertMaintainer - failed calling FirstOrCreate on company %v: error %v", company, err)
+		}
+		companyID = &company.ID
+	}
+
+	var existing model.Maintainer
+	err := tx.Where("email = ?", maintainer.Email).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && maintainer.GitHubAccount != "" {
+		err = tx.Where("git_hub_account = ?", maintainer.GitHubAccount).First(&existing).Error
+		if err == nil {
+			if email, ok := claimed[existing.ID]; ok && email != maintainer.Email {
+				err = gorm.ErrRecordNotFound
+			}
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		maintainer.CompanyID = companyID
+		if maintainer.MaintainerStatus == "" {
+			maintainer.MaintainerStatus = model.ActiveMaintainer
+		}
+		if err := tx.Create(maintainer).Error; err != nil {
+			return fmt.Errorf("maintainerd-backend: upsertMaintainer - failed to create maintainer %s: error %v", maintainer.Email, err)
+		}
+		claimed[maintainer.ID] = maintainer.Email
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("maintainerd-backend: upsertMaintainer - failed to read maintainer %s: error %v", maintainer.Email, err)
+	}
+
+	changes := map[string]any{}
+	if existing.ImportWarnings != maintainer.ImportWarnings {
+		changes["import_warnings"] = maintainer.ImportWarnings
+	}
+	if update {
+		for _, f := range []struct {
+			column, from, to string
+		}{
+			{"name", existing.Name, maintainer.Name},
+			{"email", existing.Email, maintainer.Email},
+			{"git_hub_account", existing.GitHubAccount, maintainer.GitHubAccount},
+			{"git_hub_email", existing.GitHubEmail, maintainer.GitHubEmail},
+			{"maintainer_status", string(existing.MaintainerStatus), string(maintainer.MaintainerStatus)},
+		} {
+			if f.to != "" && f.from != f.to {
+				changes[f.column] = f.to
+			}
+		}
+		if companyID != nil && (existing.CompanyID == nil || *existing.CompanyID != *companyID) {
+			changes["company_id"] = *companyID
+		}
+	}
+	if len(changes) > 0 {
+		if err := tx.Model(&existing).Updates(changes).Error; err != nil {
+			return fmt.Errorf("maintainerd-backend: upsertMaintainer - failed to update maintainer %s: error %v", maintainer.Email, err)
+		}
+	}
+	claimed[existing.ID] = existing.Email
+	*maintainer = existing
 	return nil
 }
 
-// readSheetRows returns every row as a map keyed by the header row and carries forward the last non‐empty Project and
-// Status values when those cells are blank or missing.
-func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, readRange string) ([]map[string]string, error) {
+// blankRow reports whether every cell of row is empty apart from the carried forward Project and Status and the values
+// added by a SheetMapping.
+func blankRow(row map[string]string) bool {
+	for h, v := range row {
+		switch h {
+		case ProjectHdr, StatusHdr, MaintainerStatusHdr, SheetTabKey, SheetRowKey:
+			continue
+		}
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// ReadWorksheet reads every tab of mapping, each of whose ranges MUST include the header row, from spreadsheetID using
+// the Google service account in credentialsPath and returns their rows keyed by maintainerd's header constants.
+func ReadWorksheet(ctx context.Context, spreadsheetID, credentialsPath string, mapping *SheetMapping) ([]map[string]string, error) {
+	srv, err := sheets.NewService(
+		ctx,
+		option.WithCredentialsFile(credentialsPath),
+		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ReadWorksheet: unable to retrieve Sheets client: %w", err)
+	}
+	var rows []map[string]string
+	for _, tab := range mapping.Tabs {
+		readRange := tab.A1Range()
+		values, err := readSheetValues(ctx, srv, spreadsheetID, readRange)
+		if err != nil {
+			return nil, fmt.Errorf("ReadWorksheet: %w", err)
+		}
+		_, headerRow, err := rangeStart(readRange)
+		if err != nil {
+			return nil, fmt.Errorf("ReadWorksheet: %w", err)
+		}
+		rows = append(rows, mapping.tabRows(tab, headerRow, values)...)
+	}
+	return rows, nil
+}
+
+// readSheetValues returns the cells of readRange as strings.
+func readSheetValues(ctx context.Context, srv *sheets.Service, spreadsheetID, readRange string) ([][]string, error) {
 	resp, err := srv.Spreadsheets.Values.
 		Get(spreadsheetID, readRange).
 		Context(ctx).
@@ -223,19 +473,42 @@ func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, read
 		return nil, fmt.Errorf("db: %s:%s worksheet is empty", spreadsheetID, readRange)
 	}
 
+	return sheetValues(resp.Values), nil
+}
+
+// sheetValues returns the cells returned by the Sheets API as strings.
+func sheetValues(cells [][]interface{}) [][]string {
+	values := make([][]string, len(cells))
+	for i, r := range cells {
+		values[i] = make([]string, len(r))
+		for j, cell := range r {
+			values[i][j] = fmt.Sprint(cell)
+		}
+	}
+	return values
+}
+
+// tableRows turns values, whose first row is the header row, into maps keyed by header. The last non‐empty Project
+// and Status values are carried forward when those cells are blank or missing, as the worksheet only names a project
+// on the first of its maintainers' rows.
+func tableRows(values [][]string) []map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
 	// First row → headers
-	headers := make([]string, len(resp.Values[0]))
-	for i, cell := range resp.Values[0] {
-		headers[i] = strings.TrimSpace(fmt.Sprint(cell))
+	headers := make([]string, len(values[0]))
+	for i, cell := range values[0] {
+		headers[i] = strings.TrimSpace(cell)
 	}
 
 	// Find the column indexes for "Project" and "Status"
 	projIdx, statIdx := -1, -1
 	for i, h := range headers {
 		switch h {
-		case "Project":
+		case ProjectHdr:
 			projIdx = i
-		case "Status":
+		case StatusHdr:
 			statIdx = i
 		}
 	}
@@ -244,14 +517,14 @@ func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, read
 	var lastProject, lastStatus string
 
 	// Remaining rows → maps
-	for _, r := range resp.Values[1:] {
+	for _, r := range values[1:] {
 		rowMap := make(map[string]string, len(headers))
 
 		for i, h := range headers {
 			// read raw cell if present
 			var cellVal string
 			if i < len(r) {
-				cellVal = strings.TrimSpace(fmt.Sprint(r[i]))
+				cellVal = strings.TrimSpace(r[i])
 			}
 
 			switch i {
@@ -275,12 +548,12 @@ func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, read
 		rows = append(rows, rowMap)
 	}
 
-	return rows, nil
+	return rows
 }
 
 // loadFOSSA synchronizes all data in CNCF FOSSA
-func loadFOSSA(db *gorm.DB, token string) error {
-	users, teams, err := FetchFossaData(token)
+func loadFOSSA(ctx context.Context, db *gorm.DB, token string) error {
+	users, teams, err := FetchFossaData(ctx, token)
 	if err != nil {
 		return fmt.Errorf("loadFOSSA: fetching FOSSA data: %s", err)
 	}
@@ -305,7 +578,7 @@ func loadFOSSA(db *gorm.DB, token string) error {
 				log.Printf("ERR, MapFossaUserCollaborator: error mapping service user using %s: %v", user.Email, err)
 			}
 		}
-		st, err := CreateServiceTeamsForUser(db, user.TeamUsers)
+		st, err := CreateServiceTeamsForUser(ctx, db, user.TeamUsers)
 		if err != nil {
 			log.Printf("ERR, CreateServiceTeamsForUser failed for user %d (%s): %v", user.ID, user.Email, err)
 			continue
@@ -321,6 +594,7 @@ func loadFOSSA(db *gorm.DB, token string) error {
 
 // CreateServiceTeamsForUser takes a @db connection, and an array of FOSSA TeamUsers and adds them to the DB.
 func CreateServiceTeamsForUser(
+	ctx context.Context,
 	db *gorm.DB,
 	teamUsers []struct {
 		RoleID int `json:"roleId"`
@@ -334,7 +608,7 @@ func CreateServiceTeamsForUser(
 	var teams []*model.ServiceTeam
 	var errMessages []string
 	s := NewSQLStore(db)
-	projects, _ := s.GetProjectMapByName()
+	projects, _ := s.GetProjectMapByName(ctx)
 
 	for _, team := range teamUsers {
 		if project, ok := projects[team.Team.Name]; ok {
@@ -596,15 +870,15 @@ func MapFossaUserToMaintainerOrCollaborator(db *gorm.DB, user fossa.User) (model
 	return m, c, nil
 }
 
-func FetchFossaData(token string) ([]fossa.User, []fossa.Team, interface{}) {
+func FetchFossaData(ctx context.Context, token string) ([]fossa.User, []fossa.Team, interface{}) {
 	fossaClient := fossa.NewClient(token)
 
-	users, err := fossaClient.FetchUsers()
+	users, err := fossaClient.FetchUsers(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	teams, err := fossaClient.FetchTeams()
+	teams, err := fossaClient.FetchTeams(ctx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"maintainerd/model"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLoadMaintainersAndProjectsReport(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:loader?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+
+	rows := tableRows([][]string{
+		{StatusHdr, ProjectHdr, ParentProjectHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr, GitHubEmail},
+		{"Graduated", "Kubernetes", "", "Jane Doe", "Example Inc", "jane@example.org", "janedoe", "jane@users.example.org"},
+		{"", "", "", "John Roe", "Example Inc", "john@example.org", "johnroe", ""},
+		{"", "", "", "No Email", "Example Inc", "", "noemail", ""},
+		{"", "", "", "", "", "", "", ""},
+		{"Unknown", "Mystery", "", "Ada Lovelace", "", "ada@example.org", "ada", ""},
+		{"Sandbox", "kubectl", "Missing Parent", "Jane Doe", "Example Inc", "jane@example.org", "janedoe", "not-an-email"},
+	})
+
+	report, err := loadMaintainersAndProjects(conn, rows, PruneNone)
+	require.NoError(t, err)
+	require.Equal(t, 6, report.Rows)
+	require.Equal(t, 3, report.Imported)
+	require.Equal(t, 1, report.BlankRows)
+	require.Equal(t, []SkippedRow{
+		{Row: 4, Project: "Kubernetes", Reason: "missing " + EmailHdr},
+		{Row: 6, Project: "Mystery", Email: "ada@example.org", Reason: `Status "Unknown" is not one of Sandbox, Incubating, Graduated or Archived`},
+	}, report.Skipped)
+	require.Equal(t, []RowWarning{
+		{Row: 3, Project: "Kubernetes", Email: "john@example.org", Warnings: []string{"missing " + GitHubEmail}},
+		{Row: 7, Project: "kubectl", Email: "jane@example.org", Warnings: []string{
+			GitHubEmail + ` "not-an-email" is not an email address`,
+			ParentProjectHdr + ` "Missing Parent" is not registered, imported as a top level project`,
+		}},
+	}, report.Warnings)
+
+	var john, jane model.Maintainer
+	require.NoError(t, conn.Where("email = ?", "john@example.org").First(&john).Error)
+	require.Equal(t, "missing "+GitHubEmail, john.ImportWarnings)
+	require.NoError(t, conn.Where("email = ?", "jane@example.org").First(&jane).Error)
+	require.Contains(t, jane.ImportWarnings, "not-an-email")
+}
+
+func TestLoadMaintainersAndProjectsUpsertAndPrune(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:reseed?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, RegisterAuditCallbacks(conn))
+
+	header := []string{StatusHdr, ProjectHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr}
+	_, err = loadMaintainersAndProjects(conn, tableRows([][]string{
+		header,
+		{"Sandbox", "Jaeger", "Jane Doe", "Example Inc", "jane@example.org", "janedoe"},
+		{"", "", "John Roe", "Example Inc", "john@example.org", "johnroe"},
+		{"Graduated", "Kubernetes", "John Roe", "Example Inc", "john@example.org", "johnroe"},
+		{"", "", "Ada Lovelace", "", "ada@example.org", "ada"},
+	}), PruneNone)
+	require.NoError(t, err)
+
+	// Jaeger graduates, Jane moves company and email, John leaves Jaeger and Ada leaves the sheet
+	reseed := tableRows([][]string{
+		header,
+		{"Graduated", "Jaeger", "Jane Doe", "Other Corp", "jane@other.example", "janedoe"},
+		{"Graduated", "Kubernetes", "John Roe", "Example Inc", "john@example.org", "johnroe"},
+	})
+	report, err := loadMaintainersAndProjects(conn, reseed, PruneNone)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ada@example.org"}, report.Disappeared)
+	require.Equal(t, []string{membershipKey("ada@example.org", "Kubernetes"), membershipKey("john@example.org", "Jaeger")}, report.StaleMemberships)
+
+	var jane model.Maintainer
+	require.NoError(t, conn.Preload("Company").Where("git_hub_account = ?", "janedoe").First(&jane).Error)
+	require.Equal(t, "jane@other.example", jane.Email)
+	require.Equal(t, "Other Corp", jane.Company.Name)
+	var count int64
+	require.NoError(t, conn.Model(&model.Maintainer{}).Where("git_hub_account = ?", "janedoe").Count(&count).Error)
+	require.EqualValues(t, 1, count)
+	var jaeger model.Project
+	require.NoError(t, conn.Where("name = ?", "Jaeger").First(&jaeger).Error)
+	require.Equal(t, model.Graduated, jaeger.Maturity)
+
+	report, err = loadMaintainersAndProjects(conn, reseed, PruneEmeritus)
+	require.NoError(t, err)
+	var ada model.Maintainer
+	require.NoError(t, conn.Preload("Projects").Where("email = ?", "ada@example.org").First(&ada).Error)
+	require.Equal(t, model.EmeritusMaintainer, ada.MaintainerStatus)
+	require.Len(t, ada.Projects, 1)
+
+	report, err = loadMaintainersAndProjects(conn, reseed, PruneMemberships)
+	require.NoError(t, err)
+	require.Empty(t, report.Disappeared, "Emeritus maintainers are not reported again")
+	var john model.Maintainer
+	require.NoError(t, conn.Preload("Projects").Where("email = ?", "john@example.org").First(&john).Error)
+	require.Len(t, john.Projects, 1)
+	require.Equal(t, "Kubernetes", john.Projects[0].Name)
+
+	for _, action := range []string{"UPDATE_MAINTAINERS", "UPDATE_PROJECTS", "DELETE_MAINTAINER_PROJECTS"} {
+		require.NoError(t, conn.Model(&model.AuditLog{}).Where("action = ?", action).Count(&count).Error)
+		require.NotZero(t, count, action)
+	}
+}
+
+func TestLoadMaintainersAndProjectsKeepsStatus(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:status?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+
+	rows := tableRows([][]string{
+		{StatusHdr, ProjectHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr, MaintainerStatusHdr},
+		{"Graduated", "Jaeger", "Jane Doe", "Example Inc", "jane@example.org", "janedoe", ""},
+		{"", "", "John Roe", "Example Inc", "john@example.org", "johnroe", "Retired"},
+	})
+	_, err = loadMaintainersAndProjects(conn, rows, PruneNone)
+	require.NoError(t, err)
+	store := NewSQLStore(conn)
+	ctx := context.Background()
+	jane, err := store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+	require.NoError(t, err)
+	require.Equal(t, model.ActiveMaintainer, jane.MaintainerStatus, "created Active when the cell is blank")
+
+	john, err := store.GetMaintainerByGitHubAccount(ctx, "johnroe")
+	require.NoError(t, err)
+	require.NoError(t, store.SetMaintainerStatus(ctx, jane.ID, model.EmeritusMaintainer))
+	require.NoError(t, store.SetMaintainerStatus(ctx, john.ID, model.ActiveMaintainer))
+	_, err = loadMaintainersAndProjects(conn, rows, PruneNone)
+	require.NoError(t, err)
+	jane, err = store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+	require.NoError(t, err)
+	require.Equal(t, model.EmeritusMaintainer, jane.MaintainerStatus, "a blank cell leaves the status alone")
+	john, err = store.GetMaintainerByGitHubAccount(ctx, "johnroe")
+	require.NoError(t, err)
+	require.Equal(t, model.RetiredMaintainer, john.MaintainerStatus, "a status in the sheet is taken")
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrProjectNotFound is returned when a project is not registered in maintainerd.
+var ErrProjectNotFound = errors.New("project not found")
+
+// ErrMaintainerNotFound is returned when a maintainer is not registered in maintainerd.
+var ErrMaintainerNotFound = errors.New("maintainer not found")
+
+const (
+	DefaultProjectCacheTTL = 5 * time.Minute
+
+	// missRefreshInterval limits how often a lookup for an unknown project may reload the cache.
+	missRefreshInterval = 30 * time.Second
+)
+
+// ProjectCache holds every Project with its Maintainers in memory, keyed by project name. Entries are reloaded from
+// the Store once they are older than the TTL, when a change to projects or maintainers is made through a database the
+// cache is watching, or when Refresh is called. A lookup for an unknown name also triggers a reload so that projects
+// added by another process, such as a bootstrap run, become visible without a restart.
+type ProjectCache struct {
+	store Store
+	ttl   time.Duration
+
+	mu          sync.RWMutex
+	projects    map[string]model.ProjectInfo
+	loadedAt    time.Time
+	invalidated bool
+	generation  uint64 // counts invalidations, so that a refresh can tell whether one happened while it was reading
+}
+
+// NewProjectCache returns an empty cache over store, the first lookup loads it. A ttl of zero uses
+// DefaultProjectCacheTTL.
+func NewProjectCache(store Store, ttl time.Duration) *ProjectCache {
+	if ttl <= 0 {
+		ttl = DefaultProjectCacheTTL
+	}
+	return &ProjectCache{store: store, ttl: ttl}
+}
+
+// Get returns the ProjectInfo for the project called name or ErrProjectNotFound.
+func (c *ProjectCache) Get(ctx context.Context, name string) (model.ProjectInfo, error) {
+	if c.expired() {
+		if err := c.Refresh(ctx); err != nil {
+			return model.ProjectInfo{}, err
+		}
+	}
+	if info, ok := c.lookup(name); ok {
+		return info, nil
+	}
+
+	// The project may have been added since we last loaded
+	c.mu.RLock()
+	recent := time.Since(c.loadedAt) < missRefreshInterval
+	c.mu.RUnlock()
+	if !recent {
+		if err := c.Refresh(ctx); err != nil {
+			return model.ProjectInfo{}, err
+		}
+		if info, ok := c.lookup(name); ok {
+			return info, nil
+		}
+	}
+	return model.ProjectInfo{}, fmt.Errorf("%w: %q", ErrProjectNotFound, name)
+}
+
+// Refresh reloads every project from the Store. The cache stays stale if it was invalidated during the reload, as
+// what was read may predate the change.
+func (c *ProjectCache) Refresh(ctx context.Context) error {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	byID, err := c.store.GetProjectMaintainersMap(ctx)
+	if err != nil {
+		return fmt.Errorf("ProjectCache: refresh: %w", err)
+	}
+	byName := make(map[string]model.ProjectInfo, len(byID))
+	for _, info := range byID {
+		byName[info.Project.Name] = info
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.projects = byName
+	c.loadedAt = time.Now()
+	c.invalidated = c.generation != generation
+	return nil
+}
+
+// Invalidate marks the cache as stale so that the next lookup reloads it.
+func (c *ProjectCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidated = true
+	c.generation++
+}
+
+// InvalidateOn registers callbacks on db that invalidate the cache whenever projects, maintainers or their
+// memberships are written through it.
+func (c *ProjectCache) InvalidateOn(db *gorm.DB) error {
+	invalidate := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Schema == nil {
+			return
+		}
+		switch tx.Statement.Schema.Table {
+		case "projects", "maintainers", "maintainer_projects":
+			c.Invalidate()
+		}
+	}
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("maintainerd:project_cache_create", invalidate); err != nil {
+		return fmt.Errorf("ProjectCache: InvalidateOn: %w", err)
+	}
+	if err := cb.Update().After("gorm:update").Register("maintainerd:project_cache_update", invalidate); err != nil {
+		return fmt.Errorf("ProjectCache: InvalidateOn: %w", err)
+	}
+	if err := cb.Delete().After("gorm:delete").Register("maintainerd:project_cache_delete", invalidate); err != nil {
+		return fmt.Errorf("ProjectCache: InvalidateOn: %w", err)
+	}
+	return nil
+}
+
+// Projects returns a copy of the cached projects keyed by name, loading them if needed.
+func (c *ProjectCache) Projects(ctx context.Context) (map[string]model.ProjectInfo, error) {
+	if c.expired() {
+		if err := c.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	projects := make(map[string]model.ProjectInfo, len(c.projects))
+	for name, info := range c.projects {
+		projects[name] = info
+	}
+	return projects, nil
+}
+
+// LoadedAt returns when the cache was last loaded, the zero time if it never has been.
+func (c *ProjectCache) LoadedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loadedAt
+}
+
+func (c *ProjectCache) expired() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.projects == nil || c.invalidated || time.Since(c.loadedAt) > c.ttl
+}
+
+func (c *ProjectCache) lookup(name string) (model.ProjectInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info, ok := c.projects[name]
+	return info, ok
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	store, conn := newSeededSQLStore(t, "cache")
+	cache := NewProjectCache(store, 0)
+	require.NoError(t, cache.InvalidateOn(conn))
+
+	kubernetes, err := cache.Get(ctx, "Kubernetes")
+	require.NoError(t, err)
+	require.Len(t, kubernetes.Maintainers, 1)
+	require.Len(t, kubernetes.Services, 1, "projects use the services they have a team on")
+	require.Equal(t, "FOSSA", kubernetes.Services[0].Name)
+	jaeger, err := cache.Get(ctx, "Jaeger")
+	require.NoError(t, err)
+	require.Empty(t, jaeger.Services)
+
+	// a write through the watched database is seen by the next lookup, well before the TTL
+	_, err = store.AddMaintainerToProject(ctx, kubernetes.Project.ID, model.Maintainer{Name: "Grace Hopper", Email: "grace@example.org", GitHubAccount: "grace"}, "")
+	require.NoError(t, err)
+	kubernetes, err = cache.Get(ctx, "Kubernetes")
+	require.NoError(t, err)
+	require.Len(t, kubernetes.Maintainers, 2)
+}
+
+// racingStore invalidates the cache while a refresh is reading, as a write committed just after the read would.
+type racingStore struct {
+	Store
+	cache *ProjectCache
+	reads int
+}
+
+func (s *racingStore) GetProjectMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
+	s.reads++
+	projects, err := s.Store.GetProjectMaintainersMap(ctx)
+	if s.reads == 1 {
+		s.cache.Invalidate()
+	}
+	return projects, err
+}
+
+func TestProjectCacheInvalidateDuringRefresh(t *testing.T) {
+	ctx := context.Background()
+	store := &racingStore{Store: newTestMemoryStore(t)}
+	cache := NewProjectCache(store, 0)
+	store.cache = cache
+
+	require.NoError(t, cache.Refresh(ctx))
+	require.True(t, cache.expired(), "the invalidation is not lost to the refresh it raced")
+	_, err := cache.Get(ctx, "Jaeger")
+	require.NoError(t, err)
+	require.Equal(t, 2, store.reads)
+	require.False(t, cache.expired())
+	_, err = cache.Get(ctx, "Jaeger")
+	require.NoError(t, err)
+	require.Equal(t, 2, store.reads, "a clean cache is not reloaded")
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+	"net/mail"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrChangeRequestNotFound is returned for a change request id that does not exist.
+	ErrChangeRequestNotFound = errors.New("change request not found")
+	// ErrChangeRequestReviewed is returned when reviewing a change request that was already approved or rejected.
+	ErrChangeRequestReviewed = errors.New("change request was already reviewed")
+	// ErrEmailInUse is returned when approving an email change to the address of another maintainer.
+	ErrEmailInUse = errors.New("email address belongs to another maintainer")
+)
+
+// ChangeRequestFilter narrows the change requests returned by ListChangeRequests, zero valued fields are not used to
+// filter.
+type ChangeRequestFilter struct {
+	MaintainerID *uint
+	Status       model.ChangeRequestStatus
+	Limit        int
+}
+
+// validateChangeRequest checks req before it is queued, trimming its value.
+func validateChangeRequest(req *model.ChangeRequest) error {
+	req.Value = strings.TrimSpace(req.Value)
+	switch req.Kind {
+	case model.ChangeEmail:
+		addr, err := mail.ParseAddress(req.Value)
+		if err != nil || addr.Address != req.Value {
+			return fmt.Errorf("%q is not an email address", req.Value)
+		}
+	case model.ChangeCompany:
+		if req.Value == "" {
+			return errors.New("a company name is required")
+		}
+	case model.ChangeServiceAccess:
+		if req.ProjectID == nil || req.ServiceID == nil {
+			return errors.New("a project and a service are required")
+		}
+		req.Value = ""
+	default:
+		return fmt.Errorf("unknown change request kind %q", req.Kind)
+	}
+	return nil
+}
+
+// sameChange reports whether a and b ask for the same change to the same maintainer.
+func sameChange(a, b model.ChangeRequest) bool {
+	eq := func(x, y *uint) bool { return (x == nil && y == nil) || (x != nil && y != nil && *x == *y) }
+	return a.MaintainerID == b.MaintainerID && a.Kind == b.Kind && a.Value == b.Value &&
+		eq(a.ProjectID, b.ProjectID) && eq(a.ServiceID, b.ServiceID)
+}
+
+// RequestChange queues req for a foundation officer to review. Service access can only be asked for on projects the
+// maintainer maintains. A change the maintainer already has pending is returned rather than queued twice.
+func (s *SQLStore) RequestChange(ctx context.Context, req model.ChangeRequest) (*model.ChangeRequest, error) {
+	if err := validateChangeRequest(&req); err != nil {
+		return nil, fmt.Errorf("RequestChange: %w", err)
+	}
+	req.Status = model.ChangePending
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maintainer model.Maintainer
+		if err := tx.First(&maintainer, req.MaintainerID).Error; err != nil {
+			return fmt.Errorf("%w: id %d", ErrMaintainerNotFound, req.MaintainerID)
+		}
+		if req.Kind == model.ChangeServiceAccess {
+			var count int64
+			if err := tx.Model(&model.MaintainerProject{}).
+				Where("maintainer_id = ? AND project_id = ?", req.MaintainerID, *req.ProjectID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrNotProjectMaintainer
+			}
+			if err := tx.First(&model.Service{}, *req.ServiceID).Error; err != nil {
+				return fmt.Errorf("service id %d: %w", *req.ServiceID, err)
+			}
+		}
+
+		var pending []model.ChangeRequest
+		if err := tx.Where("maintainer_id = ? AND kind = ? AND status = ?", req.MaintainerID, req.Kind, model.ChangePending).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+		for _, p := range pending {
+			if sameChange(p, req) {
+				req = p
+				return nil
+			}
+		}
+		return tx.Create(&req).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("RequestChange: %w", err)
+	}
+	return &req, nil
+}
+
+// ListChangeRequests returns the change requests that match filter with their maintainers, newest first.
+func (s *SQLStore) ListChangeRequests(ctx context.Context, filter ChangeRequestFilter) ([]model.ChangeRequest, error) {
+	q := s.db.WithContext(ctx).Preload("Maintainer").Order("id DESC")
+	if filter.MaintainerID != nil {
+		q = q.Where("maintainer_id = ?", *filter.MaintainerID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var requests []model.ChangeRequest
+	if err := q.Find(&requests).Error; err != nil {
+		return nil, fmt.Errorf("ListChangeRequests: %w", err)
+	}
+	return requests, nil
+}
+
+// ReviewChangeRequest approves or rejects the pending change request identified by id, recording the actor found in
+// ctx as the reviewer and note as the reason. Approving an email or company change applies it to the maintainer, an
+// email address another maintainer has is refused with ErrEmailInUse and the request left pending. Approving service
+// access only records the approval, onboarding the project is left to the caller.
+func (s *SQLStore) ReviewChangeRequest(ctx context.Context, id uint, approve bool, note string) (*model.ChangeRequest, error) {
+	var req model.ChangeRequest
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Maintainer").First(&req, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: id %d", ErrChangeRequestNotFound, id)
+			}
+			return err
+		}
+		if req.Status != model.ChangePending {
+			return fmt.Errorf("%w: id %d is %s", ErrChangeRequestReviewed, id, req.Status)
+		}
+		status := model.ChangeRejected
+		if approve {
+			status = model.ChangeApproved
+			switch req.Kind {
+			case model.ChangeEmail:
+				var count int64
+				if err := tx.Model(&model.Maintainer{}).
+					Where("LOWER(email) = LOWER(?) AND id <> ?", req.Value, req.MaintainerID).
+					Count(&count).Error; err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("%w: %s", ErrEmailInUse, req.Value)
+				}
+				if err := tx.Model(&req.Maintainer).Update("email", req.Value).Error; err != nil {
+					return err
+				}
+			case model.ChangeCompany:
+				if err := setMaintainerCompany(tx, &req.Maintainer, req.Value); err != nil {
+					return err
+				}
+			}
+		}
+		now := time.Now()
+		return tx.Model(&req).Updates(model.ChangeRequest{
+			Status:     status,
+			ReviewedBy: ActorFromContext(ctx),
+			ReviewedAt: &now,
+			Note:       note,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ReviewChangeRequest: %w", err)
+	}
+	return &req, nil
+}
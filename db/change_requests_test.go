@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRequests(t *testing.T) {
+	sqlStore, _ := newSeededSQLStore(t, "change_requests")
+	stores := map[string]Store{
+		"sql":    sqlStore,
+		"memory": newTestMemoryStore(t),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithActor(context.Background(), "janedoe")
+			jane, err := store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+			require.NoError(t, err)
+			projects, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			fossa, err := store.GetServiceByName(ctx, "FOSSA")
+			require.NoError(t, err)
+			kubernetes, jaeger := projects["Kubernetes"].ID, projects["Jaeger"].ID
+
+			_, err = store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeEmail, Value: "not an email"})
+			require.Error(t, err)
+			_, err = store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: "nickname", Value: "JD"})
+			require.Error(t, err)
+			_, err = store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeServiceAccess, ProjectID: &jaeger, ServiceID: &fossa.ID})
+			require.ErrorIs(t, err, ErrNotProjectMaintainer)
+
+			email, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeEmail, Value: " jane@new.example.org "})
+			require.NoError(t, err)
+			require.Equal(t, model.ChangePending, email.Status)
+			again, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeEmail, Value: "jane@new.example.org"})
+			require.NoError(t, err)
+			require.Equal(t, email.ID, again.ID)
+			company, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeCompany, Value: "New Co"})
+			require.NoError(t, err)
+			access, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeServiceAccess, ProjectID: &kubernetes, ServiceID: &fossa.ID})
+			require.NoError(t, err)
+
+			pending, err := store.ListChangeRequests(ctx, ChangeRequestFilter{Status: model.ChangePending})
+			require.NoError(t, err)
+			require.Len(t, pending, 3)
+			require.Equal(t, access.ID, pending[0].ID)
+			require.Equal(t, "Jane Doe", pending[0].Maintainer.Name)
+
+			officer := WithActor(context.Background(), "octocat")
+			taken, err := store.RequestChange(ctx, model.ChangeRequest{MaintainerID: jane.ID, Kind: model.ChangeEmail, Value: "John@Example.org"})
+			require.NoError(t, err)
+			_, err = store.ReviewChangeRequest(officer, taken.ID, true, "")
+			require.ErrorIs(t, err, ErrEmailInUse)
+			_, err = store.ReviewChangeRequest(officer, taken.ID, false, "that is John's address")
+			require.NoError(t, err, "a refused approval leaves the request pending")
+
+			approved, err := store.ReviewChangeRequest(officer, email.ID, true, "")
+			require.NoError(t, err)
+			require.Equal(t, model.ChangeApproved, approved.Status)
+			require.Equal(t, "octocat", approved.ReviewedBy)
+			require.NotNil(t, approved.ReviewedAt)
+			_, err = store.ReviewChangeRequest(officer, email.ID, false, "")
+			require.ErrorIs(t, err, ErrChangeRequestReviewed)
+			_, err = store.ReviewChangeRequest(officer, 999, true, "")
+			require.ErrorIs(t, err, ErrChangeRequestNotFound)
+
+			rejected, err := store.ReviewChangeRequest(officer, company.ID, false, "please use your employer's legal name")
+			require.NoError(t, err)
+			require.Equal(t, model.ChangeRejected, rejected.Status)
+			_, err = store.ReviewChangeRequest(officer, access.ID, true, "")
+			require.NoError(t, err)
+
+			jane, err = store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+			require.NoError(t, err)
+			require.Equal(t, "jane@new.example.org", jane.Email)
+			require.Equal(t, "Example Inc", jane.Company.Name)
+
+			mine, err := store.ListChangeRequests(ctx, ChangeRequestFilter{MaintainerID: &jane.ID, Limit: 2})
+			require.NoError(t, err)
+			require.Len(t, mine, 2)
+			john, err := store.GetMaintainerByGitHubAccount(ctx, "johnroe")
+			require.NoError(t, err)
+			require.Equal(t, "john@example.org", john.Email)
+			pending, err = store.ListChangeRequests(ctx, ChangeRequestFilter{Status: model.ChangePending})
+			require.NoError(t, err)
+			require.Empty(t, pending)
+
+			entries, err := store.ListAuditLogs(ctx, AuditFilter{Actor: "octocat", MaintainerID: &jane.ID})
+			require.NoError(t, err)
+			actions := map[string]bool{}
+			for _, e := range entries {
+				actions[e.Action] = true
+			}
+			require.True(t, actions["UPDATE_CHANGE_REQUESTS"])
+			require.True(t, actions["UPDATE_MAINTAINERS"])
+		})
+	}
+}
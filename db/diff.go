@@ -0,0 +1,283 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maintainerd/model"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// SheetDiff is the difference between the maintainers, projects, companies and memberships in worksheet rows and
+// those in the database. It describes what a re-seed would need to do to make the database match the sheet.
+type SheetDiff struct {
+	Maintainers EntityDiff `json:"maintainers"`
+	Projects    EntityDiff `json:"projects"`
+	Companies   EntityDiff `json:"companies"`
+	Memberships EntityDiff `json:"memberships"`
+	// SkippedRows counts rows without a project or an email, which cannot be matched to anything
+	SkippedRows int `json:"skipped_rows"`
+}
+
+// EntityDiff lists the keys of records only in the sheet (Added), only in the database (Removed) and the fields of
+// records in both whose values differ (Changed). Maintainers are keyed by email, projects and companies by name and
+// memberships by "email → project".
+type EntityDiff struct {
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+	Changed []FieldChange `json:"changed"`
+}
+
+// FieldChange is a field of the record identified by Key whose database value, From, differs from the sheet's, To.
+type FieldChange struct {
+	Key   string `json:"key"`
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Empty reports whether the sheet and the database agree.
+func (d *SheetDiff) Empty() bool {
+	for _, e := range d.entities() {
+		if len(e.diff.Added)+len(e.diff.Removed)+len(e.diff.Changed) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteText writes d to w for people to review, one line per added (+), removed (-) or changed (~) record.
+func (d *SheetDiff) WriteText(w io.Writer) error {
+	for _, e := range d.entities() {
+		if _, err := fmt.Fprintf(w, "%s: %d added, %d removed, %d changed\n",
+			e.name, len(e.diff.Added), len(e.diff.Removed), len(e.diff.Changed)); err != nil {
+			return err
+		}
+		for _, key := range e.diff.Added {
+			fmt.Fprintf(w, "  + %s\n", key)
+		}
+		for _, key := range e.diff.Removed {
+			fmt.Fprintf(w, "  - %s\n", key)
+		}
+		for _, c := range e.diff.Changed {
+			fmt.Fprintf(w, "  ~ %s: %s %q → %q\n", c.Key, c.Field, c.From, c.To)
+		}
+	}
+	if d.SkippedRows > 0 {
+		if _, err := fmt.Fprintf(w, "%d rows without a project or email were skipped\n", d.SkippedRows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *SheetDiff) entities() []struct {
+	name string
+	diff EntityDiff
+} {
+	return []struct {
+		name string
+		diff EntityDiff
+	}{
+		{"Maintainers", d.Maintainers},
+		{"Projects", d.Projects},
+		{"Companies", d.Companies},
+		{"Memberships", d.Memberships},
+	}
+}
+
+// sheetState is the registry described by worksheet rows, or read from the database, reduced to the fields that the
+// bootstrap loader sets.
+type sheetState struct {
+	maintainers map[string]map[string]string // email -> field -> value
+	projects    map[string]map[string]string // name -> field -> value
+	companies   map[string]bool
+	memberships map[string]bool // "email → project"
+}
+
+func newSheetState() *sheetState {
+	return &sheetState{
+		maintainers: map[string]map[string]string{},
+		projects:    map[string]map[string]string{},
+		companies:   map[string]bool{},
+		memberships: map[string]bool{},
+	}
+}
+
+func membershipKey(email, project string) string {
+	return email + " → " + project
+}
+
+// DiffSheet compares rows, as returned by a RowReader, with the registry in db without writing anything. Rows are
+// interpreted as the bootstrap loader interprets them: the first row for a project or maintainer provides its fields
+// and subprojects take the maturity of their parent.
+func DiffSheet(ctx context.Context, db *gorm.DB, rows []map[string]string) (*SheetDiff, error) {
+	current, err := currentSheetState(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	desired, skipped := desiredSheetState(rows, current)
+
+	return &SheetDiff{
+		Maintainers: diffRecords(current.maintainers, desired.maintainers),
+		Projects:    diffRecords(current.projects, desired.projects),
+		Companies:   diffKeys(current.companies, desired.companies),
+		Memberships: diffKeys(current.memberships, desired.memberships),
+		SkippedRows: skipped,
+	}, nil
+}
+
+func currentSheetState(ctx context.Context, db *gorm.DB) (*sheetState, error) {
+	state := newSheetState()
+
+	var projects []model.Project
+	if err := db.WithContext(ctx).Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("DiffSheet: failed to read projects: %w", err)
+	}
+	names := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+	for _, p := range projects {
+		var parent string
+		if p.ParentProjectID != nil {
+			parent = names[*p.ParentProjectID]
+		}
+		state.projects[p.Name] = projectFields(string(p.Maturity), parent, p.MaintainerRef, p.MailingList)
+	}
+
+	var companies []model.Company
+	if err := db.WithContext(ctx).Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("DiffSheet: failed to read companies: %w", err)
+	}
+	for _, c := range companies {
+		state.companies[c.Name] = true
+	}
+
+	var maintainers []model.Maintainer
+	if err := db.WithContext(ctx).Preload("Company").Preload("Projects").Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("DiffSheet: failed to read maintainers: %w", err)
+	}
+	for _, m := range maintainers {
+		var company string
+		if m.CompanyID != nil {
+			company = m.Company.Name
+		}
+		state.maintainers[m.Email] = maintainerFields(m.Name, m.GitHubAccount, m.GitHubEmail, company)
+		for _, p := range m.Projects {
+			state.memberships[membershipKey(m.Email, p.Name)] = true
+		}
+	}
+	return state, nil
+}
+
+func desiredSheetState(rows []map[string]string, current *sheetState) (*sheetState, int) {
+	state := newSheetState()
+	skipped := 0
+	for _, row := range rows {
+		projectName, email := row[ProjectHdr], row[EmailHdr]
+		if projectName == "" || email == "" {
+			skipped++
+			continue
+		}
+
+		if _, ok := state.projects[projectName]; !ok {
+			maturity := row[StatusHdr]
+			parent := row[ParentProjectHdr]
+			if parent != "" {
+				// Subprojects take their parent's maturity, from the sheet if it has been seen, else the database
+				if fields, ok := state.projects[parent]; ok {
+					maturity = fields["maturity"]
+				} else if fields, ok := current.projects[parent]; ok {
+					maturity = fields["maturity"]
+				}
+			}
+			mailingList := row[MailingListAddrHdr]
+			state.projects[projectName] = projectFields(maturity, parent, row[MaintainerFileRefHdr], &mailingList)
+		}
+
+		company := row[CompanyNameHdr]
+		if company != "" {
+			state.companies[company] = true
+		}
+		if _, ok := state.maintainers[email]; !ok {
+			state.maintainers[email] = maintainerFields(row[MaintainerNameHdr], row[GitHubHdr], row[GitHubEmail], company)
+		}
+		state.memberships[membershipKey(email, projectName)] = true
+	}
+	return state, skipped
+}
+
+// projectFields and maintainerFields blank the defaults the database stores for missing values, see MissingValue, so
+// that they match the blank cells they were seeded from.
+func projectFields(maturity, parent, maintainerRef string, mailingList *string) map[string]string {
+	var ml string
+	if mailingList != nil {
+		ml = *mailingList
+	}
+	return map[string]string{
+		"maturity":       maturity,
+		"parent":         parent,
+		"maintainer_ref": maintainerRef,
+		"mailing_list":   presentValue(ml),
+	}
+}
+
+func maintainerFields(name, github, githubEmail, company string) map[string]string {
+	return map[string]string{
+		"name":         name,
+		"github":       presentValue(github),
+		"github_email": presentValue(githubEmail),
+		"company":      company,
+	}
+}
+
+func diffKeys(current, desired map[string]bool) EntityDiff {
+	d := EntityDiff{Added: []string{}, Removed: []string{}, Changed: []FieldChange{}}
+	for key := range desired {
+		if !current[key] {
+			d.Added = append(d.Added, key)
+		}
+	}
+	for key := range current {
+		if !desired[key] {
+			d.Removed = append(d.Removed, key)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	return d
+}
+
+func diffRecords(current, desired map[string]map[string]string) EntityDiff {
+	currentKeys := make(map[string]bool, len(current))
+	for key := range current {
+		currentKeys[key] = true
+	}
+	desiredKeys := make(map[string]bool, len(desired))
+	for key := range desired {
+		desiredKeys[key] = true
+	}
+	d := diffKeys(currentKeys, desiredKeys)
+
+	for key, want := range desired {
+		have, ok := current[key]
+		if !ok {
+			continue
+		}
+		for field, to := range want {
+			if from := have[field]; from != to {
+				d.Changed = append(d.Changed, FieldChange{Key: key, Field: field, From: from, To: to})
+			}
+		}
+	}
+	sort.Slice(d.Changed, func(i, j int) bool {
+		if d.Changed[i].Key != d.Changed[j].Key {
+			return d.Changed[i].Key < d.Changed[j].Key
+		}
+		return d.Changed[i].Field < d.Changed[j].Field
+	})
+	return d
+}
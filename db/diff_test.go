@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSheet(t *testing.T) {
+	rows := tableRows([][]string{
+		{StatusHdr, ProjectHdr, ParentProjectHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr, MaintainerFileRefHdr},
+		{"Graduated", "Kubernetes", "", "Jane Doe", "Example Inc", "jane@example.org", "janedoe", "https://github.com/kubernetes/community/blob/master/OWNERS_ALIASES"},
+		{"", "", "", "Grace Hopper", "Navy", "grace@example.org", "grace", ""},
+		{"", "kubectl", "Kubernetes", "John Roe", "Example Inc", "john@example.org", "johnroe", ""},
+		{"Sandbox", "Jaeger", "", "", "", "", "", ""},
+	})
+
+	diff, err := DiffSheet(context.Background(), testDB, rows)
+	require.NoError(t, err)
+	require.False(t, diff.Empty())
+	require.Equal(t, 1, diff.SkippedRows)
+	require.Equal(t, []string{"grace@example.org"}, diff.Maintainers.Added)
+	require.Equal(t, []string{"ada@example.org"}, diff.Maintainers.Removed)
+	require.Equal(t, []string{"Jaeger"}, diff.Projects.Removed)
+	require.Equal(t, []string{"Navy"}, diff.Companies.Added)
+	require.Equal(t, []string{"grace@example.org → Kubernetes"}, diff.Memberships.Added)
+	require.Empty(t, diff.Projects.Added)
+	require.Empty(t, diff.Projects.Changed, "blank cells match the defaults stored for them")
+	require.Empty(t, diff.Maintainers.Changed, "blank cells match the defaults stored for them")
+}
+
+func TestDiffSheetUnchanged(t *testing.T) {
+	// the rows testdata/fixtures.yaml was written from, with no GitHub emails or mailing lists
+	rows := tableRows([][]string{
+		{StatusHdr, ProjectHdr, ParentProjectHdr, MaintainerNameHdr, CompanyNameHdr, EmailHdr, GitHubHdr, GitHubEmail, MailingListAddrHdr, MaintainerFileRefHdr},
+		{"Graduated", "Kubernetes", "", "Jane Doe", "Example Inc", "jane@example.org", "janedoe", "", "", "https://github.com/kubernetes/community/blob/master/OWNERS_ALIASES"},
+		{"", "kubectl", "Kubernetes", "John Roe", "Example Inc", "john@example.org", "johnroe", "", "", ""},
+		{"Graduated", "Jaeger", "", "Ada Lovelace", "", "ada@example.org", "ada", "", "", ""},
+	})
+
+	diff, err := DiffSheet(context.Background(), testDB, rows)
+	require.NoError(t, err)
+	require.True(t, diff.Empty(), "%+v", diff)
+}
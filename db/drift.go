@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"maintainerd/model"
+	"sort"
+	"strings"
+)
+
+// ExpectedTeamMembers returns the active maintainers expected in the team of the project identified by projectID, by
+// lower case email address with their names: the project's own and those of its subprojects that do not have a team
+// of their own in teams, as returned by GetProjectServiceTeamMap.
+func ExpectedTeamMembers(ctx context.Context, store Store, projectID uint, teams map[uint]*model.ServiceTeam) (map[string]string, error) {
+	tree, err := store.GetProjectTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	expected := map[string]string{}
+	var collect func(node model.ProjectTree) error
+	collect = func(node model.ProjectTree) error {
+		maintainers, err := store.GetMaintainersByProject(ctx, node.Project.ID)
+		if err != nil {
+			return err
+		}
+		for _, m := range maintainers {
+			if m.MaintainerStatus.IsActive() && !MissingValue(m.Email) {
+				expected[strings.ToLower(m.Email)] = m.Name
+			}
+		}
+		for _, sub := range node.Subprojects {
+			if _, own := teams[sub.Project.ID]; own {
+				continue
+			}
+			if err := collect(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := collect(*tree); err != nil {
+		return nil, err
+	}
+	return expected, nil
+}
+
+// CompareTeam compares the maintainers expected in a team, as returned by ExpectedTeamMembers, with the email
+// addresses of its members. It returns the maintainers missing from the team as "name <email>" and the members who
+// are not expected, both sorted.
+func CompareTeam(expected map[string]string, members []string) (missing, extra []string) {
+	inTeam := make(map[string]bool, len(members))
+	for _, email := range members {
+		email = strings.ToLower(email)
+		inTeam[email] = true
+		if _, ok := expected[email]; !ok {
+			extra = append(extra, email)
+		}
+	}
+	for email, name := range expected {
+		if !inTeam[email] {
+			missing = append(missing, fmt.Sprintf("%s <%s>", name, email))
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamDrift(t *testing.T) {
+	store := newTestMemoryStore(t)
+	ctx := context.Background()
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+	require.NoError(t, err)
+
+	// kubectl has no team of its own, so its maintainers are expected in Kubernetes'
+	expected, err := ExpectedTeamMembers(ctx, store, projects["Kubernetes"].ID, teams)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"jane@example.org": "Jane Doe", "john@example.org": "John Roe"}, expected)
+
+	missing, extra := CompareTeam(expected, []string{"Jane@Example.org", "old@example.org"})
+	require.Equal(t, []string{"John Roe <john@example.org>"}, missing)
+	require.Equal(t, []string{"old@example.org"}, extra)
+
+	teams[projects["kubectl"].ID] = teams[projects["Kubernetes"].ID]
+	expected, err = ExpectedTeamMembers(ctx, store, projects["Kubernetes"].ID, teams)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"jane@example.org": "Jane Doe"}, expected)
+}
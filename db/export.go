@@ -0,0 +1,297 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maintainerd/model"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// ExportOptions selects what BuildExport includes.
+type ExportOptions struct {
+	// RedactEmails leaves maintainers' email and GitHub email addresses out of the export
+	RedactEmails bool
+	// Maturities only includes projects at these maturity levels, all projects when empty
+	Maturities []model.Maturity
+	// Service only includes projects that have a team on the service with this name, all projects when empty
+	Service string
+}
+
+// Export is a snapshot of the registry: projects, the maintainers and companies of those projects, the teams the
+// projects have on services and the maintainers who are members of those teams.
+type Export struct {
+	Projects           []ExportProject           `json:"projects" yaml:"projects"`
+	Maintainers        []ExportMaintainer        `json:"maintainers" yaml:"maintainers"`
+	Companies          []string                  `json:"companies" yaml:"companies"`
+	ServiceTeams       []ExportServiceTeam       `json:"service_teams" yaml:"service_teams"`
+	ServiceMemberships []ExportServiceMembership `json:"service_memberships" yaml:"service_memberships"`
+}
+
+type ExportProject struct {
+	Name          string   `json:"name" yaml:"name"`
+	Maturity      string   `json:"maturity" yaml:"maturity"`
+	Parent        string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	MaintainerRef string   `json:"maintainer_ref,omitempty" yaml:"maintainer_ref,omitempty"`
+	MailingList   string   `json:"mailing_list,omitempty" yaml:"mailing_list,omitempty"`
+	Maintainers   []string `json:"maintainers" yaml:"maintainers"` // GitHub accounts
+	Services      []string `json:"services" yaml:"services"`
+}
+
+type ExportMaintainer struct {
+	Name        string   `json:"name" yaml:"name"`
+	GitHub      string   `json:"github" yaml:"github"`
+	Email       string   `json:"email,omitempty" yaml:"email,omitempty"`
+	GitHubEmail string   `json:"github_email,omitempty" yaml:"github_email,omitempty"`
+	Company     string   `json:"company,omitempty" yaml:"company,omitempty"`
+	Status      string   `json:"status" yaml:"status"`
+	Projects    []string `json:"projects" yaml:"projects"`
+}
+
+type ExportServiceTeam struct {
+	Service  string `json:"service" yaml:"service"`
+	Project  string `json:"project" yaml:"project"`
+	TeamID   int    `json:"team_id" yaml:"team_id"`
+	TeamName string `json:"team_name" yaml:"team_name"`
+}
+
+type ExportServiceMembership struct {
+	Service    string `json:"service" yaml:"service"`
+	Project    string `json:"project" yaml:"project"`
+	TeamID     int    `json:"team_id" yaml:"team_id"`
+	Maintainer string `json:"maintainer" yaml:"maintainer"` // GitHub account
+}
+
+// ExportTables are the tables that Export.WriteCSV can write.
+var ExportTables = []string{"projects", "maintainers", "companies", "service_teams", "service_memberships"}
+
+// BuildExport reads the registry in db selected by opts.
+func BuildExport(ctx context.Context, db *gorm.DB, opts ExportOptions) (*Export, error) {
+	db = db.WithContext(ctx)
+
+	var projects []model.Project
+	if err := db.Preload("Maintainers.Company").Order("name").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("BuildExport: failed to read projects: %w", err)
+	}
+	var services []model.Service
+	if err := db.Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("BuildExport: failed to read services: %w", err)
+	}
+	var teams []model.ServiceTeam
+	if err := db.Order("service_team_id").Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("BuildExport: failed to read service teams: %w", err)
+	}
+	var links []model.ServiceUserTeams
+	if err := db.Where("maintainer_id IS NOT NULL").Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("BuildExport: failed to read service memberships: %w", err)
+	}
+
+	serviceNames := make(map[uint]string, len(services))
+	for _, s := range services {
+		serviceNames[s.ID] = s.Name
+	}
+	projectNames := make(map[uint]string, len(projects))
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+	projectServices := map[uint][]string{}
+	for _, st := range teams {
+		projectServices[st.ProjectID] = append(projectServices[st.ProjectID], serviceNames[st.ServiceID])
+	}
+
+	maturities := map[model.Maturity]bool{}
+	for _, m := range opts.Maturities {
+		maturities[m] = true
+	}
+	selected := func(p model.Project) bool {
+		if len(maturities) > 0 && !maturities[p.Maturity] {
+			return false
+		}
+		if opts.Service == "" {
+			return true
+		}
+		for _, name := range projectServices[p.ID] {
+			if strings.EqualFold(name, opts.Service) {
+				return true
+			}
+		}
+		return false
+	}
+
+	e := &Export{
+		Projects:           []ExportProject{},
+		Maintainers:        []ExportMaintainer{},
+		Companies:          []string{},
+		ServiceTeams:       []ExportServiceTeam{},
+		ServiceMemberships: []ExportServiceMembership{},
+	}
+	selectedProjects := map[uint]bool{}
+	maintainers := map[uint]*ExportMaintainer{}
+	github := map[uint]string{}
+	companies := map[string]bool{}
+	for _, p := range projects {
+		if !selected(p) {
+			continue
+		}
+		selectedProjects[p.ID] = true
+
+		ep := ExportProject{
+			Name:          p.Name,
+			Maturity:      string(p.Maturity),
+			MaintainerRef: p.MaintainerRef,
+			Maintainers:   []string{},
+			Services:      projectServices[p.ID],
+		}
+		if ep.Services == nil {
+			ep.Services = []string{}
+		}
+		if p.ParentProjectID != nil {
+			ep.Parent = projectNames[*p.ParentProjectID]
+		}
+		if p.MailingList != nil {
+			ep.MailingList = *p.MailingList
+		}
+		for _, m := range p.Maintainers {
+			ep.Maintainers = append(ep.Maintainers, m.GitHubAccount)
+			github[m.ID] = m.GitHubAccount
+
+			em, ok := maintainers[m.ID]
+			if !ok {
+				em = &ExportMaintainer{
+					Name:   m.Name,
+					GitHub: m.GitHubAccount,
+					Status: string(m.MaintainerStatus),
+				}
+				if !opts.RedactEmails {
+					em.Email = m.Email
+					em.GitHubEmail = m.GitHubEmail
+				}
+				if m.CompanyID != nil {
+					em.Company = m.Company.Name
+					companies[m.Company.Name] = true
+				}
+				maintainers[m.ID] = em
+			}
+			em.Projects = append(em.Projects, p.Name)
+		}
+		sort.Strings(ep.Maintainers)
+		e.Projects = append(e.Projects, ep)
+	}
+
+	for _, em := range maintainers {
+		e.Maintainers = append(e.Maintainers, *em)
+	}
+	sort.Slice(e.Maintainers, func(i, j int) bool { return e.Maintainers[i].Name < e.Maintainers[j].Name })
+	for name := range companies {
+		e.Companies = append(e.Companies, name)
+	}
+	sort.Strings(e.Companies)
+
+	teamsByID := map[uint]model.ServiceTeam{}
+	for _, st := range teams {
+		if !selectedProjects[st.ProjectID] {
+			continue
+		}
+		teamsByID[st.ID] = st
+		var teamName string
+		if st.ServiceTeamName != nil {
+			teamName = *st.ServiceTeamName
+		}
+		e.ServiceTeams = append(e.ServiceTeams, ExportServiceTeam{
+			Service:  serviceNames[st.ServiceID],
+			Project:  projectNames[st.ProjectID],
+			TeamID:   st.ServiceTeamID,
+			TeamName: teamName,
+		})
+	}
+	for _, link := range links {
+		st, ok := teamsByID[link.ServiceTeamID]
+		if !ok {
+			continue
+		}
+		account, ok := github[*link.MaintainerID]
+		if !ok {
+			continue
+		}
+		e.ServiceMemberships = append(e.ServiceMemberships, ExportServiceMembership{
+			Service:    serviceNames[st.ServiceID],
+			Project:    projectNames[st.ProjectID],
+			TeamID:     st.ServiceTeamID,
+			Maintainer: account,
+		})
+	}
+	sort.Slice(e.ServiceMemberships, func(i, j int) bool {
+		a, b := e.ServiceMemberships[i], e.ServiceMemberships[j]
+		if a.TeamID != b.TeamID {
+			return a.TeamID < b.TeamID
+		}
+		return a.Maintainer < b.Maintainer
+	})
+	return e, nil
+}
+
+// WriteJSON writes e to w as indented JSON.
+func (e *Export) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// WriteYAML writes e to w as YAML.
+func (e *Export) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(e); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteCSV writes one of the ExportTables of e to w as CSV with a header row. List values, such as a project's
+// maintainers, are joined with ";".
+func (e *Export) WriteCSV(w io.Writer, table string) error {
+	var records [][]string
+	switch table {
+	case "projects":
+		records = append(records, []string{"name", "maturity", "parent", "maintainer_ref", "mailing_list", "maintainers", "services"})
+		for _, p := range e.Projects {
+			records = append(records, []string{p.Name, p.Maturity, p.Parent, p.MaintainerRef, p.MailingList,
+				strings.Join(p.Maintainers, ";"), strings.Join(p.Services, ";")})
+		}
+	case "maintainers":
+		records = append(records, []string{"name", "github", "email", "github_email", "company", "status", "projects"})
+		for _, m := range e.Maintainers {
+			records = append(records, []string{m.Name, m.GitHub, m.Email, m.GitHubEmail, m.Company, m.Status,
+				strings.Join(m.Projects, ";")})
+		}
+	case "companies":
+		records = append(records, []string{"name"})
+		for _, c := range e.Companies {
+			records = append(records, []string{c})
+		}
+	case "service_teams":
+		records = append(records, []string{"service", "project", "team_id", "team_name"})
+		for _, st := range e.ServiceTeams {
+			records = append(records, []string{st.Service, st.Project, strconv.Itoa(st.TeamID), st.TeamName})
+		}
+	case "service_memberships":
+		records = append(records, []string{"service", "project", "team_id", "maintainer"})
+		for _, sm := range e.ServiceMemberships {
+			records = append(records, []string{sm.Service, sm.Project, strconv.Itoa(sm.TeamID), sm.Maintainer})
+		}
+	default:
+		return fmt.Errorf("WriteCSV: unknown table %q, use one of %s", table, strings.Join(ExportTables, ", "))
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("WriteCSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"maintainerd/model"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExport(t *testing.T) {
+	ctx := context.Background()
+
+	e, err := BuildExport(ctx, testDB, ExportOptions{RedactEmails: true})
+	require.NoError(t, err)
+	require.Len(t, e.Projects, 3)
+	require.Len(t, e.Maintainers, 3)
+	require.Equal(t, []string{"Example Inc"}, e.Companies)
+	for _, m := range e.Maintainers {
+		require.Empty(t, m.Email)
+		require.Empty(t, m.GitHubEmail)
+	}
+
+	e, err = BuildExport(ctx, testDB, ExportOptions{Service: "FOSSA"})
+	require.NoError(t, err)
+	require.Len(t, e.Projects, 1)
+	require.Equal(t, "Kubernetes", e.Projects[0].Name)
+	require.Equal(t, []string{"FOSSA"}, e.Projects[0].Services)
+	require.Equal(t, "jane@example.org", e.Maintainers[0].Email)
+	require.Len(t, e.ServiceTeams, 1)
+
+	e, err = BuildExport(ctx, testDB, ExportOptions{Maturities: []model.Maturity{model.Sandbox}})
+	require.NoError(t, err)
+	require.Empty(t, e.Projects)
+
+	var buf bytes.Buffer
+	e, err = BuildExport(ctx, testDB, ExportOptions{RedactEmails: true})
+	require.NoError(t, err)
+	require.NoError(t, e.WriteCSV(&buf, "projects"))
+	require.Contains(t, buf.String(), "kubectl,Graduated,Kubernetes,")
+	require.Error(t, e.WriteCSV(&buf, "users"))
+}
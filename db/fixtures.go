@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"maintainerd/model"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// Fixtures describes a set of registry data in YAML so that it can be loaded into a MemoryStore or seeded into a
+// database. Records refer to each other by name: a project's parent, a maintainer's company and projects, and the
+// project and service of a service team.
+//
+//	services:
+//	  - name: FOSSA
+//	projects:
+//	  - name: Kubernetes
+//	    maturity: Graduated
+//	  - name: kubectl
+//	    parent: Kubernetes
+//	maintainers:
+//	  - name: Jane Doe
+//	    email: jane@example.org
+//	    github: janedoe
+//	    company: Example Inc
+//	    projects: [Kubernetes]
+//	service_teams:
+//	  - project: Kubernetes
+//	    service: FOSSA
+//	    team_id: 42
+type Fixtures struct {
+	Services     []ServiceFixture     `yaml:"services"`
+	Companies    []string             `yaml:"companies"`
+	Projects     []ProjectFixture     `yaml:"projects"`
+	Maintainers  []MaintainerFixture  `yaml:"maintainers"`
+	ServiceTeams []ServiceTeamFixture `yaml:"service_teams"`
+}
+
+type ServiceFixture struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+}
+
+type ProjectFixture struct {
+	Name          string `yaml:"name"`
+	Maturity      string `yaml:"maturity"`
+	Parent        string `yaml:"parent"`
+	MaintainerRef string `yaml:"maintainer_ref"`
+	MailingList   string `yaml:"mailing_list"`
+}
+
+type MaintainerFixture struct {
+	Name        string   `yaml:"name"`
+	Email       string   `yaml:"email"`
+	GitHub      string   `yaml:"github"`
+	GitHubEmail string   `yaml:"github_email"`
+	Company     string   `yaml:"company"`
+	Status      string   `yaml:"status"`
+	Projects    []string `yaml:"projects"`
+}
+
+type ServiceTeamFixture struct {
+	Project  string `yaml:"project"`
+	Service  string `yaml:"service"`
+	TeamID   int    `yaml:"team_id"`
+	TeamName string `yaml:"team_name"`
+}
+
+// LoadFixtures reads Fixtures from the YAML file at path.
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadFixtures: %w", err)
+	}
+	return ParseFixtures(data)
+}
+
+// ParseFixtures decodes Fixtures from YAML.
+func ParseFixtures(data []byte) (*Fixtures, error) {
+	var f Fixtures
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("ParseFixtures: %w", err)
+	}
+	return &f, nil
+}
+
+// SeedFixtures inserts f into db, which must already have the maintainerd schema.
+func SeedFixtures(ctx context.Context, db *gorm.DB, f *Fixtures) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		services := map[string]uint{}
+		for _, sf := range f.Services {
+			svc := model.Service{Name: sf.Name, Description: sf.Description}
+			if err := tx.Create(&svc).Error; err != nil {
+				return fmt.Errorf("SeedFixtures: service %s: %w", sf.Name, err)
+			}
+			services[svc.Name] = svc.ID
+		}
+
+		companies := map[string]uint{}
+		company := func(name string) (*uint, error) {
+			if name == "" {
+				return nil, nil
+			}
+			if id, ok := companies[name]; ok {
+				return &id, nil
+			}
+			c := model.Company{Name: name}
+			if err := tx.Create(&c).Error; err != nil {
+				return nil, fmt.Errorf("SeedFixtures: company %s: %w", name, err)
+			}
+			companies[name] = c.ID
+			return &c.ID, nil
+		}
+		for _, name := range f.Companies {
+			if _, err := company(name); err != nil {
+				return err
+			}
+		}
+
+		projects := map[string]*model.Project{}
+		for _, pf := range f.Projects {
+			p := pf.project()
+			if err := tx.Create(&p).Error; err != nil {
+				return fmt.Errorf("SeedFixtures: project %s: %w", pf.Name, err)
+			}
+			projects[p.Name] = &p
+		}
+		for _, pf := range f.Projects {
+			if pf.Parent == "" {
+				continue
+			}
+			parent, ok := projects[pf.Parent]
+			if !ok {
+				return fmt.Errorf("SeedFixtures: project %s has unknown parent %s", pf.Name, pf.Parent)
+			}
+			if err := tx.Model(projects[pf.Name]).Update("parent_project_id", parent.ID).Error; err != nil {
+				return fmt.Errorf("SeedFixtures: project %s: %w", pf.Name, err)
+			}
+		}
+
+		for _, mf := range f.Maintainers {
+			companyID, err := company(mf.Company)
+			if err != nil {
+				return err
+			}
+			m := mf.maintainer()
+			m.CompanyID = companyID
+			if err := tx.Create(&m).Error; err != nil {
+				return fmt.Errorf("SeedFixtures: maintainer %s: %w", mf.Email, err)
+			}
+			for _, name := range mf.Projects {
+				p, ok := projects[name]
+				if !ok {
+					return fmt.Errorf("SeedFixtures: maintainer %s has unknown project %s", mf.Email, name)
+				}
+				if err := tx.Model(&m).Association("Projects").Append(p); err != nil {
+					return fmt.Errorf("SeedFixtures: maintainer %s: %w", mf.Email, err)
+				}
+			}
+		}
+
+		for _, tf := range f.ServiceTeams {
+			st, err := tf.serviceTeam(projects, services)
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(st).Error; err != nil {
+				return fmt.Errorf("SeedFixtures: service team %d: %w", tf.TeamID, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (pf ProjectFixture) project() model.Project {
+	p := model.Project{
+		Name:          pf.Name,
+		Maturity:      model.Maturity(pf.Maturity),
+		MaintainerRef: pf.MaintainerRef,
+	}
+	if pf.MailingList != "" {
+		mailingList := pf.MailingList
+		p.MailingList = &mailingList
+	}
+	return p
+}
+
+func (mf MaintainerFixture) maintainer() model.Maintainer {
+	m := model.Maintainer{
+		Name:             mf.Name,
+		Email:            mf.Email,
+		GitHubAccount:    mf.GitHub,
+		GitHubEmail:      mf.GitHubEmail,
+		MaintainerStatus: model.MaintainerStatus(mf.Status),
+	}
+	if m.MaintainerStatus == "" {
+		m.MaintainerStatus = model.ActiveMaintainer
+	}
+	return m
+}
+
+func (tf ServiceTeamFixture) serviceTeam(projects map[string]*model.Project, services map[string]uint) (*model.ServiceTeam, error) {
+	p, ok := projects[tf.Project]
+	if !ok {
+		return nil, fmt.Errorf("fixtures: service team %d has unknown project %s", tf.TeamID, tf.Project)
+	}
+	serviceID, ok := services[tf.Service]
+	if !ok {
+		return nil, fmt.Errorf("fixtures: service team %d has unknown service %s", tf.TeamID, tf.Service)
+	}
+	teamName := tf.TeamName
+	if teamName == "" {
+		teamName = p.Name
+	}
+	projectName := p.Name
+	return &model.ServiceTeam{
+		ProjectID:       p.ID,
+		ServiceID:       serviceID,
+		ServiceTeamID:   tf.TeamID,
+		ServiceTeamName: &teamName,
+		ProjectName:     &projectName,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// GetSubprojects returns the projects whose parent is the project identified by parentID.
+func (s *SQLStore) GetSubprojects(ctx context.Context, parentID uint) ([]model.Project, error) {
+	var projects []model.Project
+	err := s.db.WithContext(ctx).
+		Where("parent_project_id = ?", parentID).
+		Order("name").
+		Find(&projects).Error
+	return projects, err
+}
+
+// GetProjectTree returns the project identified by rootID with all of its subprojects beneath it.
+func (s *SQLStore) GetProjectTree(ctx context.Context, rootID uint) (*model.ProjectTree, error) {
+	var projects []model.Project
+	if err := s.db.WithContext(ctx).Order("name").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	tree, ok := buildProjectTree(projects, rootID)
+	if !ok {
+		return nil, fmt.Errorf("GetProjectTree: %w: id %d", ErrProjectNotFound, rootID)
+	}
+	return tree, nil
+}
+
+// GetRootProject walks up from the project identified by projectID and returns the top-most project in its hierarchy,
+// which is the project itself if it has no parent.
+func (s *SQLStore) GetRootProject(ctx context.Context, projectID uint) (*model.Project, error) {
+	seen := map[uint]bool{}
+	id := projectID
+	for {
+		var p model.Project
+		if err := s.db.WithContext(ctx).First(&p, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("GetRootProject: %w: id %d", ErrProjectNotFound, id)
+			}
+			return nil, err
+		}
+		seen[p.ID] = true
+		if p.ParentProjectID == nil || seen[*p.ParentProjectID] {
+			return &p, nil
+		}
+		id = *p.ParentProjectID
+	}
+}
+
+// GetRolledUpMaintainers returns the maintainers of the project identified by projectID together with the
+// maintainers of all of its subprojects. Each maintainer is listed once.
+func (s *SQLStore) GetRolledUpMaintainers(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
+	tree, err := s.GetProjectTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var maintainers []model.Maintainer
+	err = s.db.WithContext(ctx).
+		Distinct("maintainers.*").
+		Joins("JOIN maintainer_projects mp ON mp.maintainer_id = maintainers.id").
+		Where("mp.project_id IN ?", projectTreeIDs(*tree)).
+		Preload("Company").
+		Order("maintainers.name").
+		Find(&maintainers).Error
+	return maintainers, err
+}
+
+// buildProjectTree arranges projects into the tree beneath rootID. A project that is its own ancestor is only visited
+// once so a bad ParentProjectID cannot loop forever.
+func buildProjectTree(projects []model.Project, rootID uint) (*model.ProjectTree, bool) {
+	children := make(map[uint][]model.Project)
+	var root *model.Project
+	for i, p := range projects {
+		if p.ID == rootID {
+			root = &projects[i]
+		}
+		if p.ParentProjectID != nil {
+			children[*p.ParentProjectID] = append(children[*p.ParentProjectID], p)
+		}
+	}
+	if root == nil {
+		return nil, false
+	}
+
+	visited := map[uint]bool{}
+	var build func(p model.Project) model.ProjectTree
+	build = func(p model.Project) model.ProjectTree {
+		visited[p.ID] = true
+		node := model.ProjectTree{Project: p}
+		for _, child := range children[p.ID] {
+			if !visited[child.ID] {
+				node.Subprojects = append(node.Subprojects, build(child))
+			}
+		}
+		return node
+	}
+	tree := build(*root)
+	return &tree, true
+}
+
+// projectTreeIDs returns the ids of every project in tree.
+func projectTreeIDs(tree model.ProjectTree) []uint {
+	ids := []uint{tree.Project.ID}
+	for _, sub := range tree.Subprojects {
+		ids = append(ids, projectTreeIDs(sub)...)
+	}
+	return ids
+}
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RowReader returns the rows of the maintainer worksheet keyed by header, see tableRows.
+type RowReader func(ctx context.Context) ([]map[string]string, error)
+
+// SheetRows returns a RowReader for the tabs of mapping in the Google Sheet spreadsheetID.
+func SheetRows(spreadsheetID, credentialsPath string, mapping *SheetMapping) RowReader {
+	return func(ctx context.Context) ([]map[string]string, error) {
+		return ReadWorksheet(ctx, spreadsheetID, credentialsPath, mapping)
+	}
+}
+
+// FileRows returns a RowReader for a CSV or XLSX export of the worksheet, see ReadRowsFile.
+func FileRows(filePath string, mapping *SheetMapping) RowReader {
+	return func(ctx context.Context) ([]map[string]string, error) {
+		return ReadRowsFile(filePath, mapping)
+	}
+}
+
+// ReadRowsFile reads a CSV or XLSX export of the maintainer worksheet, chosen by the file extension, and returns its
+// rows as ReadWorksheet does. Every tab of mapping is read from an XLSX file, whose tabs MUST start with the header row;
+// tab ranges are not used. A CSV file holds a single tab, its rows take the status of mapping's first tab.
+func ReadRowsFile(filePath string, mapping *SheetMapping) ([]map[string]string, error) {
+	var rows []map[string]string
+	switch ext := strings.ToLower(filepath.Ext(filePath)); ext {
+	case ".csv":
+		values, err := readCSV(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("ReadRowsFile: %s: %w", filePath, err)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("ReadRowsFile: %s is empty", filePath)
+		}
+		rows = mapping.tabRows(mapping.Tabs[0], 1, values)
+	case ".xlsx":
+		for _, tab := range mapping.Tabs {
+			values, err := readXLSX(filePath, tab.Name)
+			if err != nil {
+				return nil, fmt.Errorf("ReadRowsFile: %s: %w", filePath, err)
+			}
+			if len(values) == 0 {
+				return nil, fmt.Errorf("ReadRowsFile: %s: worksheet %s is empty", filePath, tab.Name)
+			}
+			rows = append(rows, mapping.tabRows(tab, 1, values)...)
+		}
+	default:
+		return nil, fmt.Errorf("ReadRowsFile: %s: unsupported file type %q, use .csv or .xlsx", filePath, ext)
+	}
+	return rows, nil
+}
+
+func readCSV(filePath string) ([][]string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1 // exports drop trailing empty cells on some rows
+	values, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) > 0 && len(values[0]) > 0 {
+		values[0][0] = strings.TrimPrefix(values[0][0], "\ufeff") // byte order mark written by spreadsheet exports
+	}
+	return values, nil
+}
+
+// XLSX files are zip archives of SpreadsheetML parts, only the parts needed to read cell values are decoded.
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is the text of a shared string or inline string, either a single t element or a run of r elements.
+type xlsxText struct {
+	T string `xml:"t"`
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.R {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(filePath, sheet string) ([][]string, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(parts, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(parts, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	var shared xlsxSharedStrings
+	if _, ok := parts["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(parts, "xl/sharedStrings.xml", &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	var rID string
+	for _, s := range workbook.Sheets {
+		if sheet == "" || s.Name == sheet {
+			rID = s.RID
+			break
+		}
+	}
+	if rID == "" {
+		return nil, fmt.Errorf("worksheet %q not found", sheet)
+	}
+	var target string
+	for _, rel := range rels.Relationships {
+		if rel.ID == rID {
+			target = rel.Target
+		}
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	var ws xlsxWorksheet
+	if err := decodeXLSXPart(parts, target, &ws); err != nil {
+		return nil, err
+	}
+
+	var values [][]string
+	for i, row := range ws.Rows {
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = i + 1
+		}
+		// Empty rows are not stored, keep them so that rows line up with the sheet
+		for len(values) < rowNum-1 {
+			values = append(values, nil)
+		}
+
+		var cells []string
+		for j, c := range row.Cells {
+			col := j
+			if c.R != "" {
+				if col, err = xlsxColumn(c.R); err != nil {
+					return nil, err
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.T {
+			case "s":
+				idx, err := strconv.Atoi(c.V)
+				if err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, fmt.Errorf("cell %s refers to unknown shared string %q", c.R, c.V)
+				}
+				cells[col] = shared.Items[idx].String()
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			default:
+				cells[col] = c.V
+			}
+		}
+		values = append(values, cells)
+	}
+	return values, nil
+}
+
+func decodeXLSXPart(parts map[string]*zip.File, name string, v any) error {
+	f, ok := parts[name]
+	if !ok {
+		return fmt.Errorf("not an XLSX file, %s is missing", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", name, err)
+	}
+	return nil
+}
+
+// xlsxColumn returns the zero based column index of a cell reference such as "AB12".
+func xlsxColumn(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ImportReport describes a load of worksheet rows so that data owners can fix the sheet. Rows are identified by their
+// tab and row number in the sheet. Disappeared and StaleMemberships list what is in the database but no longer in the
+// sheet, Prune says what was done about it.
+type ImportReport struct {
+	Rows             int          `json:"rows"`
+	Imported         int          `json:"imported"`
+	BlankRows        int          `json:"blank_rows"`
+	Skipped          []SkippedRow `json:"skipped"`
+	Warnings         []RowWarning `json:"warnings"`
+	Prune            PrunePolicy  `json:"prune,omitempty"`
+	Disappeared      []string     `json:"disappeared"`       // emails of Active maintainers
+	StaleMemberships []string     `json:"stale_memberships"` // "email → project"
+}
+
+// SkippedRow is a row that was not imported and why.
+type SkippedRow struct {
+	Tab     string `json:"tab,omitempty"`
+	Row     int    `json:"row"`
+	Project string `json:"project,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// RowWarning lists the problems with a row that was imported, they are also stored in the maintainer's
+// ImportWarnings.
+type RowWarning struct {
+	Tab      string   `json:"tab,omitempty"`
+	Row      int      `json:"row"`
+	Project  string   `json:"project"`
+	Email    string   `json:"email"`
+	Warnings []string `json:"warnings"`
+}
+
+func newImportReport(rows int) *ImportReport {
+	return &ImportReport{Rows: rows, Skipped: []SkippedRow{}, Warnings: []RowWarning{}, Disappeared: []string{}, StaleMemberships: []string{}}
+}
+
+func (r *ImportReport) skip(tab string, rowNum int, row map[string]string, reason string) {
+	r.Skipped = append(r.Skipped, SkippedRow{Tab: tab, Row: rowNum, Project: row[ProjectHdr], Email: row[EmailHdr], Reason: reason})
+}
+
+// Summary returns a one line description of r.
+func (r *ImportReport) Summary() string {
+	summary := fmt.Sprintf("%d rows read, %d imported (%d with warnings), %d skipped, %d blank",
+		r.Rows, r.Imported, len(r.Warnings), len(r.Skipped), r.BlankRows)
+	if r.Prune != "" {
+		summary += fmt.Sprintf(", %d maintainers and %d memberships no longer in the sheet (prune: %s)",
+			len(r.Disappeared), len(r.StaleMemberships), r.Prune)
+	}
+	return summary
+}
+
+// WriteText writes r to w with a line for every skipped row, every row with warnings and everything no longer in the
+// sheet.
+func (r *ImportReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, r.Summary())
+	if len(r.Skipped) > 0 {
+		fmt.Fprintln(&b, "Skipped rows:")
+		for _, s := range r.Skipped {
+			fmt.Fprintf(&b, "  %s (%s, %s): %s\n", rowName(s.Tab, s.Row), s.Project, s.Email, s.Reason)
+		}
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Fprintln(&b, "Rows with warnings:")
+		for _, rw := range r.Warnings {
+			fmt.Fprintf(&b, "  %s (%s, %s): %s\n", rowName(rw.Tab, rw.Row), rw.Project, rw.Email, strings.Join(rw.Warnings, "; "))
+		}
+	}
+	if len(r.Disappeared) > 0 {
+		fmt.Fprintln(&b, "Maintainers no longer in the sheet:")
+		for _, email := range r.Disappeared {
+			fmt.Fprintf(&b, "  %s\n", email)
+		}
+	}
+	if len(r.StaleMemberships) > 0 {
+		fmt.Fprintln(&b, "Memberships no longer in the sheet:")
+		for _, key := range r.StaleMemberships {
+			fmt.Fprintf(&b, "  %s\n", key)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func rowName(tab string, row int) string {
+	if tab == "" {
+		return fmt.Sprintf("row %d", row)
+	}
+	return fmt.Sprintf("%s row %d", tab, row)
+}
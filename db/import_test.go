@@ -0,0 +1,80 @@
+package db
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func importWant(tab, status string) []map[string]string {
+	return []map[string]string{
+		{StatusHdr: "Graduated", ProjectHdr: "Kubernetes", MaintainerNameHdr: "Jane Doe", EmailHdr: "jane@example.org",
+			MaintainerStatusHdr: status, SheetTabKey: tab, SheetRowKey: "2"},
+		{StatusHdr: "Graduated", ProjectHdr: "Kubernetes", MaintainerNameHdr: "John Roe", EmailHdr: "john@example.org",
+			MaintainerStatusHdr: status, SheetTabKey: tab, SheetRowKey: "3"},
+		{StatusHdr: "Sandbox", ProjectHdr: "Jaeger", MaintainerNameHdr: "Ada Lovelace", EmailHdr: "ada@example.org",
+			MaintainerStatusHdr: status, SheetTabKey: tab, SheetRowKey: "4"},
+	}
+}
+
+func TestReadRowsFileCSV(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "maintainers.csv")
+	require.NoError(t, os.WriteFile(path, []byte("\ufeffMaturity,Project,Maintainer Name,Email Address\n"+
+		"Graduated,Kubernetes,Jane Doe,jane@example.org\n"+
+		",,John Roe,john@example.org\n"+
+		"Sandbox,Jaeger,Ada Lovelace,ada@example.org\n"), 0o600))
+	mappingPath := filepath.Join(dir, "mapping.yaml")
+	require.NoError(t, os.WriteFile(mappingPath, []byte("columns:\n  status: Maturity\n  email: Email Address\n"+
+		"tabs:\n  - name: Emeritus\n    status: Emeritus\n"), 0o600))
+
+	mapping, err := LoadSheetMapping(mappingPath)
+	require.NoError(t, err)
+	rows, err := ReadRowsFile(path, mapping)
+	require.NoError(t, err)
+	require.Equal(t, importWant("Emeritus", "Emeritus"), rows)
+}
+
+func TestReadRowsFileXLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "maintainers.xlsx")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			`<sheet name="Emeritus" sheetId="1" r:id="rId1"/><sheet name="Active" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Status</t></si><si><t>Project</t></si><si><r><t>Maintainer </t></r><r><t>Name</t></r></si>` +
+			`<si><t>Emails</t></si><si><t>Graduated</t></si><si><t>Kubernetes</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData/></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>4</v></c><c r="B2" t="s"><v>5</v></c><c r="C2" t="inlineStr"><is><t>Jane Doe</t></is></c>` +
+			`<c r="D2" t="inlineStr"><is><t>jane@example.org</t></is></c></row>` +
+			`<row r="3"><c r="C3" t="inlineStr"><is><t>John Roe</t></is></c><c r="D3" t="inlineStr"><is><t>john@example.org</t></is></c></row>` +
+			`<row r="4"><c r="A4" t="str"><v>Sandbox</v></c><c r="B4" t="str"><v>Jaeger</v></c><c r="C4" t="str"><v>Ada Lovelace</v></c>` +
+			`<c r="D4" t="str"><v>ada@example.org</v></c></row>` +
+			`</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	rows, err := ReadRowsFile(path, DefaultSheetMapping())
+	require.NoError(t, err)
+	require.Equal(t, importWant("Active", "Active"), rows)
+
+	_, err = ReadRowsFile(path, DefaultSheetMapping().OnlyTab("Missing"))
+	require.Error(t, err)
+	_, err = ReadRowsFile(path, DefaultSheetMapping().OnlyTab("Emeritus"))
+	require.Error(t, err) // it is empty
+}
@@ -0,0 +1,311 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maintainerd/model"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// Landscape is the part of the CNCF landscape.yml that maintainerd reads: categories of subcategories of items.
+type Landscape struct {
+	Categories []struct {
+		Name          string `yaml:"name"`
+		Subcategories []struct {
+			Name  string          `yaml:"name"`
+			Items []LandscapeItem `yaml:"items"`
+		} `yaml:"subcategories"`
+	} `yaml:"landscape"`
+}
+
+// LandscapeItem is an entry of the landscape. Only items with a Project, the CNCF maturity in lower case, are CNCF
+// projects.
+type LandscapeItem struct {
+	Name            string `yaml:"name"`
+	RepoURL         string `yaml:"repo_url"`
+	AdditionalRepos []struct {
+		RepoURL string `yaml:"repo_url"`
+	} `yaml:"additional_repos"`
+	Project string `yaml:"project"`
+}
+
+// Maturity returns the maturity of i as maintainerd spells it, e.g. Graduated for graduated.
+func (i LandscapeItem) Maturity() model.Maturity {
+	if i.Project == "" {
+		return ""
+	}
+	return model.Maturity(strings.ToUpper(i.Project[:1]) + strings.ToLower(i.Project[1:]))
+}
+
+// Repos returns the repository URLs of i, its main repository first.
+func (i LandscapeItem) Repos() []string {
+	var repos []string
+	for _, url := range append([]string{i.RepoURL}, additionalRepoURLs(i)...) {
+		if url = strings.TrimSuffix(strings.TrimSpace(url), "/"); url != "" {
+			repos = appendUnique(repos, url)
+		}
+	}
+	return repos
+}
+
+func additionalRepoURLs(i LandscapeItem) []string {
+	urls := make([]string, len(i.AdditionalRepos))
+	for n, r := range i.AdditionalRepos {
+		urls[n] = r.RepoURL
+	}
+	return urls
+}
+
+// LoadLandscape reads a landscape.yml from path.
+func LoadLandscape(path string) (*Landscape, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadLandscape: %w", err)
+	}
+	var l Landscape
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("LoadLandscape: %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// Projects returns the CNCF projects in l, each once, in the order they appear.
+func (l *Landscape) Projects() []LandscapeItem {
+	var items []LandscapeItem
+	seen := map[string]bool{}
+	for _, c := range l.Categories {
+		for _, sc := range c.Subcategories {
+			for _, item := range sc.Items {
+				if item.Project == "" || item.Name == "" || seen[item.Name] {
+					continue
+				}
+				seen[item.Name] = true
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// LandscapeReport describes an import of the landscape. Created and Unknown hold landscape names, Changed holds the
+// maturity and repo changes keyed by registry project name and NotInLandscape the top level registry projects that no
+// landscape project matched.
+type LandscapeReport struct {
+	Created        []string        `json:"created"`
+	Changed        []FieldChange   `json:"changed"`
+	Mismatches     []NameMismatch  `json:"mismatches"`
+	Collisions     []NameCollision `json:"collisions"` // landscape projects skipped as an earlier one had their match
+	Unknown        []string        `json:"unknown"`    // landscape projects whose maturity maintainerd does not know
+	NotInLandscape []string        `json:"not_in_landscape"`
+}
+
+// NameMismatch is a landscape project matched to a registry project whose name is spelled differently.
+type NameMismatch struct {
+	Landscape string `json:"landscape"`
+	Registry  string `json:"registry"`
+}
+
+// NameCollision is a landscape project matched to the same registry project as an earlier one in the landscape.
+type NameCollision struct {
+	Landscape string `json:"landscape"`
+	Earlier   string `json:"earlier"`
+	Registry  string `json:"registry"`
+}
+
+// WriteText writes r to w for people to review.
+func (r *LandscapeReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d projects created, %d changes, %d name mismatches, %d name collisions, %d not in the landscape\n",
+		len(r.Created), len(r.Changed), len(r.Mismatches), len(r.Collisions), len(r.NotInLandscape))
+	for _, name := range r.Created {
+		fmt.Fprintf(&b, "  + %s\n", name)
+	}
+	for _, c := range r.Changed {
+		fmt.Fprintf(&b, "  ~ %s: %s %q → %q\n", c.Key, c.Field, c.From, c.To)
+	}
+	for _, m := range r.Mismatches {
+		fmt.Fprintf(&b, "  ! landscape %q is %q in maintainerd\n", m.Landscape, m.Registry)
+	}
+	for _, c := range r.Collisions {
+		fmt.Fprintf(&b, "  ! landscape %q and %q are both %q in maintainerd, %q was skipped\n", c.Earlier, c.Landscape, c.Registry, c.Landscape)
+	}
+	for _, name := range r.Unknown {
+		fmt.Fprintf(&b, "  ? %s has an unknown maturity\n", name)
+	}
+	for _, name := range r.NotInLandscape {
+		fmt.Fprintf(&b, "  - %s\n", name)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ImportLandscape brings the maturity and repos of the projects in db into line with the CNCF projects in l, creating
+// the projects db does not have. Landscape projects are matched to registry projects by name, ignoring case and
+// punctuation and trying the alias in brackets of names such as "Open Policy Agent (OPA)". Matches whose names differ
+// are reported as mismatches so that one of them can be renamed. A landscape project matching the same registry project
+// as an earlier one, or the project created for it, is reported as a collision and skipped. Subprojects take the
+// maturity of their parent. When dryRun is set nothing is written.
+func ImportLandscape(ctx context.Context, db *gorm.DB, l *Landscape, dryRun bool) (*LandscapeReport, error) {
+	report := &LandscapeReport{Created: []string{}, Changed: []FieldChange{}, Mismatches: []NameMismatch{}, Collisions: []NameCollision{}, Unknown: []string{}, NotInLandscape: []string{}}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var projects []model.Project
+		if err := tx.Find(&projects).Error; err != nil {
+			return fmt.Errorf("failed to read projects: %w", err)
+		}
+		byKey := map[string]*model.Project{}
+		for i := range projects {
+			byKey[landscapeKey(projects[i].Name)] = &projects[i]
+		}
+		matched := map[uint]bool{}
+		claimed := map[*model.Project]string{} // landscape name of the item each project was matched to or created for
+
+		for _, item := range l.Projects() {
+			maturity := item.Maturity()
+			if !maturity.IsValid() {
+				report.Unknown = append(report.Unknown, item.Name)
+				continue
+			}
+			project := matchLandscapeItem(byKey, item.Name)
+			if earlier, ok := claimed[project]; ok {
+				report.Collisions = append(report.Collisions, NameCollision{Landscape: item.Name, Earlier: earlier, Registry: project.Name})
+				continue
+			}
+			if project == nil {
+				report.Created = append(report.Created, item.Name)
+				// a dry run goes on with the project unsaved, so that the repos it would get are reported
+				project = &model.Project{Name: item.Name, Maturity: maturity}
+				if !dryRun {
+					if err := tx.Create(project).Error; err != nil {
+						return fmt.Errorf("failed to create project %s: %w", item.Name, err)
+					}
+				}
+				byKey[landscapeKey(item.Name)] = project
+			} else {
+				if project.Name != item.Name {
+					report.Mismatches = append(report.Mismatches, NameMismatch{Landscape: item.Name, Registry: project.Name})
+				}
+				if err := setLandscapeMaturity(tx, project, maturity, dryRun, report); err != nil {
+					return err
+				}
+			}
+			claimed[project] = item.Name
+			matched[project.ID] = true
+			if err := setLandscapeRepos(tx, project, item.Repos(), dryRun, report); err != nil {
+				return err
+			}
+		}
+
+		for _, p := range projects {
+			if p.ParentProjectID == nil && !matched[p.ID] {
+				report.NotInLandscape = append(report.NotInLandscape, p.Name)
+			}
+		}
+		sort.Strings(report.NotInLandscape)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ImportLandscape: %w", err)
+	}
+	return report, nil
+}
+
+// matchLandscapeItem returns the project in byKey named name, or named the alias in brackets at the end of name or
+// the rest of name, or nil.
+func matchLandscapeItem(byKey map[string]*model.Project, name string) *model.Project {
+	candidates := []string{name}
+	if open := strings.LastIndex(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+		candidates = append(candidates, name[open+1:len(name)-1], name[:open])
+	}
+	for _, c := range candidates {
+		if p, ok := byKey[landscapeKey(c)]; ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// landscapeKey reduces a project name to its lower case letters and digits.
+func landscapeKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// setLandscapeMaturity sets the maturity of project and of its subprojects to maturity.
+func setLandscapeMaturity(tx *gorm.DB, project *model.Project, maturity model.Maturity, dryRun bool, report *LandscapeReport) error {
+	var subprojects []model.Project
+	if err := tx.Where("parent_project_id = ?", project.ID).Find(&subprojects).Error; err != nil {
+		return fmt.Errorf("failed to read subprojects of %s: %w", project.Name, err)
+	}
+	for _, p := range append([]model.Project{*project}, subprojects...) {
+		if p.Maturity == maturity {
+			continue
+		}
+		report.Changed = append(report.Changed, FieldChange{Key: p.Name, Field: "maturity", From: string(p.Maturity), To: string(maturity)})
+		if dryRun {
+			continue
+		}
+		if err := tx.Model(&p).Update("maturity", maturity).Error; err != nil {
+			return fmt.Errorf("failed to set maturity of %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// setLandscapeRepos makes repos the repos of project, removing the ones the landscape no longer lists.
+func setLandscapeRepos(tx *gorm.DB, project *model.Project, repos []string, dryRun bool, report *LandscapeReport) error {
+	var current []model.ProjectRepo
+	if project.ID != 0 {
+		if err := tx.Where("project_id = ?", project.ID).Find(&current).Error; err != nil {
+			return fmt.Errorf("failed to read repos of %s: %w", project.Name, err)
+		}
+	}
+	have := map[string]bool{}
+	for _, r := range current {
+		have[r.URL] = true
+		if slices.Contains(repos, r.URL) {
+			continue
+		}
+		report.Changed = append(report.Changed, FieldChange{Key: project.Name, Field: "repo", From: r.URL})
+		if dryRun {
+			continue
+		}
+		if err := tx.Delete(&r).Error; err != nil {
+			return fmt.Errorf("failed to remove repo %s of %s: %w", r.URL, project.Name, err)
+		}
+	}
+	for _, url := range repos {
+		if have[url] {
+			continue
+		}
+		report.Changed = append(report.Changed, FieldChange{Key: project.Name, Field: "repo", To: url})
+		if dryRun {
+			continue
+		}
+		// A repo moved between projects in the landscape belongs to the new one
+		var repo model.ProjectRepo
+		err := tx.Unscoped().Where("url = ?", url).First(&repo).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = tx.Create(&model.ProjectRepo{ProjectID: project.ID, URL: url}).Error
+		case err == nil:
+			err = tx.Unscoped().Model(&repo).Updates(map[string]any{"project_id": project.ID, "deleted_at": nil}).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add repo %s to %s: %w", url, project.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"maintainerd/model"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestImportLandscape(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:landscape?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, RegisterAuditCallbacks(conn))
+
+	k8s := model.Project{Name: "Kubernetes", Maturity: model.Incubating}
+	require.NoError(t, conn.Create(&k8s).Error)
+	require.NoError(t, conn.Create(&model.Project{Name: "kubectl", Maturity: model.Incubating, ParentProjectID: &k8s.ID}).Error)
+	require.NoError(t, conn.Create(&model.Project{Name: "jaeger", Maturity: model.Graduated}).Error)
+	require.NoError(t, conn.Create(&model.Project{Name: "Retired Thing", Maturity: model.Archived}).Error)
+
+	landscape, err := LoadLandscape("testdata/landscape.yml")
+	require.NoError(t, err)
+	require.Len(t, landscape.Projects(), 4, "Nomad is not a CNCF project")
+
+	ctx := context.Background()
+	dry, err := ImportLandscape(ctx, conn, landscape, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"OpenTelemetry"}, dry.Created)
+	require.Contains(t, dry.Changed, FieldChange{Key: "OpenTelemetry", Field: "repo", To: "https://github.com/open-telemetry/opentelemetry-collector"},
+		"a dry run reports the repos of the projects it would create")
+	var count int64
+	require.NoError(t, conn.Model(&model.ProjectRepo{}).Count(&count).Error)
+	require.Zero(t, count, "a dry run writes nothing")
+
+	report, err := ImportLandscape(ctx, conn, landscape, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"OpenTelemetry"}, report.Created)
+	require.Equal(t, []NameMismatch{{Landscape: "Jaeger Tracing (Jaeger)", Registry: "jaeger"}}, report.Mismatches)
+	require.Equal(t, []string{"Mystery"}, report.Unknown)
+	require.Equal(t, []string{"Retired Thing"}, report.NotInLandscape)
+	require.Contains(t, report.Changed, FieldChange{Key: "kubectl", Field: "maturity", From: "Incubating", To: "Graduated"})
+
+	var kubectl model.Project
+	require.NoError(t, conn.Where("name = ?", "kubectl").First(&kubectl).Error)
+	require.Equal(t, model.Graduated, kubectl.Maturity)
+	var repos []model.ProjectRepo
+	require.NoError(t, conn.Where("project_id = ?", k8s.ID).Order("url").Find(&repos).Error)
+	require.Len(t, repos, 2)
+	require.Equal(t, "https://github.com/kubernetes/kubectl", repos[0].URL)
+
+	again, err := ImportLandscape(ctx, conn, landscape, false)
+	require.NoError(t, err)
+	require.Empty(t, again.Created)
+	require.Empty(t, again.Changed)
+
+	require.NoError(t, conn.Model(&model.AuditLog{}).Where("action = ?", "UPDATE_PROJECTS").Count(&count).Error)
+	require.EqualValues(t, 2, count)
+}
+
+func TestImportLandscapeCollisions(t *testing.T) {
+	conn, err := gorm.Open(sqlite.Open("file:landscape_collisions?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, conn.Create(&model.Project{Name: "OPA", Maturity: model.Graduated}).Error)
+
+	var landscape Landscape
+	require.NoError(t, yaml.Unmarshal([]byte(`
+landscape:
+  - name: Security
+    subcategories:
+      - name: Policy
+        items:
+          - name: Open Policy Agent (OPA)
+            repo_url: https://github.com/open-policy-agent/opa
+            project: graduated
+          - name: OPA
+            repo_url: https://github.com/open-policy-agent/gatekeeper
+            project: incubating
+          - name: Cloud Custodian
+            repo_url: https://github.com/cloud-custodian/cloud-custodian
+            project: incubating
+          - name: cloud-custodian
+            repo_url: https://github.com/cloud-custodian/c7n-org
+            project: sandbox
+`), &landscape))
+
+	for _, dryRun := range []bool{true, false} {
+		report, err := ImportLandscape(context.Background(), conn, &landscape, dryRun)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Cloud Custodian"}, report.Created)
+		require.Equal(t, []NameCollision{
+			{Landscape: "OPA", Earlier: "Open Policy Agent (OPA)", Registry: "OPA"},
+			{Landscape: "cloud-custodian", Earlier: "Cloud Custodian", Registry: "Cloud Custodian"},
+		}, report.Collisions)
+	}
+
+	var opa model.Project
+	require.NoError(t, conn.Where("name = ?", "OPA").First(&opa).Error)
+	require.Equal(t, model.Graduated, opa.Maturity, "the later landscape item does not overwrite the earlier")
+	var urls []string
+	require.NoError(t, conn.Model(&model.ProjectRepo{}).Order("url").Pluck("url", &urls).Error)
+	require.Equal(t, []string{"https://github.com/cloud-custodian/cloud-custodian", "https://github.com/open-policy-agent/opa"}, urls)
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB is an in-memory SQLite database seeded with testdata/fixtures.yaml.
+var testDB *gorm.DB
+
+func TestMain(m *testing.M) {
+	if err := setupTestDB(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func setupTestDB() error {
+	var err error
+	testDB, err = gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return fmt.Errorf("open test db: %w", err)
+	}
+	if err := Migrate(testDB); err != nil {
+		return err
+	}
+	if err := RegisterAuditCallbacks(testDB); err != nil {
+		return err
+	}
+	f, err := LoadFixtures("testdata/fixtures.yaml")
+	if err != nil {
+		return err
+	}
+	return SeedFixtures(context.Background(), testDB, f)
+}
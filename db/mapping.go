@@ -0,0 +1,220 @@
+package db
+
+import (
+	"fmt"
+	"maintainerd/model"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys added to every row read through a SheetMapping, alongside the header keys, saying where the row came from.
+const (
+	SheetTabKey = "maintainerd:tab"
+	SheetRowKey = "maintainerd:row"
+)
+
+// DefaultSheetTab is the tab read when no mapping file is given.
+var DefaultSheetTab = SheetTab{Name: "Active", Range: "A1:J2100", Status: model.ActiveMaintainer}
+
+// SheetMapping says which worksheet column feeds which maintainerd field and which tabs of the worksheet are read.
+//
+//	columns:
+//	  email: Email Address
+//	  github: GitHub Handle
+//	tabs:
+//	  - name: Active
+//	    range: A1:L2100
+//	    status: Active
+//	  - name: Emeritus
+//	    status: Emeritus
+type SheetMapping struct {
+	Columns ColumnMapping `yaml:"columns"`
+	Tabs    []SheetTab    `yaml:"tabs"`
+}
+
+// ColumnMapping holds the header of the column that feeds each field. Fields left empty use the header maintainerd
+// has always expected, e.g. Emails for the maintainer's email.
+type ColumnMapping struct {
+	Project          string `yaml:"project"`
+	Status           string `yaml:"status"` // project maturity
+	ParentProject    string `yaml:"parent_project"`
+	MaintainerName   string `yaml:"maintainer_name"`
+	Company          string `yaml:"company"`
+	Email            string `yaml:"email"`
+	GitHub           string `yaml:"github"`
+	GitHubEmail      string `yaml:"github_email"`
+	MaintainerRef    string `yaml:"maintainer_ref"`
+	MailingList      string `yaml:"mailing_list"`
+	MaintainerStatus string `yaml:"maintainer_status"`
+	ImportWarnings   string `yaml:"import_warnings"`
+}
+
+// SheetTab is a tab of the worksheet and the status given to maintainers in it who have no Maintainer Status of their
+// own.
+type SheetTab struct {
+	Name   string                 `yaml:"name"`
+	Range  string                 `yaml:"range"` // A1 range within the tab, the whole tab when empty
+	Status model.MaintainerStatus `yaml:"status"`
+}
+
+// A1Range returns the range of t including its name, e.g. Active!A1:J2100.
+func (t SheetTab) A1Range() string {
+	name := t.Name
+	if strings.ContainsAny(name, " '!") {
+		name = "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+	if t.Range == "" {
+		return name
+	}
+	return name + "!" + t.Range
+}
+
+// SheetTabFromRange returns the tab read by an A1 range such as Active!A1:J2100 with DefaultSheetTab's status.
+func SheetTabFromRange(readRange string) SheetTab {
+	tab := SheetTab{Name: readRange, Status: DefaultSheetTab.Status}
+	if i := strings.LastIndex(readRange, "!"); i >= 0 {
+		tab.Name, tab.Range = readRange[:i], readRange[i+1:]
+	}
+	if strings.HasPrefix(tab.Name, "'") && strings.HasSuffix(tab.Name, "'") && len(tab.Name) > 1 {
+		tab.Name = strings.ReplaceAll(tab.Name[1:len(tab.Name)-1], "''", "'")
+	}
+	return tab
+}
+
+// DefaultSheetMapping returns the mapping for the worksheet layout maintainerd was written against.
+func DefaultSheetMapping() *SheetMapping {
+	m := &SheetMapping{}
+	m.setDefaults()
+	return m
+}
+
+// LoadSheetMapping reads a SheetMapping from the YAML file at path. Columns and tabs it leaves out take their
+// defaults.
+func LoadSheetMapping(path string) (*SheetMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadSheetMapping: %w", err)
+	}
+	var m SheetMapping
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("LoadSheetMapping: %s: %w", path, err)
+	}
+	m.setDefaults()
+	for _, tab := range m.Tabs {
+		if tab.Name == "" {
+			return nil, fmt.Errorf("LoadSheetMapping: %s: every tab needs a name", path)
+		}
+		if !tab.Status.IsValid() {
+			return nil, fmt.Errorf("LoadSheetMapping: %s: tab %s has invalid status %q", path, tab.Name, tab.Status)
+		}
+	}
+	return &m, nil
+}
+
+// OnlyTab returns a copy of m that reads the tab called name, keeping its status if m lists it.
+func (m *SheetMapping) OnlyTab(name string) *SheetMapping {
+	only := *m
+	tab := SheetTab{Name: name, Status: DefaultSheetTab.Status}
+	for _, t := range m.Tabs {
+		if t.Name == name {
+			tab = t
+		}
+	}
+	only.Tabs = []SheetTab{tab}
+	return &only
+}
+
+func (m *SheetMapping) setDefaults() {
+	c := &m.Columns
+	for _, f := range []struct {
+		field *string
+		hdr   string
+	}{
+		{&c.Project, ProjectHdr},
+		{&c.Status, StatusHdr},
+		{&c.ParentProject, ParentProjectHdr},
+		{&c.MaintainerName, MaintainerNameHdr},
+		{&c.Company, CompanyNameHdr},
+		{&c.Email, EmailHdr},
+		{&c.GitHub, GitHubHdr},
+		{&c.GitHubEmail, GitHubEmail},
+		{&c.MaintainerRef, MaintainerFileRefHdr},
+		{&c.MailingList, MailingListAddrHdr},
+		{&c.MaintainerStatus, MaintainerStatusHdr},
+		{&c.ImportWarnings, ImportWarningsHdr},
+	} {
+		if *f.field == "" {
+			*f.field = f.hdr
+		}
+	}
+	if len(m.Tabs) == 0 {
+		m.Tabs = []SheetTab{DefaultSheetTab}
+	}
+	for i := range m.Tabs {
+		if m.Tabs[i].Status == "" {
+			m.Tabs[i].Status = model.ActiveMaintainer
+		}
+	}
+}
+
+// headers returns the sheet header for each of maintainerd's header constants.
+func (c ColumnMapping) headers() map[string]string {
+	return map[string]string{
+		c.Project:          ProjectHdr,
+		c.Status:           StatusHdr,
+		c.ParentProject:    ParentProjectHdr,
+		c.MaintainerName:   MaintainerNameHdr,
+		c.Company:          CompanyNameHdr,
+		c.Email:            EmailHdr,
+		c.GitHub:           GitHubHdr,
+		c.GitHubEmail:      GitHubEmail,
+		c.MaintainerRef:    MaintainerFileRefHdr,
+		c.MailingList:      MailingListAddrHdr,
+		c.MaintainerStatus: MaintainerStatusHdr,
+		c.ImportWarnings:   ImportWarningsHdr,
+	}
+}
+
+// canonicalHeaders returns header renamed to maintainerd's header constants using c.
+func (c ColumnMapping) canonicalHeaders(header []string) []string {
+	renames := c.headers()
+	canonical := make([]string, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if to, ok := renames[h]; ok {
+			h = to
+		}
+		canonical[i] = h
+	}
+	return canonical
+}
+
+// tabRows returns values read from tab, whose header row is row headerRow of the tab, as rows keyed by maintainerd's
+// header constants. Each row records its tab and row number and maintainers without a Maintainer Status of their own
+// take the tab's.
+func (m *SheetMapping) tabRows(tab SheetTab, headerRow int, values [][]string) []map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	values = append([][]string{m.Columns.canonicalHeaders(values[0])}, values[1:]...)
+	rows := tableRows(values)
+	for i, row := range rows {
+		row[SheetTabKey] = tab.Name
+		row[SheetRowKey] = strconv.Itoa(headerRow + 1 + i)
+		if row[MaintainerStatusHdr] == "" {
+			row[MaintainerStatusHdr] = string(tab.Status)
+		}
+	}
+	return rows
+}
+
+// rowPosition returns the tab and row number recorded in row by tabRows, or "" and fallback.
+func rowPosition(row map[string]string, fallback int) (string, int) {
+	if n, err := strconv.Atoi(row[SheetRowKey]); err == nil {
+		return row[SheetTabKey], n
+	}
+	return row[SheetTabKey], fallback
+}
@@ -0,0 +1,949 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MemoryStore is a Store that keeps the registry in memory. It lets maintainerd be embedded, and onboarding and
+// plugins be exercised end to end, without a database file. Like SQLStore, every write is audited against the actor
+// and correlation id carried by its context.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	nextID       map[string]uint
+	services     map[uint]model.Service
+	companies    map[uint]model.Company
+	projects     map[uint]model.Project
+	maintainers  map[uint]model.Maintainer
+	members      map[uint]map[uint]bool // project id -> maintainer ids
+	serviceTeams map[uint]model.ServiceTeam
+	apiTokens    map[string]model.APIToken // token hash -> token
+	changes      map[uint]model.ChangeRequest
+	auditLogs    []model.AuditLog
+	webhooks     map[uint]model.WebhookSubscription
+	deliveries   map[uint]model.WebhookDelivery
+	cursor       *uint // last audit log id read for webhook events
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nextID:       map[string]uint{},
+		services:     map[uint]model.Service{},
+		companies:    map[uint]model.Company{},
+		projects:     map[uint]model.Project{},
+		maintainers:  map[uint]model.Maintainer{},
+		members:      map[uint]map[uint]bool{},
+		serviceTeams: map[uint]model.ServiceTeam{},
+		apiTokens:    map[string]model.APIToken{},
+		changes:      map[uint]model.ChangeRequest{},
+		webhooks:     map[uint]model.WebhookSubscription{},
+		deliveries:   map[uint]model.WebhookDelivery{},
+	}
+}
+
+// NewMemoryStoreFromFile returns a MemoryStore loaded with the YAML fixtures at path.
+func NewMemoryStoreFromFile(path string) (*MemoryStore, error) {
+	f, err := LoadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMemoryStore()
+	if err := m.Load(f); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load adds f to the store. Loading is not audited.
+func (m *MemoryStore) Load(f *Fixtures) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	services := map[string]uint{}
+	for _, sf := range f.Services {
+		svc := model.Service{Model: m.newModel("services", now), Name: sf.Name, Description: sf.Description}
+		m.services[svc.ID] = svc
+		services[svc.Name] = svc.ID
+	}
+
+	companies := map[string]uint{}
+	for _, c := range m.companies {
+		companies[c.Name] = c.ID
+	}
+	company := func(name string) *uint {
+		if name == "" {
+			return nil
+		}
+		if id, ok := companies[name]; ok {
+			return &id
+		}
+		c := model.Company{Model: m.newModel("companies", now), Name: name}
+		m.companies[c.ID] = c
+		companies[name] = c.ID
+		return &c.ID
+	}
+	for _, name := range f.Companies {
+		company(name)
+	}
+
+	projects := map[string]*model.Project{}
+	for _, pf := range f.Projects {
+		p := pf.project()
+		p.Model = m.newModel("projects", now)
+		projects[p.Name] = &p
+	}
+	for _, pf := range f.Projects {
+		if pf.Parent == "" {
+			continue
+		}
+		parent, ok := projects[pf.Parent]
+		if !ok {
+			return fmt.Errorf("MemoryStore: project %s has unknown parent %s", pf.Name, pf.Parent)
+		}
+		projects[pf.Name].ParentProjectID = &parent.ID
+	}
+	for _, p := range projects {
+		m.projects[p.ID] = *p
+	}
+
+	for _, mf := range f.Maintainers {
+		maintainer := mf.maintainer()
+		maintainer.Model = m.newModel("maintainers", now)
+		maintainer.CompanyID = company(mf.Company)
+		m.maintainers[maintainer.ID] = maintainer
+		for _, name := range mf.Projects {
+			p, ok := projects[name]
+			if !ok {
+				return fmt.Errorf("MemoryStore: maintainer %s has unknown project %s", mf.Email, name)
+			}
+			if m.members[p.ID] == nil {
+				m.members[p.ID] = map[uint]bool{}
+			}
+			m.members[p.ID][maintainer.ID] = true
+		}
+	}
+
+	for _, tf := range f.ServiceTeams {
+		st, err := tf.serviceTeam(projects, services)
+		if err != nil {
+			return fmt.Errorf("MemoryStore: %w", err)
+		}
+		st.Model = m.newModel("service_teams", now)
+		m.serviceTeams[st.ID] = *st
+	}
+	return nil
+}
+
+// newModel returns a gorm.Model with the next id for table. Callers must hold the write lock.
+func (m *MemoryStore) newModel(table string, now time.Time) gorm.Model {
+	m.nextID[table]++
+	return gorm.Model{ID: m.nextID[table], CreatedAt: now, UpdatedAt: now}
+}
+
+// withCompany returns maintainer with its Company filled in. Callers must hold the lock.
+func (m *MemoryStore) withCompany(maintainer model.Maintainer) model.Maintainer {
+	if maintainer.CompanyID != nil {
+		maintainer.Company = m.companies[*maintainer.CompanyID]
+	}
+	return maintainer
+}
+
+// projectMaintainers returns the maintainers of the project identified by projectID ordered by id. Callers must hold
+// the lock.
+func (m *MemoryStore) projectMaintainers(projectID uint) []model.Maintainer {
+	var maintainers []model.Maintainer
+	for id := range m.members[projectID] {
+		if maintainer, ok := m.maintainers[id]; ok {
+			maintainers = append(maintainers, m.withCompany(maintainer))
+		}
+	}
+	sort.Slice(maintainers, func(i, j int) bool { return maintainers[i].ID < maintainers[j].ID })
+	return maintainers
+}
+
+// sortedProjects returns every project ordered by name. Callers must hold the lock.
+func (m *MemoryStore) sortedProjects() []model.Project {
+	projects := make([]model.Project, 0, len(m.projects))
+	for _, p := range m.projects {
+		projects = append(projects, p)
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	return projects
+}
+
+func (m *MemoryStore) GetServiceByName(ctx context.Context, name string) (*model.Service, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, svc := range m.services {
+		if svc.Name == name {
+			return &svc, nil
+		}
+	}
+	return nil, fmt.Errorf("GetServiceByName: %s: %w", name, gorm.ErrRecordNotFound)
+}
+
+func (m *MemoryStore) GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	using := map[uint]bool{}
+	for _, st := range m.serviceTeams {
+		if st.ServiceID == serviceID {
+			using[st.ProjectID] = true
+		}
+	}
+	var projects []model.Project
+	for _, p := range m.sortedProjects() {
+		if using[p.ID] {
+			p.Maintainers = m.projectMaintainers(p.ID)
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+// GetProjectMaintainersMap returns a map keyed by the project id which holds a list of Maintainers
+// associated with that project.
+func (m *MemoryStore) GetProjectMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	services := make([]model.Service, 0, len(m.services))
+	for _, svc := range m.services {
+		services = append(services, svc)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	teams := make([]model.ServiceTeam, 0, len(m.serviceTeams))
+	for _, st := range m.serviceTeams {
+		teams = append(teams, st)
+	}
+	byProject := servicesByProject(services, teams)
+	result := make(map[uint]model.ProjectInfo, len(m.projects))
+	for _, p := range m.projects {
+		p.Maintainers = m.projectMaintainers(p.ID)
+		result[p.ID] = model.ProjectInfo{
+			Project:     p,
+			Maintainers: p.Maintainers,
+			Services:    byProject[p.ID],
+		}
+	}
+	return result, nil
+}
+
+func (m *MemoryStore) GetProjectIDMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
+	return m.GetProjectMaintainersMap(ctx)
+}
+
+func (m *MemoryStore) GetProjectMapByName(ctx context.Context) (map[string]model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	projectsByName := make(map[string]model.Project, len(m.projects))
+	for _, p := range m.projects {
+		projectsByName[p.Name] = p
+	}
+	return projectsByName, nil
+}
+
+func (m *MemoryStore) GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.projectMaintainers(projectID), nil
+}
+
+// GetSubprojects returns the projects whose parent is the project identified by parentID.
+func (m *MemoryStore) GetSubprojects(ctx context.Context, parentID uint) ([]model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var projects []model.Project
+	for _, p := range m.sortedProjects() {
+		if p.ParentProjectID != nil && *p.ParentProjectID == parentID {
+			projects = append(projects, p)
+		}
+	}
+	return projects, nil
+}
+
+// GetProjectTree returns the project identified by rootID with all of its subprojects beneath it.
+func (m *MemoryStore) GetProjectTree(ctx context.Context, rootID uint) (*model.ProjectTree, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tree, ok := buildProjectTree(m.sortedProjects(), rootID)
+	if !ok {
+		return nil, fmt.Errorf("GetProjectTree: %w: id %d", ErrProjectNotFound, rootID)
+	}
+	return tree, nil
+}
+
+// GetRootProject returns the top-most project in the hierarchy of the project identified by projectID.
+func (m *MemoryStore) GetRootProject(ctx context.Context, projectID uint) (*model.Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := map[uint]bool{}
+	id := projectID
+	for {
+		p, ok := m.projects[id]
+		if !ok {
+			return nil, fmt.Errorf("GetRootProject: %w: id %d", ErrProjectNotFound, id)
+		}
+		seen[p.ID] = true
+		if p.ParentProjectID == nil || seen[*p.ParentProjectID] {
+			return &p, nil
+		}
+		id = *p.ParentProjectID
+	}
+}
+
+// GetRolledUpMaintainers returns the maintainers of the project identified by projectID together with the
+// maintainers of all of its subprojects. Each maintainer is listed once.
+func (m *MemoryStore) GetRolledUpMaintainers(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
+	tree, err := m.GetProjectTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	seen := map[uint]bool{}
+	var maintainers []model.Maintainer
+	for _, id := range projectTreeIDs(*tree) {
+		for _, maintainer := range m.projectMaintainers(id) {
+			if !seen[maintainer.ID] {
+				seen[maintainer.ID] = true
+				maintainers = append(maintainers, maintainer)
+			}
+		}
+	}
+	sort.Slice(maintainers, func(i, j int) bool { return maintainers[i].Name < maintainers[j].Name })
+	return maintainers, nil
+}
+
+// GetProjectServiceTeamMap returns a map of projectID to ServiceTeams
+// for every Project that uses the service identified by serviceName
+func (m *MemoryStore) GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error) {
+	service, err := m.GetServiceByName(ctx, serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service, %s, by name: %v", serviceName, err)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := map[uint]*model.ServiceTeam{}
+	for _, st := range m.serviceTeams {
+		if st.ServiceID == service.ID {
+			st := st
+			result[st.ProjectID] = &st
+		}
+	}
+	return result, nil
+}
+
+// GetMaintainerMapByEmail returns a map of Maintainers keyed by email address
+func (m *MemoryStore) GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]model.Maintainer, len(m.maintainers))
+	for _, maintainer := range m.maintainers {
+		result[maintainer.Email] = maintainer
+	}
+	return result, nil
+}
+
+// GetMaintainerMapByID returns a map of Maintainers keyed by ID
+func (m *MemoryStore) GetMaintainerMapByID(ctx context.Context) (map[uint]model.Maintainer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[uint]model.Maintainer, len(m.maintainers))
+	for _, maintainer := range m.maintainers {
+		result[maintainer.ID] = maintainer
+	}
+	return result, nil
+}
+
+// GetMaintainerMapByGitHubAccount returns a map of Maintainers keyed by GitHub Account
+func (m *MemoryStore) GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]model.Maintainer, len(m.maintainers))
+	for _, maintainer := range m.maintainers {
+		result[maintainer.GitHubAccount] = maintainer
+	}
+	return result, nil
+}
+
+// GetMaintainerByGitHubAccount returns the maintainer whose GitHub account is account, ignoring case, with their
+// company and projects.
+func (m *MemoryStore) GetMaintainerByGitHubAccount(ctx context.Context, account string) (*model.Maintainer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, maintainer := range m.maintainers {
+		if !strings.EqualFold(maintainer.GitHubAccount, account) {
+			continue
+		}
+		maintainer = m.withCompany(maintainer)
+		for _, p := range m.sortedProjects() {
+			if m.members[p.ID][maintainer.ID] {
+				maintainer.Projects = append(maintainer.Projects, p)
+			}
+		}
+		return &maintainer, nil
+	}
+	return nil, fmt.Errorf("GetMaintainerByGitHubAccount: %w: %s", ErrMaintainerNotFound, account)
+}
+
+// Ping always succeeds, a MemoryStore cannot become unreachable.
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// ListServices returns every service ordered by name.
+func (m *MemoryStore) ListServices(ctx context.Context) ([]model.Service, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	services := make([]model.Service, 0, len(m.services))
+	for _, svc := range m.services {
+		services = append(services, svc)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services, nil
+}
+
+func (m *MemoryStore) GetServiceTeamByProject(ctx context.Context, projectID, serviceID uint) (*model.ServiceTeam, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, st := range m.serviceTeams {
+		if st.ProjectID == projectID && st.ServiceID == serviceID {
+			return &st, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MemoryStore) LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error {
+	if event.Message == "" {
+		event.Message = event.Action
+	}
+	if event.Actor == "" {
+		event.Actor = ActorFromContext(ctx)
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = CorrelationIDFromContext(ctx)
+	}
+
+	m.mu.Lock()
+	event.Model = m.newModel(auditLogTable, time.Now())
+	m.auditLogs = append(m.auditLogs, event)
+	m.mu.Unlock()
+
+	logger.Infow("audit log recorded",
+		"project_id", event.ProjectID,
+		"maintainer_id", event.MaintainerID,
+		"service_id", event.ServiceID,
+		"action", event.Action,
+		"actor", event.Actor,
+		"correlation_id", event.CorrelationID,
+		"message", event.Message,
+	)
+	return nil
+}
+
+// ListAuditLogs returns the audit entries that match filter, newest first, or oldest first by id when filter.AfterID
+// is set.
+func (m *MemoryStore) ListAuditLogs(ctx context.Context, filter AuditFilter) ([]model.AuditLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	// entries are appended in id order
+	next, at := -1, len(m.auditLogs)-1
+	if filter.AfterID > 0 {
+		next, at = 1, 0
+	}
+	var entries []model.AuditLog
+	for i := at; i >= 0 && i < len(m.auditLogs); i += next {
+		e := m.auditLogs[i]
+		switch {
+		case filter.ProjectID != nil && e.ProjectID != *filter.ProjectID,
+			filter.MaintainerID != nil && (e.MaintainerID == nil || *e.MaintainerID != *filter.MaintainerID),
+			filter.ServiceID != nil && (e.ServiceID == nil || *e.ServiceID != *filter.ServiceID),
+			filter.Action != "" && e.Action != filter.Action,
+			filter.Actor != "" && e.Actor != filter.Actor,
+			filter.CorrelationID != "" && e.CorrelationID != filter.CorrelationID,
+			!filter.Since.IsZero() && e.CreatedAt.Before(filter.Since),
+			!filter.Until.IsZero() && !e.CreatedAt.Before(filter.Until),
+			e.ID <= filter.AfterID:
+			continue
+		}
+		entries = append(entries, e)
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// CreateServiceTeam creates or retrieves the service team for the remote team identified by serviceID.
+func (m *MemoryStore) CreateServiceTeam(ctx context.Context,
+	projectID uint, projectName string,
+	serviceID int, serviceName string) (*model.ServiceTeam, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, st := range m.serviceTeams {
+		if st.ServiceTeamID == serviceID {
+			return &st, nil
+		}
+	}
+
+	fossaID := uint(1) // TODO : Hardcoded to FOSSA for now, as in SQLStore
+	for _, svc := range m.services {
+		if svc.Name == "FOSSA" {
+			fossaID = svc.ID
+		}
+	}
+	st := model.ServiceTeam{
+		Model:           m.newModel("service_teams", time.Now()),
+		ServiceTeamID:   serviceID,
+		ServiceID:       fossaID,
+		ServiceTeamName: &serviceName,
+		ProjectID:       projectID,
+		ProjectName:     &projectName,
+	}
+	m.serviceTeams[st.ID] = st
+	if err := m.audit(ctx, AuditActionCreate, "service_teams", nil, st, st.ProjectID, nil, &st.ServiceID); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// AddMaintainerToProject makes maintainer a maintainer of the project identified by projectID. An existing maintainer
+// with the same GitHub account, or else email, is used in place of creating one, keeping their status. The
+// maintainer's company is set to company when it is not empty.
+func (m *MemoryStore) AddMaintainerToProject(ctx context.Context, projectID uint, maintainer model.Maintainer, company string) (*model.Maintainer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.projects[projectID]; !ok {
+		return nil, fmt.Errorf("AddMaintainerToProject: %w: id %d", ErrProjectNotFound, projectID)
+	}
+
+	found := false
+	for _, match := range []func(model.Maintainer) bool{
+		func(e model.Maintainer) bool {
+			return !MissingValue(maintainer.GitHubAccount) && strings.EqualFold(e.GitHubAccount, maintainer.GitHubAccount)
+		},
+		func(e model.Maintainer) bool { return !MissingValue(maintainer.Email) && e.Email == maintainer.Email },
+	} {
+		for _, existing := range m.maintainers {
+			if match(existing) {
+				maintainer, found = existing, true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		maintainer.Model = m.newModel("maintainers", time.Now())
+		if maintainer.MaintainerStatus == "" {
+			maintainer.MaintainerStatus = model.ActiveMaintainer
+		}
+		m.maintainers[maintainer.ID] = maintainer
+		if err := m.audit(ctx, AuditActionCreate, "maintainers", nil, maintainer, 0, &maintainer.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if company != "" {
+		if err := m.setMaintainerCompany(ctx, maintainer.ID, company); err != nil {
+			return nil, fmt.Errorf("AddMaintainerToProject: %w", err)
+		}
+	}
+	if !m.members[projectID][maintainer.ID] {
+		if m.members[projectID] == nil {
+			m.members[projectID] = map[uint]bool{}
+		}
+		m.members[projectID][maintainer.ID] = true
+		membership := model.MaintainerProject{MaintainerID: maintainer.ID, ProjectID: projectID, JoinedAt: time.Now()}
+		if err := m.audit(ctx, AuditActionCreate, "maintainer_projects", nil, membership, projectID, &maintainer.ID, nil); err != nil {
+			return nil, err
+		}
+	}
+	result := m.withCompany(m.maintainers[maintainer.ID])
+	return &result, nil
+}
+
+// RemoveMaintainerFromProject removes the maintainer identified by maintainerID from the project identified by
+// projectID. The maintainer themselves is kept.
+func (m *MemoryStore) RemoveMaintainerFromProject(ctx context.Context, projectID, maintainerID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.members[projectID][maintainerID] {
+		return fmt.Errorf("RemoveMaintainerFromProject: %w", ErrNotProjectMaintainer)
+	}
+	delete(m.members[projectID], maintainerID)
+	membership := model.MaintainerProject{MaintainerID: maintainerID, ProjectID: projectID}
+	return m.audit(ctx, AuditActionDelete, "maintainer_projects", membership, nil, projectID, &maintainerID, nil)
+}
+
+// SetMaintainerStatus sets the status of the maintainer identified by maintainerID.
+func (m *MemoryStore) SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("SetMaintainerStatus: invalid status %q", status)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	before, ok := m.maintainers[maintainerID]
+	if !ok {
+		return fmt.Errorf("SetMaintainerStatus: %w: id %d", ErrMaintainerNotFound, maintainerID)
+	}
+	if before.MaintainerStatus == status {
+		return nil
+	}
+	after := before
+	after.MaintainerStatus = status
+	after.UpdatedAt = time.Now()
+	m.maintainers[maintainerID] = after
+	return m.audit(ctx, AuditActionUpdate, "maintainers", before, after, 0, &maintainerID, nil)
+}
+
+// SetMaintainerCompany sets the company of the maintainer identified by maintainerID, registering the company if it
+// is new.
+func (m *MemoryStore) SetMaintainerCompany(ctx context.Context, maintainerID uint, company string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.setMaintainerCompany(ctx, maintainerID, company); err != nil {
+		return fmt.Errorf("SetMaintainerCompany: %w", err)
+	}
+	return nil
+}
+
+// setMaintainerCompany is SetMaintainerCompany for callers that hold the write lock.
+func (m *MemoryStore) setMaintainerCompany(ctx context.Context, maintainerID uint, name string) error {
+	if name == "" {
+		return errors.New("a company name is required")
+	}
+	before, ok := m.maintainers[maintainerID]
+	if !ok {
+		return fmt.Errorf("%w: id %d", ErrMaintainerNotFound, maintainerID)
+	}
+	var company *model.Company
+	for _, c := range m.companies {
+		if c.Name == name {
+			company = &c
+			break
+		}
+	}
+	if company == nil {
+		company = &model.Company{Model: m.newModel("companies", time.Now()), Name: name}
+		m.companies[company.ID] = *company
+		if err := m.audit(ctx, AuditActionCreate, "companies", nil, *company, 0, nil, nil); err != nil {
+			return err
+		}
+	}
+	if before.CompanyID != nil && *before.CompanyID == company.ID {
+		return nil
+	}
+	after := before
+	after.CompanyID = &company.ID
+	after.UpdatedAt = time.Now()
+	m.maintainers[maintainerID] = after
+	return m.audit(ctx, AuditActionUpdate, "maintainers", before, after, 0, &maintainerID, nil)
+}
+
+// AddAPIToken lets token authenticate officer, like IssueAPIToken does for a database. It is not audited.
+func (m *MemoryStore) AddAPIToken(officer model.FoundationOfficer, token string, expiresAt *time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiTokens[HashAPIToken(token)] = model.APIToken{
+		Model:             m.newModel("api_tokens", time.Now()),
+		FoundationOfficer: officer,
+		TokenHash:         HashAPIToken(token),
+		ExpiresAt:         expiresAt,
+	}
+}
+
+// AuthenticateAPIToken returns the foundation officer that token was issued to.
+func (m *MemoryStore) AuthenticateAPIToken(ctx context.Context, token string) (*model.FoundationOfficer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	record, ok := m.apiTokens[HashAPIToken(token)]
+	if !ok || !apiTokenUsable(record, time.Now()) {
+		return nil, ErrInvalidAPIToken
+	}
+	officer := record.FoundationOfficer
+	return &officer, nil
+}
+
+// RequestChange queues req for a foundation officer to review. Service access can only be asked for on projects the
+// maintainer maintains. A change the maintainer already has pending is returned rather than queued twice.
+func (m *MemoryStore) RequestChange(ctx context.Context, req model.ChangeRequest) (*model.ChangeRequest, error) {
+	if err := validateChangeRequest(&req); err != nil {
+		return nil, fmt.Errorf("RequestChange: %w", err)
+	}
+	req.Status = model.ChangePending
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.maintainers[req.MaintainerID]; !ok {
+		return nil, fmt.Errorf("RequestChange: %w: id %d", ErrMaintainerNotFound, req.MaintainerID)
+	}
+	if req.Kind == model.ChangeServiceAccess {
+		if !m.members[*req.ProjectID][req.MaintainerID] {
+			return nil, fmt.Errorf("RequestChange: %w", ErrNotProjectMaintainer)
+		}
+		if _, ok := m.services[*req.ServiceID]; !ok {
+			return nil, fmt.Errorf("RequestChange: service id %d: %w", *req.ServiceID, gorm.ErrRecordNotFound)
+		}
+	}
+	for _, p := range m.changes {
+		if p.Status == model.ChangePending && sameChange(p, req) {
+			p.Maintainer = model.Maintainer{}
+			return &p, nil
+		}
+	}
+	req.Model = m.newModel("change_requests", time.Now())
+	req.Maintainer = model.Maintainer{}
+	m.changes[req.ID] = req
+	projectID := uint(0)
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	if err := m.audit(ctx, AuditActionCreate, "change_requests", nil, req, projectID, &req.MaintainerID, req.ServiceID); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// ListChangeRequests returns the change requests that match filter with their maintainers, newest first.
+func (m *MemoryStore) ListChangeRequests(ctx context.Context, filter ChangeRequestFilter) ([]model.ChangeRequest, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var requests []model.ChangeRequest
+	for _, req := range m.changes {
+		if filter.MaintainerID != nil && req.MaintainerID != *filter.MaintainerID ||
+			filter.Status != "" && req.Status != filter.Status {
+			continue
+		}
+		req.Maintainer = m.withCompany(m.maintainers[req.MaintainerID])
+		requests = append(requests, req)
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].ID > requests[j].ID })
+	if filter.Limit > 0 && len(requests) > filter.Limit {
+		requests = requests[:filter.Limit]
+	}
+	return requests, nil
+}
+
+// ReviewChangeRequest approves or rejects the pending change request identified by id, recording the actor found in
+// ctx as the reviewer and note as the reason. Approving an email or company change applies it to the maintainer, an
+// email address another maintainer has is refused with ErrEmailInUse and the request left pending. Approving service
+// access only records the approval, onboarding the project is left to the caller.
+func (m *MemoryStore) ReviewChangeRequest(ctx context.Context, id uint, approve bool, note string) (*model.ChangeRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	before, ok := m.changes[id]
+	if !ok {
+		return nil, fmt.Errorf("ReviewChangeRequest: %w: id %d", ErrChangeRequestNotFound, id)
+	}
+	if before.Status != model.ChangePending {
+		return nil, fmt.Errorf("ReviewChangeRequest: %w: id %d is %s", ErrChangeRequestReviewed, id, before.Status)
+	}
+	after := before
+	after.Status = model.ChangeRejected
+	if approve {
+		after.Status = model.ChangeApproved
+		switch before.Kind {
+		case model.ChangeEmail:
+			for _, other := range m.maintainers {
+				if other.ID != before.MaintainerID && strings.EqualFold(other.Email, before.Value) {
+					return nil, fmt.Errorf("ReviewChangeRequest: %w: %s", ErrEmailInUse, before.Value)
+				}
+			}
+			maintainer := m.maintainers[before.MaintainerID]
+			if maintainer.Email != before.Value {
+				updated := maintainer
+				updated.Email = before.Value
+				updated.UpdatedAt = time.Now()
+				m.maintainers[maintainer.ID] = updated
+				if err := m.audit(ctx, AuditActionUpdate, "maintainers", maintainer, updated, 0, &maintainer.ID, nil); err != nil {
+					return nil, err
+				}
+			}
+		case model.ChangeCompany:
+			if err := m.setMaintainerCompany(ctx, before.MaintainerID, before.Value); err != nil {
+				return nil, fmt.Errorf("ReviewChangeRequest: %w", err)
+			}
+		}
+	}
+	now := time.Now()
+	after.ReviewedBy = ActorFromContext(ctx)
+	after.ReviewedAt = &now
+	after.Note = note
+	after.UpdatedAt = now
+	m.changes[id] = after
+	projectID := uint(0)
+	if after.ProjectID != nil {
+		projectID = *after.ProjectID
+	}
+	if err := m.audit(ctx, AuditActionUpdate, "change_requests", before, after, projectID, &after.MaintainerID, after.ServiceID); err != nil {
+		return nil, err
+	}
+	after.Maintainer = m.withCompany(m.maintainers[after.MaintainerID])
+	return &after, nil
+}
+
+// CreateWebhookSubscription registers sub, recording the actor found in ctx as its creator. It is sent the events
+// recorded from then on.
+func (m *MemoryStore) CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(&sub); err != nil {
+		return nil, fmt.Errorf("CreateWebhookSubscription: %w: %v", ErrInvalidWebhookSubscription, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub.Model = m.newModel("webhook_subscriptions", time.Now())
+	sub.CreatedBy = ActorFromContext(ctx)
+	m.webhooks[sub.ID] = sub
+	if err := m.audit(ctx, AuditActionCreate, "webhook_subscriptions", nil, redactSubscription(sub), 0, nil, nil); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// redactSubscription returns sub without its secret, as it is written to the audit log.
+func redactSubscription(sub model.WebhookSubscription) model.WebhookSubscription {
+	sub.Secret = redacted
+	return sub
+}
+
+// ListWebhookSubscriptions returns every webhook subscription, oldest first.
+func (m *MemoryStore) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	subs := make([]model.WebhookSubscription, 0, len(m.webhooks))
+	for _, sub := range m.webhooks {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+// DeleteWebhookSubscription deletes the webhook subscription identified by id, its pending deliveries are failed.
+func (m *MemoryStore) DeleteWebhookSubscription(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sub, ok := m.webhooks[id]
+	if !ok {
+		return fmt.Errorf("DeleteWebhookSubscription: %w: id %d", ErrWebhookSubscriptionNotFound, id)
+	}
+	delete(m.webhooks, id)
+	for _, d := range m.deliveries {
+		if d.SubscriptionID == id && d.Status == model.DeliveryPending {
+			d.Status = model.DeliveryFailed
+			d.NextAttemptAt = nil
+			d.LastError = webhookSubscriptionDeleted
+			m.deliveries[d.ID] = d
+		}
+	}
+	return m.audit(ctx, AuditActionDelete, "webhook_subscriptions", redactSubscription(sub), nil, 0, nil, nil)
+}
+
+// WebhookCursor returns the id of the last audit log entry read for webhook events. Until one has been read it is the
+// newest entry, so that a registry's history is not sent.
+func (m *MemoryStore) WebhookCursor(ctx context.Context) (uint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cursor != nil {
+		return *m.cursor, nil
+	}
+	if len(m.auditLogs) == 0 {
+		return 0, nil
+	}
+	return m.auditLogs[len(m.auditLogs)-1].ID, nil
+}
+
+// EnqueueWebhookDeliveries queues deliveries and moves the webhook cursor to the audit log entry identified by cursor.
+func (m *MemoryStore) EnqueueWebhookDeliveries(ctx context.Context, cursor uint, deliveries []model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, d := range deliveries {
+		d = newWebhookDelivery(d, now)
+		d.Model = m.newModel(webhookDeliveryTable, now)
+		m.deliveries[d.ID] = d
+	}
+	m.cursor = &cursor
+	return nil
+}
+
+// ListWebhookDeliveries returns the webhook deliveries that match filter newest first, or oldest first when DueBy is
+// set.
+func (m *MemoryStore) ListWebhookDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]model.WebhookDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var deliveries []model.WebhookDelivery
+	for _, d := range m.deliveries {
+		switch {
+		case filter.SubscriptionID != nil && d.SubscriptionID != *filter.SubscriptionID,
+			filter.Status != "" && d.Status != filter.Status,
+			!filter.DueBy.IsZero() && (d.Status != model.DeliveryPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(filter.DueBy)):
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !filter.DueBy.IsZero() {
+			return deliveries[i].ID < deliveries[j].ID
+		}
+		return deliveries[i].ID > deliveries[j].ID
+	})
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt to send d.
+func (m *MemoryStore) UpdateWebhookDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.deliveries[d.ID]
+	if !ok {
+		return fmt.Errorf("UpdateWebhookDelivery: id %d: %w", d.ID, gorm.ErrRecordNotFound)
+	}
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.NextAttemptAt = d.NextAttemptAt
+	stored.ResponseStatus = d.ResponseStatus
+	stored.LastError = d.LastError
+	stored.DeliveredAt = d.DeliveredAt
+	stored.UpdatedAt = time.Now()
+	m.deliveries[d.ID] = stored
+	return nil
+}
+
+// audit records a write in the same form as the SQLStore audit callbacks. Callers must hold the write lock.
+func (m *MemoryStore) audit(ctx context.Context, action, table string, before, after any, projectID uint, maintainerID, serviceID *uint) error {
+	blob, err := json.Marshal(map[string]any{"table": table, "before": before, "after": after})
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode %s on %s: %w", action, table, err)
+	}
+	m.auditLogs = append(m.auditLogs, model.AuditLog{
+		Model:         m.newModel(auditLogTable, time.Now()),
+		ProjectID:     projectID,
+		MaintainerID:  maintainerID,
+		ServiceID:     serviceID,
+		Action:        action + "_" + strings.ToUpper(table),
+		Actor:         ActorFromContext(ctx),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Message:       fmt.Sprintf("%s %s", strings.ToLower(action), table),
+		Metadata:      string(blob),
+	})
+	return nil
+}
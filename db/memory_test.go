@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestMemoryStore(t *testing.T) *MemoryStore {
+	t.Helper()
+	store, err := NewMemoryStoreFromFile("testdata/fixtures.yaml")
+	require.NoError(t, err)
+	return store
+}
+
+// TestMemoryStoreMatchesSQLStore checks that both stores answer the read queries the same way for the same fixtures.
+func TestMemoryStoreMatchesSQLStore(t *testing.T) {
+	ctx := context.Background()
+	stores := map[string]Store{
+		"sql":    NewSQLStore(testDB),
+		"memory": newTestMemoryStore(t),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			fossa, err := store.GetServiceByName(ctx, "FOSSA")
+			require.NoError(t, err)
+
+			_, err = store.GetServiceByName(ctx, "Snyk")
+			require.Error(t, err)
+
+			projects, err := store.GetProjectsUsingService(ctx, fossa.ID)
+			require.NoError(t, err)
+			require.Len(t, projects, 1)
+			require.Equal(t, "Kubernetes", projects[0].Name)
+			require.Len(t, projects[0].Maintainers, 1)
+
+			byName, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			require.Len(t, byName, 3)
+			k8s, kubectl := byName["Kubernetes"], byName["kubectl"]
+			require.NotNil(t, kubectl.ParentProjectID)
+			require.Equal(t, k8s.ID, *kubectl.ParentProjectID)
+
+			root, err := store.GetRootProject(ctx, kubectl.ID)
+			require.NoError(t, err)
+			require.Equal(t, "Kubernetes", root.Name)
+
+			subprojects, err := store.GetSubprojects(ctx, k8s.ID)
+			require.NoError(t, err)
+			require.Len(t, subprojects, 1)
+
+			infos, err := store.GetProjectMaintainersMap(ctx)
+			require.NoError(t, err)
+			require.Len(t, infos[k8s.ID].Maintainers, 1)
+			require.Equal(t, "Example Inc", infos[k8s.ID].Maintainers[0].Company.Name)
+
+			rolledUp, err := store.GetRolledUpMaintainers(ctx, k8s.ID)
+			require.NoError(t, err)
+			require.Len(t, rolledUp, 2)
+
+			teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+			require.NoError(t, err)
+			require.Equal(t, 42, teams[k8s.ID].ServiceTeamID)
+
+			st, err := store.GetServiceTeamByProject(ctx, byName["Jaeger"].ID, fossa.ID)
+			require.NoError(t, err)
+			require.Nil(t, st)
+
+			byEmail, err := store.GetMaintainerMapByEmail(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "janedoe", byEmail["jane@example.org"].GitHubAccount)
+
+			jane, err := store.GetMaintainerByGitHubAccount(ctx, "JaneDoe")
+			require.NoError(t, err)
+			require.Equal(t, "Example Inc", jane.Company.Name)
+			require.Len(t, jane.Projects, 1)
+			require.Equal(t, "Kubernetes", jane.Projects[0].Name)
+			_, err = store.GetMaintainerByGitHubAccount(ctx, "nobody")
+			require.ErrorIs(t, err, ErrMaintainerNotFound)
+
+			services, err := store.ListServices(ctx)
+			require.NoError(t, err)
+			require.Len(t, services, 1)
+			require.Equal(t, "FOSSA", services[0].Name)
+		})
+	}
+}
+
+func TestMemoryStoreAuditsWrites(t *testing.T) {
+	ctx := WithActor(context.Background(), "tester")
+	store := newTestMemoryStore(t)
+	byName, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	jaeger := byName["Jaeger"]
+
+	st, err := store.CreateServiceTeam(ctx, jaeger.ID, jaeger.Name, 7, "jaeger")
+	require.NoError(t, err)
+	again, err := store.CreateServiceTeam(ctx, jaeger.ID, jaeger.Name, 7, "jaeger")
+	require.NoError(t, err)
+	require.Equal(t, st.ID, again.ID)
+
+	require.NoError(t, store.LogAuditEvent(ctx, zap.NewNop().Sugar(), model.AuditLog{ProjectID: jaeger.ID, Action: "INVITE_SENT"}))
+
+	entries, err := store.ListAuditLogs(ctx, AuditFilter{ProjectID: &jaeger.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "INVITE_SENT", entries[0].Action)
+	require.Equal(t, "CREATE_SERVICE_TEAMS", entries[1].Action)
+	require.Equal(t, "tester", entries[1].Actor)
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"fmt"
+	"maintainerd/model"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// PrunePolicy says what a seed does with maintainers and memberships that are in the database but no longer in the
+// worksheet.
+type PrunePolicy string
+
+const (
+	// PruneNone only reports maintainers and memberships that have disappeared from the sheet.
+	PruneNone PrunePolicy = "none"
+	// PruneEmeritus marks Active maintainers that have disappeared from the sheet Emeritus, keeping their memberships.
+	PruneEmeritus PrunePolicy = "emeritus"
+	// PruneMemberships removes the memberships that have disappeared from the sheet, leaving maintainers' status alone.
+	PruneMemberships PrunePolicy = "remove-memberships"
+)
+
+// ParsePrunePolicy returns the PrunePolicy named by s, an empty s selects PruneNone.
+func ParsePrunePolicy(s string) (PrunePolicy, error) {
+	switch PrunePolicy(s) {
+	case "", PruneNone:
+		return PruneNone, nil
+	case PruneEmeritus:
+		return PruneEmeritus, nil
+	case PruneMemberships:
+		return PruneMemberships, nil
+	}
+	return "", fmt.Errorf("unknown prune policy %q, use %q, %q or %q", s, PruneNone, PruneEmeritus, PruneMemberships)
+}
+
+// sheetSeen records the maintainers, by email, and memberships, by membershipKey, named by the rows of a seed,
+// including rows that were skipped, so that they are not taken to have left the sheet.
+type sheetSeen struct {
+	maintainers map[string]bool
+	memberships map[string]bool
+}
+
+func newSheetSeen() *sheetSeen {
+	return &sheetSeen{maintainers: map[string]bool{}, memberships: map[string]bool{}}
+}
+
+func (s *sheetSeen) add(email, project string) {
+	if email == "" {
+		return
+	}
+	s.maintainers[email] = true
+	if project != "" {
+		s.memberships[membershipKey(email, project)] = true
+	}
+}
+
+// pruneMissing finds the Active maintainers and the memberships in db that seen does not name, records them in report
+// and applies policy to them in one transaction. Every change is audited by the callbacks on db.
+func pruneMissing(db *gorm.DB, seen *sheetSeen, policy PrunePolicy, report *ImportReport) error {
+	report.Prune = policy
+	return db.Transaction(func(tx *gorm.DB) error {
+		var maintainers []model.Maintainer
+		if err := tx.Preload("Projects").Find(&maintainers).Error; err != nil {
+			return fmt.Errorf("failed to read maintainers: %w", err)
+		}
+		sort.Slice(maintainers, func(i, j int) bool { return maintainers[i].Email < maintainers[j].Email })
+
+		for _, m := range maintainers {
+			if !seen.maintainers[m.Email] && m.MaintainerStatus.IsActive() {
+				report.Disappeared = append(report.Disappeared, m.Email)
+				if policy == PruneEmeritus {
+					if err := tx.Model(&m).Update("maintainer_status", model.EmeritusMaintainer).Error; err != nil {
+						return fmt.Errorf("failed to mark %s Emeritus: %w", m.Email, err)
+					}
+				}
+			}
+			for _, p := range m.Projects {
+				key := membershipKey(m.Email, p.Name)
+				if seen.memberships[key] {
+					continue
+				}
+				report.StaleMemberships = append(report.StaleMemberships, key)
+				if policy == PruneMemberships {
+					membership := model.MaintainerProject{MaintainerID: m.ID, ProjectID: p.ID}
+					if err := tx.Delete(&membership).Error; err != nil {
+						return fmt.Errorf("failed to remove %s: %w", key, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,328 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maintainerd/model"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SheetCellUpdate is a maintainer's cell in the worksheet whose value, Sheet, differs from maintainerd's, Value. Base
+// is the value the cell had when the two were last in agreement, if they ever were.
+type SheetCellUpdate struct {
+	Cell   string `json:"cell"` // A1 notation, including the sheet name
+	Email  string `json:"email"`
+	Column string `json:"column"`
+	Sheet  string `json:"sheet"`
+	Value  string `json:"value"`
+	Base   string `json:"base,omitempty"`
+}
+
+// SheetSyncReport lists the cells SyncSheet wrote, or would write in a dry run, and the cells it left alone because
+// they were changed in both the sheet and maintainerd.
+type SheetSyncReport struct {
+	Updates   []SheetCellUpdate `json:"updates"`
+	Conflicts []SheetCellUpdate `json:"conflicts"`
+	converged []SheetCellUpdate // cells that already agree, their Base is recorded
+}
+
+// sheetTable is a worksheet range read with its position so that cells can be written back.
+type sheetTable struct {
+	sheet    string // sheet name as written in the range, e.g. Active or 'Maintainer List'
+	firstRow int    // 1 based row number of the header row
+	firstCol int    // 0 based column index of the first column
+	headers  []string
+	rows     [][]string
+}
+
+// cell returns the A1 notation of column col of rows[row].
+func (t *sheetTable) cell(row, col int) string {
+	ref := columnName(t.firstCol+col) + strconv.Itoa(t.firstRow+1+row)
+	if t.sheet == "" {
+		return ref
+	}
+	return t.sheet + "!" + ref
+}
+
+func (t *sheetTable) column(header string) int {
+	for i, h := range t.headers {
+		if h == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// newSheetTable returns values read from readRange as a sheetTable, the first row being the header row, whose headers
+// are renamed to maintainerd's header constants by columns.
+func newSheetTable(readRange string, values [][]string, columns ColumnMapping) (*sheetTable, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s is empty", readRange)
+	}
+	col, row, err := rangeStart(readRange)
+	if err != nil {
+		return nil, err
+	}
+	t := &sheetTable{firstCol: col, firstRow: row}
+	if i := strings.LastIndex(readRange, "!"); i >= 0 {
+		t.sheet = readRange[:i]
+	}
+	t.headers = columns.canonicalHeaders(values[0])
+	t.rows = values[1:]
+	return t, nil
+}
+
+// rangeStart returns the 0 based column and 1 based row of the top left cell of an A1 range such as Active!B3:J100,
+// A1 when the range is a whole tab.
+func rangeStart(readRange string) (col, row int, err error) {
+	start := readRange
+	if i := strings.LastIndex(readRange, "!"); i >= 0 {
+		start = readRange[i+1:]
+	} else if !strings.ContainsAny(readRange, ":0123456789") {
+		return 0, 1, nil // a tab name
+	}
+	start, _, _ = strings.Cut(start, ":")
+	if start == "" {
+		return 0, 1, nil
+	}
+	letters := strings.TrimRight(strings.ToUpper(start), "0123456789")
+	if col, err = xlsxColumn(letters); err != nil {
+		return 0, 0, fmt.Errorf("invalid range %s: %w", readRange, err)
+	}
+	row = 1
+	if digits := start[len(letters):]; digits != "" {
+		if row, err = strconv.Atoi(digits); err != nil {
+			return 0, 0, fmt.Errorf("invalid range %s: %w", readRange, err)
+		}
+	}
+	return col, row, nil
+}
+
+// columnName returns the letters of the zero based column index col, e.g. 27 is AB.
+func columnName(col int) string {
+	var name []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+// SyncSheet writes what maintainerd knows about maintainers back to their rows in the tabs of mapping: GitHub handles
+// missing from the sheet, including those learnt from FOSSA, and, when the sheet has those columns, maintainer statuses
+// and import warnings. A cell is only written when the sheet still holds the value it had when the two last agreed, or
+// is blank if they never have; cells changed in both places are reported as conflicts and left alone. With dryRun
+// nothing is written. Every cell written is recorded in the audit log.
+func SyncSheet(ctx context.Context, db *gorm.DB, spreadsheetID, credentialsPath string, mapping *SheetMapping, dryRun bool) (*SheetSyncReport, error) {
+	srv, err := sheets.NewService(
+		ctx,
+		option.WithCredentialsFile(credentialsPath),
+		option.WithScopes(sheets.SpreadsheetsScope),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("SyncSheet: unable to retrieve Sheets client: %w", err)
+	}
+
+	report := &SheetSyncReport{Updates: []SheetCellUpdate{}, Conflicts: []SheetCellUpdate{}}
+	for _, tab := range mapping.Tabs {
+		readRange := tab.A1Range()
+		values, err := readSheetValues(ctx, srv, spreadsheetID, readRange)
+		if err != nil {
+			return nil, fmt.Errorf("SyncSheet: %w", err)
+		}
+		table, err := newSheetTable(readRange, values, mapping.Columns)
+		if err != nil {
+			return nil, fmt.Errorf("SyncSheet: %w", err)
+		}
+		tabReport, err := planSheetSync(ctx, db, table)
+		if err != nil {
+			return nil, err
+		}
+		report.Updates = append(report.Updates, tabReport.Updates...)
+		report.Conflicts = append(report.Conflicts, tabReport.Conflicts...)
+		report.converged = append(report.converged, tabReport.converged...)
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	if len(report.Updates) > 0 {
+		data := make([]*sheets.ValueRange, 0, len(report.Updates))
+		for _, u := range report.Updates {
+			data = append(data, &sheets.ValueRange{Range: u.Cell, Values: [][]interface{}{{u.Value}}})
+		}
+		if _, err := srv.Spreadsheets.Values.BatchUpdate(spreadsheetID, &sheets.BatchUpdateValuesRequest{
+			ValueInputOption: "RAW",
+			Data:             data,
+		}).Context(ctx).Do(); err != nil {
+			return nil, fmt.Errorf("SyncSheet: failed to write %d cells: %w", len(data), err)
+		}
+	}
+	if err := recordSheetSync(ctx, db, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// planSheetSync compares the maintainer cells of table with the database and returns the cells to write.
+func planSheetSync(ctx context.Context, db *gorm.DB, table *sheetTable) (*SheetSyncReport, error) {
+	emailCol := table.column(EmailHdr)
+	if emailCol < 0 {
+		return nil, fmt.Errorf("SyncSheet: the sheet has no %s column", EmailHdr)
+	}
+
+	known, err := sheetSyncValues(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var states []model.SheetSyncState
+	if err := db.WithContext(ctx).Find(&states).Error; err != nil {
+		return nil, fmt.Errorf("SyncSheet: failed to read sync state: %w", err)
+	}
+	base := make(map[[2]string]string, len(states))
+	for _, s := range states {
+		base[[2]string{s.Email, s.Column}] = s.Value
+	}
+
+	report := &SheetSyncReport{Updates: []SheetCellUpdate{}, Conflicts: []SheetCellUpdate{}}
+	for _, header := range []string{GitHubHdr, MaintainerStatusHdr, ImportWarningsHdr} {
+		col := table.column(header)
+		if col < 0 {
+			continue
+		}
+		for i, row := range table.rows {
+			email := strings.ToLower(strings.TrimSpace(cellAt(row, emailCol)))
+			values, ok := known[email]
+			if email == "" || !ok || values[header] == "" {
+				continue
+			}
+			u := SheetCellUpdate{
+				Cell:   table.cell(i, col),
+				Email:  email,
+				Column: header,
+				Sheet:  strings.TrimSpace(cellAt(row, col)),
+				Value:  values[header],
+			}
+			b, synced := base[[2]string{email, header}]
+			u.Base = b
+			switch {
+			case u.Sheet == u.Value:
+				report.converged = append(report.converged, u)
+			case synced && u.Sheet == b:
+				report.Updates = append(report.Updates, u) // changed in maintainerd only
+			case synced && u.Value == b:
+				// changed in the sheet only, the next seed picks it up
+			case !synced && u.Sheet == "":
+				report.Updates = append(report.Updates, u)
+			default:
+				report.Conflicts = append(report.Conflicts, u)
+			}
+		}
+	}
+	return report, nil
+}
+
+// sheetSyncValues returns the values maintainerd holds for the sheet's maintainer columns keyed by lower cased email
+// then header. Maintainers without a GitHub account take the one FOSSA has linked to them.
+func sheetSyncValues(ctx context.Context, db *gorm.DB) (map[string]map[string]string, error) {
+	var maintainers []model.Maintainer
+	if err := db.WithContext(ctx).Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("SyncSheet: failed to read maintainers: %w", err)
+	}
+
+	type fossaHandle struct {
+		MaintainerID      uint
+		ServiceGitHubName string
+	}
+	var handles []fossaHandle
+	if err := db.WithContext(ctx).
+		Table("service_user_teams").
+		Select("service_user_teams.maintainer_id, service_users.service_git_hub_name").
+		Joins("JOIN service_users ON service_users.service_user_id = service_user_teams.service_user_id " +
+			"AND service_users.service_id = service_user_teams.service_id").
+		Where("service_user_teams.maintainer_id IS NOT NULL AND service_users.service_git_hub_name <> ''").
+		Scan(&handles).Error; err != nil {
+		return nil, fmt.Errorf("SyncSheet: failed to read FOSSA GitHub handles: %w", err)
+	}
+	linked := make(map[uint]string, len(handles))
+	for _, h := range handles {
+		linked[h.MaintainerID] = h.ServiceGitHubName
+	}
+
+	known := make(map[string]map[string]string, len(maintainers))
+	for _, m := range maintainers {
+		github := m.GitHubAccount
+		if MissingValue(github) {
+			github = linked[m.ID]
+		}
+		known[strings.ToLower(m.Email)] = map[string]string{
+			GitHubHdr:           github,
+			MaintainerStatusHdr: string(m.MaintainerStatus),
+			ImportWarningsHdr:   m.ImportWarnings,
+		}
+	}
+	return known, nil
+}
+
+// MissingValue reports whether v is empty or one of the column defaults used for a value that was not provided.
+func MissingValue(v string) bool {
+	switch v {
+	case "", "GITHUB_MISSING", "EMAIL_MISSING", "GITHUB_EMAIL_MISSING", "MML_MISSING":
+		return true
+	}
+	return false
+}
+
+// recordSheetSync stores the agreed value of every written or converged cell and audits the written ones.
+func recordSheetSync(ctx context.Context, db *gorm.DB, report *SheetSyncReport) error {
+	cells := append(append([]SheetCellUpdate{}, report.Updates...), report.converged...)
+	sort.Slice(cells, func(i, j int) bool { return cells[i].Cell < cells[j].Cell })
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, u := range cells {
+			state := model.SheetSyncState{Email: u.Email, Column: u.Column, Value: u.Value}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "email"}, {Name: "column"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&state).Error; err != nil {
+				return fmt.Errorf("SyncSheet: failed to record %s: %w", u.Cell, err)
+			}
+		}
+		for _, u := range report.Updates {
+			var maintainer model.Maintainer
+			var maintainerID *uint
+			if err := tx.Where("LOWER(email) = ?", u.Email).First(&maintainer).Error; err == nil {
+				maintainerID = &maintainer.ID
+			}
+			blob, err := json.Marshal(u)
+			if err != nil {
+				return fmt.Errorf("SyncSheet: failed to encode %s: %w", u.Cell, err)
+			}
+			if err := tx.Create(&model.AuditLog{
+				MaintainerID:  maintainerID,
+				Action:        "SHEET_CELL_UPDATED",
+				Actor:         ActorFromContext(ctx),
+				CorrelationID: CorrelationIDFromContext(ctx),
+				Message:       fmt.Sprintf("wrote %s %q to %s", u.Column, u.Value, u.Cell),
+				Metadata:      string(blob),
+			}).Error; err != nil {
+				return fmt.Errorf("SyncSheet: failed to audit %s: %w", u.Cell, err)
+			}
+		}
+		return nil
+	})
+}
+
+func cellAt(row []string, col int) string {
+	if col < len(row) {
+		return row[col]
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSheetSync(t *testing.T) {
+	ctx := context.Background()
+	values := [][]string{
+		{MaintainerNameHdr, EmailHdr, GitHubHdr, MaintainerStatusHdr},
+		{"Jane Doe", "jane@example.org", "", "Active"},
+		{"John Roe", "John@Example.org", "john-roe", ""},
+		{"Someone Else", "else@example.org", "", ""},
+	}
+	table, err := newSheetTable("Active!B3:E100", values, DefaultSheetMapping().Columns)
+	require.NoError(t, err)
+	require.Equal(t, "Active!D4", table.cell(0, 2))
+	require.Equal(t, "AB", columnName(27))
+
+	report, err := planSheetSync(ctx, testDB, table)
+	require.NoError(t, err)
+	require.Equal(t, []SheetCellUpdate{
+		{Cell: "Active!D4", Email: "jane@example.org", Column: GitHubHdr, Value: "janedoe"},
+		{Cell: "Active!E5", Email: "john@example.org", Column: MaintainerStatusHdr, Value: "Active"},
+	}, report.Updates)
+	require.Equal(t, []SheetCellUpdate{
+		{Cell: "Active!D5", Email: "john@example.org", Column: GitHubHdr, Sheet: "john-roe", Value: "johnroe"},
+	}, report.Conflicts)
+	require.NoError(t, recordSheetSync(ctx, testDB, report))
+
+	// Once written the cells agree; a later change made only in the sheet is not overwritten
+	values[1][2] = "janedoe"
+	values[2][3] = "Emeritus"
+	report, err = planSheetSync(ctx, testDB, table)
+	require.NoError(t, err)
+	require.Empty(t, report.Updates)
+	require.Len(t, report.Conflicts, 1)
+
+	entries, err := NewSQLStore(testDB).ListAuditLogs(ctx, AuditFilter{Action: "SHEET_CELL_UPDATED"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
@@ -1,20 +1,46 @@
 package db
 
 import (
+	"context"
 	"go.uber.org/zap"
 	"maintainerd/model"
 )
 
 type Store interface {
-	GetProjectsUsingService(serviceID uint) ([]model.Project, error)
-	GetProjectMaintainersMap() (map[uint]model.ProjectInfo, error)
-	GetProjectMapByName() (map[string]model.Project, error)
-	GetMaintainersByProject(projectID uint) ([]model.Maintainer, error)
-	GetProjectServiceTeamMap(serviceName string) (map[uint]*model.ServiceTeam, error)
-	GetProjectIDMaintainersMap() (map[uint]model.ProjectInfo, error)
-	GetMaintainerMapByEmail() (map[string]model.Maintainer, error)
-	GetServiceTeamByProject(projectID uint, serviceID uint) (*model.ServiceTeam, error)
-	LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) error
-	GetMaintainerMapByGitHubAccount() (map[string]model.Maintainer, error)
-	CreateServiceTeamForUser(interface{ any }) (*model.ServiceTeam, error)
+	GetServiceByName(ctx context.Context, name string) (*model.Service, error)
+	GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error)
+	GetProjectMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error)
+	GetProjectMapByName(ctx context.Context) (map[string]model.Project, error)
+	GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error)
+	GetSubprojects(ctx context.Context, parentID uint) ([]model.Project, error)
+	GetProjectTree(ctx context.Context, rootID uint) (*model.ProjectTree, error)
+	GetRootProject(ctx context.Context, projectID uint) (*model.Project, error)
+	GetRolledUpMaintainers(ctx context.Context, projectID uint) ([]model.Maintainer, error)
+	GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error)
+	GetProjectIDMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error)
+	GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error)
+	GetMaintainerMapByID(ctx context.Context) (map[uint]model.Maintainer, error)
+	GetServiceTeamByProject(ctx context.Context, projectID uint, serviceID uint) (*model.ServiceTeam, error)
+	LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error
+	ListAuditLogs(ctx context.Context, filter AuditFilter) ([]model.AuditLog, error)
+	GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error)
+	GetMaintainerByGitHubAccount(ctx context.Context, account string) (*model.Maintainer, error)
+	ListServices(ctx context.Context) ([]model.Service, error)
+	CreateServiceTeam(ctx context.Context, projectID uint, projectName string, serviceID int, serviceName string) (*model.ServiceTeam, error)
+	AddMaintainerToProject(ctx context.Context, projectID uint, maintainer model.Maintainer, company string) (*model.Maintainer, error)
+	RemoveMaintainerFromProject(ctx context.Context, projectID, maintainerID uint) error
+	SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus) error
+	SetMaintainerCompany(ctx context.Context, maintainerID uint, company string) error
+	AuthenticateAPIToken(ctx context.Context, token string) (*model.FoundationOfficer, error)
+	RequestChange(ctx context.Context, req model.ChangeRequest) (*model.ChangeRequest, error)
+	ListChangeRequests(ctx context.Context, filter ChangeRequestFilter) ([]model.ChangeRequest, error)
+	ReviewChangeRequest(ctx context.Context, id uint, approve bool, note string) (*model.ChangeRequest, error)
+	CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id uint) error
+	WebhookCursor(ctx context.Context) (uint, error)
+	EnqueueWebhookDeliveries(ctx context.Context, cursor uint, deliveries []model.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]model.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, d model.WebhookDelivery) error
+	Ping(ctx context.Context) error
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -14,19 +15,33 @@ type SQLStore struct {
 	db *gorm.DB
 }
 
+var _ Store = (*SQLStore)(nil)
+
 func NewSQLStore(db *gorm.DB) *SQLStore {
 	return &SQLStore{db: db}
 }
 
-// getServiceByName returns a &Service the service identified by name
-func (s *SQLStore) getServiceByName(name string) (*model.Service, error) {
+// Ping checks that the database can still be reached.
+func (s *SQLStore) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+	return nil
+}
+
+// GetServiceByName returns a &Service the service identified by name
+func (s *SQLStore) GetServiceByName(ctx context.Context, name string) (*model.Service, error) {
 	var svc model.Service
-	err := s.db.Where("name = ?", name).First(&svc).Error
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&svc).Error
 	return &svc, err
 }
-func (s *SQLStore) GetProjectsUsingService(serviceID uint) ([]model.Project, error) {
+func (s *SQLStore) GetProjectsUsingService(ctx context.Context, serviceID uint) ([]model.Project, error) {
 	var projects []model.Project
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Joins("JOIN service_teams st ON st.project_id = projects.id").
 		Where("st.service_id = ?", serviceID).
 		Preload("Maintainers.Company").
@@ -34,9 +49,9 @@ func (s *SQLStore) GetProjectsUsingService(serviceID uint) ([]model.Project, err
 	return projects, err
 }
 
-func (s *SQLStore) GetMaintainersByProject(projectID uint) ([]model.Maintainer, error) {
+func (s *SQLStore) GetMaintainersByProject(ctx context.Context, projectID uint) ([]model.Maintainer, error) {
 	var maintainers []model.Maintainer
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Joins("JOIN maintainer_projects mp ON mp.maintainer_id = maintainers.id").
 		Where("mp.project_id = ?", projectID).
 		Preload("Company").
@@ -44,9 +59,9 @@ func (s *SQLStore) GetMaintainersByProject(projectID uint) ([]model.Maintainer,
 	return maintainers, err
 }
 
-func (s *SQLStore) GetServiceTeamByProject(projectID, serviceID uint) (*model.ServiceTeam, error) {
+func (s *SQLStore) GetServiceTeamByProject(ctx context.Context, projectID, serviceID uint) (*model.ServiceTeam, error) {
 	var st model.ServiceTeam
-	err := s.db.
+	err := s.db.WithContext(ctx).
 		Where("project_id = ? AND service_id = ?", projectID, serviceID).
 		First(&st).Error
 	if err == gorm.ErrRecordNotFound {
@@ -56,9 +71,9 @@ func (s *SQLStore) GetServiceTeamByProject(projectID, serviceID uint) (*model.Se
 }
 
 // GetMaintainerMapByEmail returns a map of Maintainers keyed by email address
-func (s *SQLStore) GetMaintainerMapByEmail() (map[string]model.Maintainer, error) {
+func (s *SQLStore) GetMaintainerMapByEmail(ctx context.Context) (map[string]model.Maintainer, error) {
 	var maintainers []model.Maintainer
-	err := s.db.Find(&maintainers).Error
+	err := s.db.WithContext(ctx).Find(&maintainers).Error
 	if err != nil {
 		return nil, err
 	}
@@ -69,10 +84,24 @@ func (s *SQLStore) GetMaintainerMapByEmail() (map[string]model.Maintainer, error
 	return m, nil
 }
 
+// GetMaintainerMapByID returns a map of Maintainers keyed by ID
+func (s *SQLStore) GetMaintainerMapByID(ctx context.Context) (map[uint]model.Maintainer, error) {
+	var maintainers []model.Maintainer
+	err := s.db.WithContext(ctx).Find(&maintainers).Error
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[uint]model.Maintainer, len(maintainers))
+	for _, maintainer := range maintainers {
+		m[maintainer.ID] = maintainer
+	}
+	return m, nil
+}
+
 // GetMaintainerMapByGitHubAccount returns a map of Maintainers keyed by GitHub Account
-func (s *SQLStore) GetMaintainerMapByGitHubAccount() (map[string]model.Maintainer, error) {
+func (s *SQLStore) GetMaintainerMapByGitHubAccount(ctx context.Context) (map[string]model.Maintainer, error) {
 	var maintainers []model.Maintainer
-	err := s.db.Find(&maintainers).Error
+	err := s.db.WithContext(ctx).Find(&maintainers).Error
 	if err != nil {
 		return nil, err
 	}
@@ -83,13 +112,42 @@ func (s *SQLStore) GetMaintainerMapByGitHubAccount() (map[string]model.Maintaine
 	return m, nil
 }
 
+// GetMaintainerByGitHubAccount returns the maintainer whose GitHub account is account, ignoring case, with their
+// company and projects.
+func (s *SQLStore) GetMaintainerByGitHubAccount(ctx context.Context, account string) (*model.Maintainer, error) {
+	var maintainer model.Maintainer
+	err := s.db.WithContext(ctx).
+		Preload("Company").
+		Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Where("LOWER(git_hub_account) = LOWER(?)", account).
+		First(&maintainer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("GetMaintainerByGitHubAccount: %w: %s", ErrMaintainerNotFound, account)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// ListServices returns every service ordered by name.
+func (s *SQLStore) ListServices(ctx context.Context) ([]model.Service, error) {
+	var services []model.Service
+	err := s.db.WithContext(ctx).Order("name").Find(&services).Error
+	return services, err
+}
+
 // GetProjectMaintainersMap returns a map keyed by the project id which holds a list of Maintainers
 // associated with that project.
-func (s *SQLStore) GetProjectMaintainersMap() (map[uint]model.ProjectInfo, error) {
+func (s *SQLStore) GetProjectMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
 	var projects []model.Project
 
 	// Preload the many-to-many relationship
-	err := s.db.Preload("Maintainers").Find(&projects).Error
+	err := s.db.WithContext(ctx).Preload("Maintainers.Company").Find(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.projectServices(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -100,18 +158,22 @@ func (s *SQLStore) GetProjectMaintainersMap() (map[uint]model.ProjectInfo, error
 		result[project.ID] = model.ProjectInfo{
 			Project:     project,
 			Maintainers: project.Maintainers,
-			Services:    project.Services,
+			Services:    services[project.ID],
 		}
 	}
 
 	return result, nil
 }
 
-func (s *SQLStore) getProjectIDMaintainersMap() (map[uint]model.ProjectInfo, error) {
+func (s *SQLStore) getProjectIDMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
 	var projects []model.Project
 
 	// Preload the many-to-many relationship
-	err := s.db.Preload("Maintainers").Find(&projects).Error
+	err := s.db.WithContext(ctx).Preload("Maintainers").Find(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.projectServices(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +184,7 @@ func (s *SQLStore) getProjectIDMaintainersMap() (map[uint]model.ProjectInfo, err
 		result[project.ID] = model.ProjectInfo{
 			Project:     project,
 			Maintainers: project.Maintainers,
-			Services:    project.Services,
+			Services:    services[project.ID],
 		}
 	}
 
@@ -131,11 +193,15 @@ func (s *SQLStore) getProjectIDMaintainersMap() (map[uint]model.ProjectInfo, err
 
 // getProjectMaintainersMap returns a map keyed by the project name which holds a list of Maintainers
 // associated with that project.
-func (s *SQLStore) getProjectMaintainersMap() (map[string]model.ProjectInfo, error) {
+func (s *SQLStore) getProjectMaintainersMap(ctx context.Context) (map[string]model.ProjectInfo, error) {
 	var projects []model.Project
 
 	// Preload the many-to-many relationship
-	err := s.db.Preload("Maintainers").Find(&projects).Error
+	err := s.db.WithContext(ctx).Preload("Maintainers").Find(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.projectServices(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -146,23 +212,54 @@ func (s *SQLStore) getProjectMaintainersMap() (map[string]model.ProjectInfo, err
 		result[project.Name] = model.ProjectInfo{
 			Project:     project,
 			Maintainers: project.Maintainers,
-			Services:    project.Services,
+			Services:    services[project.ID],
 		}
 	}
 
 	return result, nil
 }
 
+// projectServices returns the services each project has a team on, keyed by project id and in name order.
+func (s *SQLStore) projectServices(ctx context.Context) (map[uint][]model.Service, error) {
+	var services []model.Service
+	if err := s.db.WithContext(ctx).Order("name").Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("reading services: %w", err)
+	}
+	var teams []model.ServiceTeam
+	if err := s.db.WithContext(ctx).Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("reading service teams: %w", err)
+	}
+	return servicesByProject(services, teams), nil
+}
+
+// servicesByProject groups services, given in the order wanted, by the projects that have a team on them.
+func servicesByProject(services []model.Service, teams []model.ServiceTeam) map[uint][]model.Service {
+	onService := map[uint]map[uint]bool{} // service id -> project ids
+	for _, st := range teams {
+		if onService[st.ServiceID] == nil {
+			onService[st.ServiceID] = map[uint]bool{}
+		}
+		onService[st.ServiceID][st.ProjectID] = true
+	}
+	result := map[uint][]model.Service{}
+	for _, svc := range services {
+		for projectID := range onService[svc.ID] {
+			result[projectID] = append(result[projectID], svc)
+		}
+	}
+	return result
+}
+
 // GetProjectServiceTeamMap returns a map of projectID to ServiceTeams
 // for every Project that uses the service identified by serviceId
-func (s *SQLStore) GetProjectServiceTeamMap(serviceName string) (map[uint]*model.ServiceTeam, error) {
+func (s *SQLStore) GetProjectServiceTeamMap(ctx context.Context, serviceName string) (map[uint]*model.ServiceTeam, error) {
 	var serviceTeams []model.ServiceTeam
-	service, err := s.getServiceByName(serviceName)
+	service, err := s.GetServiceByName(ctx, serviceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service, %s, by name: %v", serviceName, err)
 	}
 	// Preload the many-to-many relationship
-	err = s.db.
+	err = s.db.WithContext(ctx).
 		Where("service_id = ? ", service.ID).
 		Find(&serviceTeams).Error
 	if err != nil {
@@ -179,9 +276,9 @@ func (s *SQLStore) GetProjectServiceTeamMap(serviceName string) (map[uint]*model
 	return result, nil
 
 }
-func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
+func (s *SQLStore) GetProjectMapByName(ctx context.Context) (map[string]model.Project, error) {
 	var projects []model.Project
-	if err := s.db.Find(&projects).Error; err != nil {
+	if err := s.db.WithContext(ctx).Find(&projects).Error; err != nil {
 		return nil, err
 	}
 
@@ -192,11 +289,15 @@ func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
 	return projectsByName, nil
 }
 
-func (s *SQLStore) GetProjectIDMaintainersMap() (map[uint]model.ProjectInfo, error) {
+func (s *SQLStore) GetProjectIDMaintainersMap(ctx context.Context) (map[uint]model.ProjectInfo, error) {
 	var projects []model.Project
 
 	// Preload the many-to-many relationship
-	err := s.db.Preload("Maintainers").Find(&projects).Error
+	err := s.db.WithContext(ctx).Preload("Maintainers").Find(&projects).Error
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.projectServices(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -207,19 +308,25 @@ func (s *SQLStore) GetProjectIDMaintainersMap() (map[uint]model.ProjectInfo, err
 		result[project.ID] = model.ProjectInfo{
 			Project:     project,
 			Maintainers: project.Maintainers,
-			Services:    project.Services,
+			Services:    services[project.ID],
 		}
 	}
 
 	return result, nil
 }
 
-func (s *SQLStore) LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) error {
+func (s *SQLStore) LogAuditEvent(ctx context.Context, logger *zap.SugaredLogger, event model.AuditLog) error {
 	if event.Message == "" {
 		event.Message = event.Action
 	}
+	if event.Actor == "" {
+		event.Actor = ActorFromContext(ctx)
+	}
+	if event.CorrelationID == "" {
+		event.CorrelationID = CorrelationIDFromContext(ctx)
+	}
 
-	err := s.db.WithContext(context.Background()).Create(&event).Error
+	err := s.db.WithContext(ctx).Create(&event).Error
 	if err != nil {
 		logger.Errorf("failed to write audit log: %v", err)
 		return err
@@ -230,6 +337,8 @@ func (s *SQLStore) LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog
 		"maintainer_id", event.MaintainerID,
 		"service_id", event.ServiceID,
 		"action", event.Action,
+		"actor", event.Actor,
+		"correlation_id", event.CorrelationID,
 		"message", event.Message,
 	)
 	return nil
@@ -237,7 +346,7 @@ func (s *SQLStore) LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog
 
 // CreateServiceTeam creates or retrieves a service team entry in the database based on the provided project and service details.
 // It accepts a project ID, project name, service ID, and service name as input and returns the service team or an error.
-func (s *SQLStore) CreateServiceTeam(
+func (s *SQLStore) CreateServiceTeam(ctx context.Context,
 	projectID uint, projectName string,
 	serviceID int, serviceName string) (*model.ServiceTeam, error) {
 
@@ -250,7 +359,7 @@ func (s *SQLStore) CreateServiceTeam(
 		ProjectID:       projectID,
 		ProjectName:     &projectName,
 	}
-	err := s.db.Where("service_team_id = ?", serviceID).FirstOrCreate(st).Error
+	err := s.db.WithContext(ctx).Where("service_team_id = ?", serviceID).FirstOrCreate(st).Error
 	if err != nil {
 		msg := fmt.Sprintf("CreateServiceTeamsForUser: failed for team %d (%s): %v", serviceID, serviceName, err)
 		log.Println(msg)
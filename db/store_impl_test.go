@@ -1,13 +1,14 @@
 package db
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func TestGetProjectsUsingService(t *testing.T) {
 	store := NewSQLStore(testDB)
-	projects, err := store.GetProjectsUsingService(1)
+	projects, err := store.GetProjectsUsingService(context.Background(), 1)
 	require.NoError(t, err)
 	require.NotEmpty(t, projects)
 }
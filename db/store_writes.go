@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotProjectMaintainer is returned when removing a maintainer from a project they do not maintain.
+var ErrNotProjectMaintainer = errors.New("not a maintainer of the project")
+
+// AddMaintainerToProject makes maintainer a maintainer of the project identified by projectID. An existing maintainer
+// with the same GitHub account, or else email, is used in place of creating one, keeping their status. The
+// maintainer's company is set to company when it is not empty.
+func (s *SQLStore) AddMaintainerToProject(ctx context.Context, projectID uint, maintainer model.Maintainer, company string) (*model.Maintainer, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.First(&project, projectID).Error; err != nil {
+			return fmt.Errorf("%w: id %d", ErrProjectNotFound, projectID)
+		}
+
+		var existing model.Maintainer
+		err := gorm.ErrRecordNotFound
+		if !MissingValue(maintainer.GitHubAccount) {
+			err = tx.Where("LOWER(git_hub_account) = LOWER(?)", maintainer.GitHubAccount).First(&existing).Error
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) && !MissingValue(maintainer.Email) {
+			err = tx.Where("email = ?", maintainer.Email).First(&existing).Error
+		}
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if maintainer.MaintainerStatus == "" {
+				maintainer.MaintainerStatus = model.ActiveMaintainer
+			}
+			if err := tx.Create(&maintainer).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			maintainer = existing
+		}
+
+		if company != "" {
+			if err := setMaintainerCompany(tx, &maintainer, company); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&maintainer).Association("Projects").Append(&project)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("AddMaintainerToProject: %w", err)
+	}
+	return &maintainer, nil
+}
+
+// RemoveMaintainerFromProject removes the maintainer identified by maintainerID from the project identified by
+// projectID. The maintainer themselves is kept.
+func (s *SQLStore) RemoveMaintainerFromProject(ctx context.Context, projectID, maintainerID uint) error {
+	result := s.db.WithContext(ctx).Delete(&model.MaintainerProject{MaintainerID: maintainerID, ProjectID: projectID})
+	if result.Error != nil {
+		return fmt.Errorf("RemoveMaintainerFromProject: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("RemoveMaintainerFromProject: %w", ErrNotProjectMaintainer)
+	}
+	return nil
+}
+
+// SetMaintainerStatus sets the status of the maintainer identified by maintainerID.
+func (s *SQLStore) SetMaintainerStatus(ctx context.Context, maintainerID uint, status model.MaintainerStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("SetMaintainerStatus: invalid status %q", status)
+	}
+	var maintainer model.Maintainer
+	if err := s.db.WithContext(ctx).First(&maintainer, maintainerID).Error; err != nil {
+		return fmt.Errorf("SetMaintainerStatus: %w: id %d", ErrMaintainerNotFound, maintainerID)
+	}
+	if maintainer.MaintainerStatus == status {
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Model(&maintainer).Update("maintainer_status", status).Error; err != nil {
+		return fmt.Errorf("SetMaintainerStatus: %w", err)
+	}
+	return nil
+}
+
+// SetMaintainerCompany sets the company of the maintainer identified by maintainerID, registering the company if it
+// is new.
+func (s *SQLStore) SetMaintainerCompany(ctx context.Context, maintainerID uint, company string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var maintainer model.Maintainer
+		if err := tx.First(&maintainer, maintainerID).Error; err != nil {
+			return fmt.Errorf("%w: id %d", ErrMaintainerNotFound, maintainerID)
+		}
+		return setMaintainerCompany(tx, &maintainer, company)
+	})
+	if err != nil {
+		return fmt.Errorf("SetMaintainerCompany: %w", err)
+	}
+	return nil
+}
+
+func setMaintainerCompany(tx *gorm.DB, maintainer *model.Maintainer, name string) error {
+	if name == "" {
+		return errors.New("a company name is required")
+	}
+	company := model.Company{Name: name}
+	if err := tx.FirstOrCreate(&company, model.Company{Name: name}).Error; err != nil {
+		return err
+	}
+	if maintainer.CompanyID != nil && *maintainer.CompanyID == company.ID {
+		return nil
+	}
+	return tx.Model(maintainer).Update("company_id", company.ID).Error
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newSeededSQLStore returns a SQLStore over a database of its own seeded with testdata/fixtures.yaml, for tests that
+// write.
+func newSeededSQLStore(t *testing.T, name string) (*SQLStore, *gorm.DB) {
+	t.Helper()
+	conn, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, Migrate(conn))
+	require.NoError(t, RegisterAuditCallbacks(conn))
+	f, err := LoadFixtures("testdata/fixtures.yaml")
+	require.NoError(t, err)
+	require.NoError(t, SeedFixtures(context.Background(), conn, f))
+	return NewSQLStore(conn), conn
+}
+
+func TestStoreMaintainerWrites(t *testing.T) {
+	ctx := WithActor(context.Background(), "officer")
+	sqlStore, _ := newSeededSQLStore(t, "writes")
+	stores := map[string]Store{
+		"sql":    sqlStore,
+		"memory": newTestMemoryStore(t),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			byName, err := store.GetProjectMapByName(ctx)
+			require.NoError(t, err)
+			jaeger := byName["Jaeger"]
+
+			// ada already maintains Jaeger, matching her GitHub account in any case adds no one
+			ada, err := store.AddMaintainerToProject(ctx, jaeger.ID, model.Maintainer{GitHubAccount: "ADA"}, "")
+			require.NoError(t, err)
+			require.Equal(t, "ada@example.org", ada.Email)
+
+			grace, err := store.AddMaintainerToProject(ctx, jaeger.ID,
+				model.Maintainer{Name: "Grace Hopper", Email: "grace@example.org", GitHubAccount: "grace"}, "Navy")
+			require.NoError(t, err)
+			require.Equal(t, model.ActiveMaintainer, grace.MaintainerStatus)
+			maintainers, err := store.GetMaintainersByProject(ctx, jaeger.ID)
+			require.NoError(t, err)
+			require.Len(t, maintainers, 2)
+
+			require.NoError(t, store.SetMaintainerStatus(ctx, grace.ID, model.EmeritusMaintainer))
+			require.Error(t, store.SetMaintainerStatus(ctx, grace.ID, "Bored"))
+			require.NoError(t, store.SetMaintainerCompany(ctx, grace.ID, "Example Inc"))
+			updated, err := store.GetMaintainerByGitHubAccount(ctx, "grace")
+			require.NoError(t, err)
+			require.Equal(t, model.EmeritusMaintainer, updated.MaintainerStatus)
+			require.Equal(t, "Example Inc", updated.Company.Name)
+
+			require.NoError(t, store.RemoveMaintainerFromProject(ctx, jaeger.ID, grace.ID))
+			require.ErrorIs(t, store.RemoveMaintainerFromProject(ctx, jaeger.ID, grace.ID), ErrNotProjectMaintainer)
+			_, err = store.AddMaintainerToProject(ctx, 999, model.Maintainer{GitHubAccount: "x"}, "")
+			require.ErrorIs(t, err, ErrProjectNotFound)
+
+			entries, err := store.ListAuditLogs(ctx, AuditFilter{MaintainerID: &grace.ID, Actor: "officer"})
+			require.NoError(t, err)
+			actions := map[string]bool{}
+			for _, e := range entries {
+				actions[e.Action] = true
+			}
+			for _, action := range []string{"CREATE_MAINTAINERS", "CREATE_MAINTAINER_PROJECTS", "UPDATE_MAINTAINERS", "DELETE_MAINTAINER_PROJECTS"} {
+				require.True(t, actions[action], action)
+			}
+		})
+	}
+}
+
+func TestAPITokens(t *testing.T) {
+	ctx := context.Background()
+	store, conn := newSeededSQLStore(t, "tokens")
+
+	_, _, err := IssueAPIToken(ctx, conn, "octocat", "laptop", 0)
+	require.Error(t, err, "only foundation officers get tokens")
+
+	officer := model.FoundationOfficer{Name: "Octo Cat", GitHubAccount: "octocat"}
+	require.NoError(t, AddFoundationOfficer(ctx, conn, &officer))
+	token, record, err := IssueAPIToken(ctx, conn, "OctoCat", "laptop", time.Hour)
+	require.NoError(t, err)
+	require.NotContains(t, record.TokenHash, token)
+
+	got, err := store.AuthenticateAPIToken(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, "octocat", got.GitHubAccount)
+	_, err = store.AuthenticateAPIToken(ctx, token+"x")
+	require.ErrorIs(t, err, ErrInvalidAPIToken)
+
+	require.NoError(t, RevokeAPIToken(ctx, conn, record.ID))
+	_, err = store.AuthenticateAPIToken(ctx, token)
+	require.ErrorIs(t, err, ErrInvalidAPIToken)
+
+	expired, _, err := IssueAPIToken(ctx, conn, "octocat", "old", time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = store.AuthenticateAPIToken(ctx, expired)
+	require.ErrorIs(t, err, ErrInvalidAPIToken)
+}
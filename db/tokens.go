@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APITokenPrefix starts every API token so that leaked tokens are easy to recognise.
+const APITokenPrefix = "mdt_"
+
+// ErrInvalidAPIToken is returned for an API token that is unknown, revoked or expired.
+var ErrInvalidAPIToken = errors.New("invalid API token")
+
+// NewAPIToken returns a random API token.
+func NewAPIToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewAPIToken: %w", err)
+	}
+	return APITokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashAPIToken returns the hash of token that is stored in place of the token.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AddFoundationOfficer registers officer, or updates the name and email of the officer with the same GitHub account.
+func AddFoundationOfficer(ctx context.Context, db *gorm.DB, officer *model.FoundationOfficer) error {
+	if MissingValue(officer.GitHubAccount) {
+		return errors.New("AddFoundationOfficer: a GitHub account is required")
+	}
+	var existing model.FoundationOfficer
+	err := db.WithContext(ctx).Where("LOWER(git_hub_account) = LOWER(?)", officer.GitHubAccount).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		now := time.Now()
+		officer.RegisteredAt = &now
+		if err := db.WithContext(ctx).Create(officer).Error; err != nil {
+			return fmt.Errorf("AddFoundationOfficer: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("AddFoundationOfficer: %w", err)
+	}
+	if err := db.WithContext(ctx).Model(&existing).Updates(model.FoundationOfficer{Name: officer.Name, Email: officer.Email}).Error; err != nil {
+		return fmt.Errorf("AddFoundationOfficer: %w", err)
+	}
+	*officer = existing
+	return nil
+}
+
+// IssueAPIToken creates an API token called name for the foundation officer with GitHub account officerGitHub. The
+// token expires after ttl, or never when ttl is zero. The token is returned once, only its hash is stored.
+func IssueAPIToken(ctx context.Context, db *gorm.DB, officerGitHub, name string, ttl time.Duration) (string, *model.APIToken, error) {
+	var officer model.FoundationOfficer
+	if err := db.WithContext(ctx).Where("LOWER(git_hub_account) = LOWER(?)", officerGitHub).First(&officer).Error; err != nil {
+		return "", nil, fmt.Errorf("IssueAPIToken: no foundation officer %s: %w", officerGitHub, err)
+	}
+	token, err := NewAPIToken()
+	if err != nil {
+		return "", nil, err
+	}
+	record := &model.APIToken{FoundationOfficerID: officer.ID, Name: name, TokenHash: HashAPIToken(token)}
+	if ttl > 0 {
+		expires := time.Now().Add(ttl)
+		record.ExpiresAt = &expires
+	}
+	if err := db.WithContext(ctx).Create(record).Error; err != nil {
+		return "", nil, fmt.Errorf("IssueAPIToken: %w", err)
+	}
+	record.FoundationOfficer = officer
+	return token, record, nil
+}
+
+// RevokeAPIToken revokes the API token identified by id.
+func RevokeAPIToken(ctx context.Context, db *gorm.DB, id uint) error {
+	var record model.APIToken
+	if err := db.WithContext(ctx).First(&record, id).Error; err != nil {
+		return fmt.Errorf("RevokeAPIToken: %d: %w", id, err)
+	}
+	if record.RevokedAt != nil {
+		return nil
+	}
+	if err := db.WithContext(ctx).Model(&record).Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("RevokeAPIToken: %w", err)
+	}
+	return nil
+}
+
+// ListAPITokens returns every API token with its officer, newest first.
+func ListAPITokens(ctx context.Context, db *gorm.DB) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := db.WithContext(ctx).Preload("FoundationOfficer").Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// apiTokenUsable reports whether record can authenticate a request made at now.
+func apiTokenUsable(record model.APIToken, now time.Time) bool {
+	return record.RevokedAt == nil && (record.ExpiresAt == nil || now.Before(*record.ExpiresAt))
+}
+
+// AuthenticateAPIToken returns the foundation officer that token was issued to.
+func (s *SQLStore) AuthenticateAPIToken(ctx context.Context, token string) (*model.FoundationOfficer, error) {
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+	var record model.APIToken
+	err := s.db.WithContext(ctx).Preload("FoundationOfficer").Where("token_hash = ?", HashAPIToken(token)).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("AuthenticateAPIToken: %w", err)
+	}
+	if !apiTokenUsable(record, time.Now()) {
+		return nil, ErrInvalidAPIToken
+	}
+	return &record.FoundationOfficer, nil
+}
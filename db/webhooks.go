@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maintainerd/model"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWebhookSubscriptionNotFound is returned for a webhook subscription id that does not exist.
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrInvalidWebhookSubscription is returned when creating a webhook subscription without a usable URL, secret or
+	// events.
+	ErrInvalidWebhookSubscription = errors.New("invalid webhook subscription")
+)
+
+// webhookSubscriptionDeleted is recorded against the deliveries still pending when their subscription is deleted.
+const webhookSubscriptionDeleted = "subscription deleted"
+
+// WebhookDeliveryFilter narrows the deliveries returned by ListWebhookDeliveries, zero valued fields are not used to
+// filter.
+type WebhookDeliveryFilter struct {
+	SubscriptionID *uint
+	Status         model.WebhookDeliveryStatus
+	DueBy          time.Time // only pending deliveries whose next attempt is due by then
+	Limit          int
+}
+
+// validateWebhookSubscription checks sub before it is created, normalising its list of events.
+func validateWebhookSubscription(sub *model.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%q is not an http or https URL", sub.URL)
+	}
+	if sub.Secret == "" {
+		return errors.New("a secret is required to sign deliveries")
+	}
+	var events []string
+	seen := map[model.WebhookEvent]bool{}
+	for _, e := range sub.EventList() {
+		if !e.IsValid() {
+			return fmt.Errorf("unknown event %q", e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, string(e))
+		}
+	}
+	if len(events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	sub.Events = strings.Join(events, ",")
+	return nil
+}
+
+// CreateWebhookSubscription registers sub, recording the actor found in ctx as its creator. It is sent the events
+// recorded from then on.
+func (s *SQLStore) CreateWebhookSubscription(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	if err := validateWebhookSubscription(&sub); err != nil {
+		return nil, fmt.Errorf("CreateWebhookSubscription: %w: %v", ErrInvalidWebhookSubscription, err)
+	}
+	sub.Model = gorm.Model{}
+	sub.CreatedBy = ActorFromContext(ctx)
+	if err := s.db.WithContext(ctx).Create(&sub).Error; err != nil {
+		return nil, fmt.Errorf("CreateWebhookSubscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// ListWebhookSubscriptions returns every webhook subscription, oldest first.
+func (s *SQLStore) ListWebhookSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	if err := s.db.WithContext(ctx).Order("id").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("ListWebhookSubscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// DeleteWebhookSubscription deletes the webhook subscription identified by id, its pending deliveries are failed.
+func (s *SQLStore) DeleteWebhookSubscription(ctx context.Context, id uint) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sub model.WebhookSubscription
+		if err := tx.First(&sub, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: id %d", ErrWebhookSubscriptionNotFound, id)
+			}
+			return err
+		}
+		if err := tx.Delete(&sub).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.WebhookDelivery{}).
+			Where("subscription_id = ? AND status = ?", id, model.DeliveryPending).
+			Updates(map[string]any{
+				"status":          model.DeliveryFailed,
+				"next_attempt_at": nil,
+				"last_error":      webhookSubscriptionDeleted,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("DeleteWebhookSubscription: %w", err)
+	}
+	return nil
+}
+
+// WebhookCursor returns the id of the last audit log entry read for webhook events. Until one has been read it is the
+// newest entry, so that a registry's history is not sent.
+func (s *SQLStore) WebhookCursor(ctx context.Context) (uint, error) {
+	var cursor model.WebhookCursor
+	err := s.db.WithContext(ctx).First(&cursor).Error
+	if err == nil {
+		return cursor.AuditLogID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("WebhookCursor: %w", err)
+	}
+	var latest []model.AuditLog
+	if err := s.db.WithContext(ctx).Order("id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return 0, fmt.Errorf("WebhookCursor: %w", err)
+	}
+	if len(latest) == 0 {
+		return 0, nil
+	}
+	return latest[0].ID, nil
+}
+
+// EnqueueWebhookDeliveries queues deliveries and moves the webhook cursor to the audit log entry identified by cursor
+// in one transaction, so that every event is queued once.
+func (s *SQLStore) EnqueueWebhookDeliveries(ctx context.Context, cursor uint, deliveries []model.WebhookDelivery) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, d := range deliveries {
+			d = newWebhookDelivery(d, time.Now())
+			if err := tx.Create(&d).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(&model.WebhookCursor{ID: 1, AuditLogID: cursor}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("EnqueueWebhookDeliveries: %w", err)
+	}
+	return nil
+}
+
+// newWebhookDelivery returns d ready to be attempted at now.
+func newWebhookDelivery(d model.WebhookDelivery, now time.Time) model.WebhookDelivery {
+	d.Model = gorm.Model{}
+	d.Status = model.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+	return d
+}
+
+// ListWebhookDeliveries returns the webhook deliveries that match filter newest first, or oldest first when DueBy is
+// set so that the deliveries waiting longest are sent first.
+func (s *SQLStore) ListWebhookDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]model.WebhookDelivery, error) {
+	q := s.db.WithContext(ctx).Order("id DESC")
+	if !filter.DueBy.IsZero() {
+		q = s.db.WithContext(ctx).Order("id")
+	}
+	if filter.SubscriptionID != nil {
+		q = q.Where("subscription_id = ?", *filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if !filter.DueBy.IsZero() {
+		q = q.Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, filter.DueBy)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+	var deliveries []model.WebhookDelivery
+	if err := q.Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("ListWebhookDeliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery records the outcome of an attempt to send d.
+func (s *SQLStore) UpdateWebhookDelivery(ctx context.Context, d model.WebhookDelivery) error {
+	err := s.db.WithContext(ctx).Model(&model.WebhookDelivery{Model: gorm.Model{ID: d.ID}}).
+		Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "delivered_at").
+		Updates(&d).Error
+	if err != nil {
+		return fmt.Errorf("UpdateWebhookDelivery: %w", err)
+	}
+	return nil
+}
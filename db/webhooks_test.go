@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	sqlStore, _ := newSeededSQLStore(t, "webhooks")
+	stores := map[string]Store{
+		"sql":    sqlStore,
+		"memory": newTestMemoryStore(t),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := WithActor(context.Background(), "octocat")
+
+			for _, invalid := range []model.WebhookSubscription{
+				{URL: "ftp://example.org", Secret: "s", Events: "maintainer.added"},
+				{URL: "https://example.org", Events: "maintainer.added"},
+				{URL: "https://example.org", Secret: "s", Events: "maintainer.renamed"},
+				{URL: "https://example.org", Secret: "s", Events: " , "},
+			} {
+				_, err := store.CreateWebhookSubscription(ctx, invalid)
+				require.ErrorIs(t, err, ErrInvalidWebhookSubscription, "%+v", invalid)
+			}
+
+			sub, err := store.CreateWebhookSubscription(ctx, model.WebhookSubscription{
+				URL:    "https://example.org/hook",
+				Secret: "s3cret",
+				Events: "maintainer.added, maintainer.removed,maintainer.added",
+			})
+			require.NoError(t, err)
+			require.Equal(t, "maintainer.added,maintainer.removed", sub.Events)
+			require.Equal(t, "octocat", sub.CreatedBy)
+			require.True(t, sub.Subscribes(model.EventMaintainerRemoved))
+			require.False(t, sub.Subscribes(model.EventServiceAccessGranted))
+
+			entries, err := store.ListAuditLogs(ctx, AuditFilter{Action: "CREATE_WEBHOOK_SUBSCRIPTIONS"})
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			require.NotContains(t, entries[0].Metadata, "s3cret")
+
+			// the cursor starts at the newest entry and only moves past the entries read
+			cursor, err := store.WebhookCursor(ctx)
+			require.NoError(t, err)
+			require.Equal(t, entries[0].ID, cursor)
+			newer, err := store.ListAuditLogs(ctx, AuditFilter{AfterID: cursor})
+			require.NoError(t, err)
+			require.Empty(t, newer)
+
+			require.NoError(t, store.EnqueueWebhookDeliveries(ctx, cursor+5, []model.WebhookDelivery{
+				{SubscriptionID: sub.ID, AuditLogID: cursor, Event: "maintainer.added", Payload: `{"id":1}`},
+				{SubscriptionID: sub.ID, AuditLogID: cursor + 1, Event: "maintainer.removed", Payload: `{"id":2}`},
+			}))
+			cursor, err = store.WebhookCursor(ctx)
+			require.NoError(t, err)
+			require.Equal(t, entries[0].ID+5, cursor)
+
+			due, err := store.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{DueBy: time.Now()})
+			require.NoError(t, err)
+			require.Len(t, due, 2)
+			require.Equal(t, "maintainer.added", due[0].Event, "oldest first")
+			require.Equal(t, model.DeliveryPending, due[0].Status)
+
+			delivered := due[0]
+			now := time.Now()
+			delivered.Status, delivered.Attempts, delivered.ResponseStatus = model.DeliveryDelivered, 1, 204
+			delivered.NextAttemptAt, delivered.DeliveredAt = nil, &now
+			require.NoError(t, store.UpdateWebhookDelivery(ctx, delivered))
+			retrying := due[1]
+			later := now.Add(time.Hour)
+			retrying.Attempts, retrying.ResponseStatus, retrying.LastError = 1, 500, "unexpected status"
+			retrying.NextAttemptAt = &later
+			require.NoError(t, store.UpdateWebhookDelivery(ctx, retrying))
+
+			due, err = store.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{DueBy: time.Now()})
+			require.NoError(t, err)
+			require.Empty(t, due)
+			all, err := store.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{SubscriptionID: &sub.ID})
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+			require.Equal(t, "maintainer.removed", all[0].Event, "newest first")
+			require.Equal(t, 500, all[0].ResponseStatus)
+			require.Equal(t, model.DeliveryDelivered, all[1].Status)
+
+			require.NoError(t, store.DeleteWebhookSubscription(ctx, sub.ID))
+			require.ErrorIs(t, store.DeleteWebhookSubscription(ctx, sub.ID), ErrWebhookSubscriptionNotFound)
+			subs, err := store.ListWebhookSubscriptions(ctx)
+			require.NoError(t, err)
+			require.Empty(t, subs)
+			failed, err := store.ListWebhookDeliveries(ctx, WebhookDeliveryFilter{Status: model.DeliveryFailed})
+			require.NoError(t, err)
+			require.Len(t, failed, 1)
+			require.Equal(t, "subscription deleted", failed[0].LastError)
+		})
+	}
+}
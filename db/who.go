@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"maintainerd/model"
+	"slices"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Person is someone Who found: a maintainer or a collaborator, with the accounts they hold on services and the
+// service teams those accounts are members of.
+type Person struct {
+	Kind          string              `json:"kind"` // maintainer or collaborator
+	ID            uint                `json:"id"`
+	MatchedBy     string              `json:"matched_by"` // email, github or name
+	Name          string              `json:"name"`
+	Email         string              `json:"email,omitempty"`
+	GitHubAccount string              `json:"github,omitempty"`
+	GitHubEmail   string              `json:"github_email,omitempty"`
+	Company       string              `json:"company,omitempty"`
+	Status        string              `json:"status,omitempty"`
+	Projects      []string            `json:"projects"`
+	ServiceUsers  []PersonServiceUser `json:"service_users"`
+	Teams         []PersonServiceTeam `json:"teams"`
+}
+
+// PersonServiceUser is a person's account on a service, a model.ServiceUser.
+type PersonServiceUser struct {
+	Service       string `json:"service"`
+	ServiceUserID int    `json:"service_user_id"`
+	Email         string `json:"email,omitempty"`
+	GitHubName    string `json:"github,omitempty"`
+	Ref           string `json:"ref,omitempty"`
+}
+
+// PersonServiceTeam is a service team a person is linked to by a model.ServiceUserTeams.
+type PersonServiceTeam struct {
+	Service       string `json:"service"`
+	Project       string `json:"project"`
+	TeamID        int    `json:"team_id"`
+	TeamName      string `json:"team_name,omitempty"`
+	ServiceUserID int    `json:"service_user_id"`
+}
+
+// Who finds the maintainers and collaborators in db known by query: an email address, which may be their GitHub
+// email, a GitHub account, with or without its @, or part of their name. Case is ignored. Maintainers come first,
+// each list in name order.
+func Who(ctx context.Context, db *gorm.DB, query string) ([]Person, error) {
+	q := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	if q == "" {
+		return nil, fmt.Errorf("Who: nothing to look up")
+	}
+	db = db.WithContext(ctx)
+	store := NewSQLStore(db)
+
+	// maintainers by ID, with how they matched
+	matched := map[uint]string{}
+	byEmail, err := store.GetMaintainerMapByEmail(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Who: %w", err)
+	}
+	for email, m := range byEmail {
+		if !MissingValue(email) && strings.ToLower(email) == q {
+			matched[m.ID] = "email"
+		}
+	}
+	byGitHub, err := store.GetMaintainerMapByGitHubAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Who: %w", err)
+	}
+	for account, m := range byGitHub {
+		if _, ok := matched[m.ID]; !ok && !MissingValue(account) && strings.ToLower(account) == q {
+			matched[m.ID] = "github"
+		}
+	}
+	// the maps keep one maintainer per key, so GitHub emails and names are looked for in the table, where maintainers
+	// who share a missing email address or GitHub account are not collapsed into one
+	var emailOrName []model.Maintainer
+	err = db.Where("LOWER(git_hub_email) = ? OR LOWER(name) LIKE ?", q, "%"+q+"%").Find(&emailOrName).Error
+	if err != nil {
+		return nil, fmt.Errorf("Who: failed to read maintainers: %w", err)
+	}
+	for _, m := range emailOrName {
+		if _, ok := matched[m.ID]; ok {
+			continue
+		}
+		if strings.ToLower(m.GitHubEmail) == q && !MissingValue(m.GitHubEmail) {
+			matched[m.ID] = "email"
+		} else if strings.Contains(strings.ToLower(m.Name), q) {
+			matched[m.ID] = "name"
+		}
+	}
+
+	var maintainers []model.Maintainer
+	if len(matched) > 0 {
+		ids := make([]uint, 0, len(matched))
+		for id := range matched {
+			ids = append(ids, id)
+		}
+		err := db.Preload("Company").
+			Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+			Where("id IN ?", ids).
+			Order("name").
+			Find(&maintainers).Error
+		if err != nil {
+			return nil, fmt.Errorf("Who: failed to read maintainers: %w", err)
+		}
+	}
+	var collaborators []model.Collaborator
+	err = db.Where("LOWER(email) = ? OR LOWER(git_hub_email) = ? OR LOWER(git_hub_account) = ? OR LOWER(name) LIKE ?",
+		q, q, q, "%"+q+"%").
+		Order("name").
+		Find(&collaborators).Error
+	if err != nil {
+		return nil, fmt.Errorf("Who: failed to read collaborators: %w", err)
+	}
+
+	access, err := loadServiceAccess(db)
+	if err != nil {
+		return nil, err
+	}
+	people := make([]Person, 0, len(maintainers)+len(collaborators))
+	for _, m := range maintainers {
+		p := Person{
+			Kind:          "maintainer",
+			ID:            m.ID,
+			MatchedBy:     matched[m.ID],
+			Name:          m.Name,
+			Email:         presentValue(m.Email),
+			GitHubAccount: presentValue(m.GitHubAccount),
+			GitHubEmail:   presentValue(m.GitHubEmail),
+			Company:       m.Company.Name,
+			Status:        string(m.MaintainerStatus),
+			Projects:      []string{},
+		}
+		for _, project := range m.Projects {
+			p.Projects = append(p.Projects, project.Name)
+		}
+		access.fill(&p, func(link model.ServiceUserTeams) bool { return link.MaintainerID != nil && *link.MaintainerID == m.ID })
+		people = append(people, p)
+	}
+	for _, c := range collaborators {
+		p := Person{
+			Kind:      "collaborator",
+			ID:        c.ID,
+			MatchedBy: "name",
+			Name:      c.Name,
+			Email:     presentValue(c.Email),
+			Projects:  []string{},
+		}
+		if c.GitHubAccount != nil {
+			p.GitHubAccount = presentValue(*c.GitHubAccount)
+		}
+		if c.GitHubEmail != nil {
+			p.GitHubEmail = presentValue(*c.GitHubEmail)
+		}
+		switch q {
+		case strings.ToLower(p.Email), strings.ToLower(p.GitHubEmail):
+			p.MatchedBy = "email"
+		case strings.ToLower(p.GitHubAccount):
+			p.MatchedBy = "github"
+		}
+		access.fill(&p, func(link model.ServiceUserTeams) bool {
+			return link.CollaboratorID != nil && *link.CollaboratorID == c.ID
+		})
+		// collaborators are not on project rosters, they belong to the projects of their teams
+		for _, team := range p.Teams {
+			if !slices.Contains(p.Projects, team.Project) {
+				p.Projects = append(p.Projects, team.Project)
+			}
+		}
+		sort.Strings(p.Projects)
+		people = append(people, p)
+	}
+	return people, nil
+}
+
+// serviceAccess holds the service accounts and team links Who reports.
+type serviceAccess struct {
+	services     map[uint]string
+	projects     map[uint]string
+	teams        map[uint]model.ServiceTeam
+	serviceUsers []model.ServiceUser
+	links        []model.ServiceUserTeams
+}
+
+func loadServiceAccess(db *gorm.DB) (*serviceAccess, error) {
+	a := &serviceAccess{services: map[uint]string{}, projects: map[uint]string{}, teams: map[uint]model.ServiceTeam{}}
+	var services []model.Service
+	if err := db.Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("Who: failed to read services: %w", err)
+	}
+	for _, s := range services {
+		a.services[s.ID] = s.Name
+	}
+	var projects []model.Project
+	if err := db.Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("Who: failed to read projects: %w", err)
+	}
+	for _, p := range projects {
+		a.projects[p.ID] = p.Name
+	}
+	var teams []model.ServiceTeam
+	if err := db.Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("Who: failed to read service teams: %w", err)
+	}
+	for _, st := range teams {
+		a.teams[st.ID] = st
+	}
+	if err := db.Order("service_id, service_user_id").Find(&a.serviceUsers).Error; err != nil {
+		return nil, fmt.Errorf("Who: failed to read service users: %w", err)
+	}
+	if err := db.Order("service_id, service_team_id").Find(&a.links).Error; err != nil {
+		return nil, fmt.Errorf("Who: failed to read service user teams: %w", err)
+	}
+	return a, nil
+}
+
+// fill adds to p the team links that are p's, the service users of those links and those with p's email address or
+// GitHub account.
+func (a *serviceAccess) fill(p *Person, linked func(model.ServiceUserTeams) bool) {
+	type account struct {
+		service uint
+		id      int
+	}
+	accounts := map[account]bool{}
+	p.Teams = []PersonServiceTeam{}
+	for _, link := range a.links {
+		if !linked(link) {
+			continue
+		}
+		accounts[account{link.ServiceID, link.ServiceUserID}] = true
+		st := a.teams[link.ServiceTeamID]
+		team := PersonServiceTeam{
+			Service:       a.services[link.ServiceID],
+			Project:       a.projects[st.ProjectID],
+			TeamID:        st.ServiceTeamID,
+			ServiceUserID: link.ServiceUserID,
+		}
+		if st.ServiceTeamName != nil {
+			team.TeamName = *st.ServiceTeamName
+		}
+		p.Teams = append(p.Teams, team)
+	}
+	p.ServiceUsers = []PersonServiceUser{}
+	for _, su := range a.serviceUsers {
+		github := ""
+		if su.ServiceGitHubName != nil {
+			github = presentValue(*su.ServiceGitHubName)
+		}
+		mine := accounts[account{su.ServiceID, su.ServiceUserID}] ||
+			(p.Email != "" && strings.EqualFold(su.ServiceEmail, p.Email)) ||
+			(p.GitHubEmail != "" && strings.EqualFold(su.ServiceEmail, p.GitHubEmail)) ||
+			(p.GitHubAccount != "" && strings.EqualFold(github, p.GitHubAccount))
+		if !mine {
+			continue
+		}
+		p.ServiceUsers = append(p.ServiceUsers, PersonServiceUser{
+			Service:       a.services[su.ServiceID],
+			ServiceUserID: su.ServiceUserID,
+			Email:         presentValue(su.ServiceEmail),
+			GitHubName:    github,
+			Ref:           su.ServiceRef,
+		})
+	}
+}
+
+// presentValue returns v, or "" when it is one of the defaults MissingValue reports.
+func presentValue(v string) string {
+	if MissingValue(v) {
+		return ""
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWho(t *testing.T) {
+	ctx := context.Background()
+	store, conn := newSeededSQLStore(t, "who")
+	fossa, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+	teams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
+	require.NoError(t, err)
+	jane, err := store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+	require.NoError(t, err)
+	team := teams[jane.Projects[0].ID]
+
+	gh := "grace"
+	grace := model.Collaborator{Name: "Grace Hopper", Email: "grace@example.org", GitHubAccount: &gh}
+	require.NoError(t, conn.Create(&grace).Error)
+	janeGH := "janedoe"
+	require.NoError(t, conn.Create(&[]model.ServiceUser{
+		{ServiceID: fossa.ID, ServiceUserID: 7, ServiceEmail: "jane@example.org", ServiceRef: "fossa/7"},
+		{ServiceID: fossa.ID, ServiceUserID: 8, ServiceEmail: "jd@other.example", ServiceGitHubName: &janeGH},
+		{ServiceID: fossa.ID, ServiceUserID: 9, ServiceEmail: "grace@example.org"},
+	}).Error)
+	require.NoError(t, conn.Create(&[]model.ServiceUserTeams{
+		{ServiceID: fossa.ID, ServiceUserID: 7, ServiceTeamID: team.ID, MaintainerID: &jane.ID},
+		{ServiceID: fossa.ID, ServiceUserID: 9, ServiceTeamID: team.ID, CollaboratorID: &grace.ID},
+	}).Error)
+
+	for query, matchedBy := range map[string]string{"@JaneDoe": "github", "JANE@example.org": "email", "jane d": "name"} {
+		people, err := Who(ctx, conn, query)
+		require.NoError(t, err)
+		require.Len(t, people, 1, query)
+		require.Equal(t, matchedBy, people[0].MatchedBy, query)
+	}
+
+	people, err := Who(ctx, conn, "janedoe")
+	require.NoError(t, err)
+	p := people[0]
+	require.Equal(t, "maintainer", p.Kind)
+	require.Equal(t, "Jane Doe", p.Name)
+	require.Equal(t, "Example Inc", p.Company)
+	require.Equal(t, string(model.ActiveMaintainer), p.Status)
+	require.Equal(t, []string{"Kubernetes"}, p.Projects)
+	require.Len(t, p.ServiceUsers, 2, "the linked account and the one with their GitHub account")
+	require.Equal(t, "fossa/7", p.ServiceUsers[0].Ref)
+	require.Equal(t, "janedoe", p.ServiceUsers[1].GitHubName)
+	require.Equal(t, []PersonServiceTeam{{Service: "FOSSA", Project: "Kubernetes", TeamID: 42, TeamName: "Kubernetes", ServiceUserID: 7}}, p.Teams)
+
+	people, err = Who(ctx, conn, "grace")
+	require.NoError(t, err)
+	require.Len(t, people, 1)
+	p = people[0]
+	require.Equal(t, "collaborator", p.Kind)
+	require.Equal(t, "github", p.MatchedBy)
+	require.Equal(t, []string{"Kubernetes"}, p.Projects, "collaborators belong to the projects of their teams")
+	require.Len(t, p.ServiceUsers, 1)
+	require.Equal(t, 9, p.ServiceUsers[0].ServiceUserID)
+
+	// a name can match several people, maintainers first
+	people, err = Who(ctx, conn, "o")
+	require.NoError(t, err)
+	var names []string
+	for _, p := range people {
+		names = append(names, p.Name)
+	}
+	require.Equal(t, []string{"Ada Lovelace", "Jane Doe", "John Roe", "Grace Hopper"}, names)
+
+	// maintainers without an email address or GitHub account all have the same placeholders
+	ghEmail := "anon@users.noreply.example"
+	require.NoError(t, conn.Create(&[]model.Maintainer{
+		{Name: "Anon One", MaintainerStatus: model.ActiveMaintainer},
+		{Name: "Anon Two", MaintainerStatus: model.ActiveMaintainer},
+		{Name: "Anon Three", GitHubEmail: ghEmail, MaintainerStatus: model.ActiveMaintainer},
+	}).Error)
+	people, err = Who(ctx, conn, "anon")
+	require.NoError(t, err)
+	names = nil
+	for _, p := range people {
+		names = append(names, p.Name)
+		require.Empty(t, p.Email)
+		require.Empty(t, p.GitHubAccount)
+	}
+	require.Equal(t, []string{"Anon One", "Anon Three", "Anon Two"}, names)
+	people, err = Who(ctx, conn, ghEmail)
+	require.NoError(t, err)
+	require.Len(t, people, 1)
+	require.Equal(t, "Anon Three", people[0].Name)
+	require.Equal(t, "email", people[0].MatchedBy)
+
+	people, err = Who(ctx, conn, "nobody@example.org")
+	require.NoError(t, err)
+	require.Empty(t, people)
+	_, err = Who(ctx, conn, " @ ")
+	require.Error(t, err)
+}
@@ -0,0 +1,257 @@
+// Package directory serves a public directory of the active maintainers of every project under /directory, as HTML
+// pages and as JSON that foundation websites can embed instead of keeping their own rosters. Only what maintainers
+// already publish is served: names, GitHub handles and companies. Email addresses are never part of the directory.
+package directory
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// Prefix is the path under which the directory is served.
+const Prefix = "/directory"
+
+// DefaultMaxAge is how long browsers and CDNs may cache directory responses for.
+const DefaultMaxAge = 5 * time.Minute
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Source holds the projects listed in the directory with their maintainers, a *db.ProjectCache.
+type Source interface {
+	Projects(ctx context.Context) (map[string]model.ProjectInfo, error)
+	LoadedAt() time.Time // when the projects were read from the database
+}
+
+var _ Source = (*db.ProjectCache)(nil)
+
+// Directory is every project in the directory.
+type Directory struct {
+	AsOf     time.Time `json:"as_of"`
+	Projects []Project `json:"projects"`
+}
+
+// Project is a project with its active maintainers. AsOf is when the roster was read from the registry.
+type Project struct {
+	Name            string       `json:"name"`
+	Maturity        string       `json:"maturity"`
+	ParentProject   string       `json:"parent_project,omitempty"`
+	MaintainerCount int          `json:"maintainer_count"`
+	Maintainers     []Maintainer `json:"maintainers"`
+	AsOf            time.Time    `json:"as_of"`
+}
+
+// Maintainer is an active maintainer as listed in the directory. It deliberately has no email fields.
+type Maintainer struct {
+	Name    string `json:"name"`
+	GitHub  string `json:"github,omitempty"`
+	Company string `json:"company,omitempty"`
+}
+
+// Server serves the directory from Source.
+type Server struct {
+	Source Source
+	MaxAge time.Duration // sent in Cache-Control, DefaultMaxAge when zero
+	pages  map[string]*template.Template
+}
+
+// NewServer returns a Server listing the projects held by source.
+func NewServer(source Source) *Server {
+	return &Server{Source: source, MaxAge: DefaultMaxAge, pages: parsePages()}
+}
+
+func parsePages() map[string]*template.Template {
+	funcs := template.FuncMap{
+		"time":   func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+		"prefix": func() string { return Prefix },
+	}
+	layout := template.Must(template.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html"))
+	pages := map[string]*template.Template{}
+	for _, name := range []string{"index", "project", "error"} {
+		page := template.Must(layout.Clone())
+		pages[name] = template.Must(page.ParseFS(templateFS, "templates/"+name+".html"))
+	}
+	return pages
+}
+
+// Register mounts the directory on mux.
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+Prefix+"/{$}", s.handleIndex)
+	mux.HandleFunc("GET "+Prefix+"/projects.json", s.handleDirectoryJSON)
+	mux.HandleFunc("GET "+Prefix+"/projects/{project}", s.handleProject)
+}
+
+// Handler returns an http.Handler serving only the directory.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.Register(mux)
+	return mux
+}
+
+// directory builds the directory from Source, keeping the projects whose maturity is one of maturities, or all of them
+// when none are given.
+func (s *Server) directory(ctx context.Context, maturities ...string) (Directory, error) {
+	infos, err := s.Source.Projects(ctx)
+	if err != nil {
+		return Directory{}, err
+	}
+	asOf := s.Source.LoadedAt().UTC().Truncate(time.Second)
+	names := make(map[uint]string, len(infos))
+	for _, info := range infos {
+		names[info.Project.ID] = info.Project.Name
+	}
+	dir := Directory{AsOf: asOf, Projects: []Project{}}
+	for _, info := range infos {
+		if len(maturities) > 0 && !info.Project.Maturity.In(maturities) {
+			continue
+		}
+		dir.Projects = append(dir.Projects, newProject(info, names, asOf))
+	}
+	sort.Slice(dir.Projects, func(i, j int) bool {
+		return strings.ToLower(dir.Projects[i].Name) < strings.ToLower(dir.Projects[j].Name)
+	})
+	return dir, nil
+}
+
+// newProject lists info's active maintainers, copying only the fields the directory publishes.
+func newProject(info model.ProjectInfo, names map[uint]string, asOf time.Time) Project {
+	p := Project{Name: info.Project.Name, Maturity: string(info.Project.Maturity), Maintainers: []Maintainer{}, AsOf: asOf}
+	if info.Project.ParentProjectID != nil {
+		p.ParentProject = names[*info.Project.ParentProjectID]
+	}
+	for _, m := range info.Maintainers {
+		if !m.MaintainerStatus.IsActive() {
+			continue
+		}
+		listed := Maintainer{Name: m.Name, Company: m.Company.Name}
+		if !db.MissingValue(m.GitHubAccount) {
+			listed.GitHub = m.GitHubAccount
+		}
+		p.Maintainers = append(p.Maintainers, listed)
+	}
+	sort.Slice(p.Maintainers, func(i, j int) bool {
+		return strings.ToLower(p.Maintainers[i].Name) < strings.ToLower(p.Maintainers[j].Name)
+	})
+	p.MaintainerCount = len(p.Maintainers)
+	return p
+}
+
+// handleIndex lists every project with its maturity and maintainer count.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	dir, err := s.directory(r.Context())
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	s.render(w, r, http.StatusOK, "index", dir)
+}
+
+// handleDirectoryJSON serves every project as JSON, the maturity query parameter keeps projects of the given
+// maturities and may be repeated or comma separated.
+func (s *Server) handleDirectoryJSON(w http.ResponseWriter, r *http.Request) {
+	var maturities []string
+	for _, v := range r.URL.Query()["maturity"] {
+		for _, m := range strings.Split(v, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				maturities = append(maturities, m)
+			}
+		}
+	}
+	dir, err := s.directory(r.Context(), maturities...)
+	if err != nil {
+		s.writeJSONError(w, r, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, dir)
+}
+
+// handleProject serves a project's page, or its JSON when the name is followed by .json.
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	name, asJSON := strings.CutSuffix(r.PathValue("project"), ".json")
+	dir, err := s.directory(r.Context())
+	if err != nil {
+		if asJSON {
+			s.writeJSONError(w, r, err)
+		} else {
+			s.renderError(w, r, err)
+		}
+		return
+	}
+	for _, p := range dir.Projects {
+		if !strings.EqualFold(p.Name, name) {
+			continue
+		}
+		if asJSON {
+			s.writeJSON(w, http.StatusOK, p)
+		} else {
+			s.render(w, r, http.StatusOK, "project", p)
+		}
+		return
+	}
+	message := strconv.Quote(name) + " is not a CNCF project in the directory"
+	if asJSON {
+		s.writeJSON(w, http.StatusNotFound, map[string]string{"error": message})
+		return
+	}
+	s.render(w, r, http.StatusNotFound, "error", message)
+}
+
+// setPublic lets other sites read a response from JavaScript and, when it succeeded, browsers and CDNs cache it.
+func (s *Server) setPublic(w http.ResponseWriter, status int) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if status != http.StatusOK {
+		return
+	}
+	maxAge := s.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	s.setPublic(w, status)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("directory: WRN, failed to write response: %v", err)
+	}
+}
+
+func (s *Server) writeJSONError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("directory: ERR, %s %s: %v", r.Method, r.URL.Path, err)
+	s.writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+}
+
+// render writes the named page with data.
+func (s *Server) render(w http.ResponseWriter, r *http.Request, status int, name string, data any) {
+	var buf bytes.Buffer
+	if err := s.pages[name].ExecuteTemplate(&buf, "layout.html", data); err != nil {
+		log.Printf("directory: ERR, rendering %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	s.setPublic(w, status)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("directory: WRN, failed to write response: %v", err)
+	}
+}
+
+func (s *Server) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("directory: ERR, %s %s: %v", r.Method, r.URL.Path, err)
+	s.render(w, r, http.StatusInternalServerError, "error", "The directory is unavailable, please try again later.")
+}
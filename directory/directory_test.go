@@ -0,0 +1,99 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestDirectory(t *testing.T) {
+	ctx := context.Background()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	_, err = store.AddMaintainerToProject(ctx, projects["Kubernetes"].ID, model.Maintainer{
+		Name: "Ray Emeritus", Email: "ray@example.org", GitHubAccount: "ray", GitHubEmail: "ray@users.noreply.github.com",
+	}, "")
+	require.NoError(t, err)
+	ray, err := store.GetMaintainerByGitHubAccount(ctx, "ray")
+	require.NoError(t, err)
+	require.NoError(t, store.SetMaintainerStatus(ctx, ray.ID, model.EmeritusMaintainer))
+
+	cache := db.NewProjectCache(store, 0)
+	h := NewServer(cache).Handler()
+
+	rec := get(t, h, Prefix+"/projects.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	require.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+	require.NotContains(t, rec.Body.String(), "@", "no email address is ever served")
+	var dir Directory
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dir))
+	require.Equal(t, cache.LoadedAt().UTC().Unix(), dir.AsOf.Unix())
+	require.Len(t, dir.Projects, 3)
+	require.Equal(t, []string{"Jaeger", "kubectl", "Kubernetes"}, []string{dir.Projects[0].Name, dir.Projects[1].Name, dir.Projects[2].Name})
+	k8s := dir.Projects[2]
+	require.Equal(t, "Graduated", k8s.Maturity)
+	require.Equal(t, 1, k8s.MaintainerCount, "emeritus maintainers are not listed")
+	require.Equal(t, []Maintainer{{Name: "Jane Doe", GitHub: "janedoe", Company: "Example Inc"}}, k8s.Maintainers)
+	require.Equal(t, "Kubernetes", dir.Projects[1].ParentProject)
+
+	rec = get(t, h, Prefix+"/projects.json?maturity=incubating")
+	require.Contains(t, rec.Body.String(), `"projects":[]`)
+	rec = get(t, h, Prefix+"/projects.json?maturity=Sandbox,graduated")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dir))
+	require.Len(t, dir.Projects, 3)
+
+	rec = get(t, h, Prefix+"/projects/kubernetes.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var project Project
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &project))
+	require.Equal(t, "Kubernetes", project.Name)
+	require.False(t, project.AsOf.IsZero())
+
+	rec = get(t, h, Prefix+"/projects/Kubernetes")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `<a href="https://github.com/janedoe">@janedoe</a>`)
+	require.Contains(t, rec.Body.String(), "1 active maintainer as of")
+	require.NotContains(t, rec.Body.String(), "example.org")
+	require.NotContains(t, rec.Body.String(), "Ray Emeritus")
+
+	rec = get(t, h, Prefix+"/")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `<a href="/directory/projects/kubectl">kubectl</a> <span class="muted">in Kubernetes</span>`)
+
+	rec = get(t, h, Prefix+"/projects/Nope.json")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Empty(t, rec.Header().Get("Cache-Control"))
+	require.Equal(t, http.StatusNotFound, get(t, h, Prefix+"/projects/Nope").Code)
+}
+
+func TestNewProjectListsMaintainersWithoutStatus(t *testing.T) {
+	info := model.ProjectInfo{
+		Project: model.Project{Name: "Jaeger", Maturity: model.Graduated},
+		Maintainers: []model.Maintainer{
+			{Name: "Ada Lovelace", GitHubAccount: "ada", MaintainerStatus: model.ActiveMaintainer},
+			{Name: "Grace Hopper", GitHubAccount: "grace"},
+			{Name: "Ray Emeritus", GitHubAccount: "ray", MaintainerStatus: model.EmeritusMaintainer},
+		},
+	}
+	p := newProject(info, nil, time.Time{})
+	require.Equal(t, []Maintainer{{Name: "Ada Lovelace", GitHub: "ada"}, {Name: "Grace Hopper", GitHub: "grace"}}, p.Maintainers,
+		"a maintainer whose status was never set is Active")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"gorm.io/gorm"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,11 @@ func (s MaintainerStatus) IsValid() bool {
 	return false
 }
 
+// IsActive returns true if the maintainer is Active, which a maintainer whose status was never set is
+func (s MaintainerStatus) IsActive() bool {
+	return s == ActiveMaintainer || s == ""
+}
+
 func (s *MaintainerStatus) Scan(value interface{ any }) error {
 	v, ok := value.(string)
 	if !ok {
@@ -74,6 +80,16 @@ func (m Maturity) IsValid() bool {
 	return false
 }
 
+// In returns true if m is one of names, ignoring case
+func (m Maturity) In(names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, string(m)) {
+			return true
+		}
+	}
+	return false
+}
+
 // A Maintainer is a leader that can speak for a Project
 //
 // At registration, an email needs to be provided
@@ -117,6 +133,14 @@ type Project struct {
 	Services        []Service    `gorm:"many2many:service_projects;joinForeignKey:ProjectID;joinReferences:ServiceID"`
 }
 
+// ProjectRepo is a source repository of a project, as listed in the CNCF landscape.
+type ProjectRepo struct {
+	gorm.Model
+	ProjectID uint    `gorm:"index"`
+	URL       string  `gorm:"uniqueIndex;not null"`
+	Project   Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
 type MaintainerProject struct {
 	MaintainerID uint       `gorm:"primaryKey;index"` // FK + index
 	ProjectID    uint       `gorm:"primaryKey;index"` // FK + index
@@ -176,7 +200,153 @@ type FoundationOfficer struct {
 	GitHubAccount string `gorm:"size:100;default:GITHUB_MISSING"`
 	RegisteredAt  *time.Time
 	CompanyID     *uint
-	Services      []ServiceUser
+	Services      []ServiceUser `gorm:"many2many:foundation_officer_services"`
+}
+
+// APIToken authenticates a FoundationOfficer to the write API. Only the SHA-256 of the token is stored.
+type APIToken struct {
+	gorm.Model
+	FoundationOfficerID uint `gorm:"index"`
+	FoundationOfficer   FoundationOfficer
+	Name                string // what the token is for, e.g. projects-team-laptop
+	TokenHash           string `gorm:"size:64;uniqueIndex"`
+	ExpiresAt           *time.Time
+	RevokedAt           *time.Time
+}
+
+// ChangeRequestKind is what a ChangeRequest changes.
+type ChangeRequestKind string
+
+const (
+	ChangeEmail         ChangeRequestKind = "email"
+	ChangeCompany       ChangeRequestKind = "company"
+	ChangeServiceAccess ChangeRequestKind = "service_access"
+)
+
+func (k ChangeRequestKind) IsValid() bool {
+	switch k {
+	case ChangeEmail, ChangeCompany, ChangeServiceAccess:
+		return true
+	}
+	return false
+}
+
+// ChangeRequestStatus is where a ChangeRequest is in the approval queue.
+type ChangeRequestStatus string
+
+const (
+	ChangePending  ChangeRequestStatus = "pending"
+	ChangeApproved ChangeRequestStatus = "approved"
+	ChangeRejected ChangeRequestStatus = "rejected"
+)
+
+// ChangeRequest is a change a Maintainer asked for through the self-service portal. It is applied when a foundation
+// officer approves it. Email and company requests carry the new value in Value, service access requests name the
+// Project whose team on the Service the maintainer wants to join.
+type ChangeRequest struct {
+	gorm.Model
+	MaintainerID uint `gorm:"index"`
+	Maintainer   Maintainer
+	Kind         ChangeRequestKind
+	Value        string
+	ProjectID    *uint               `gorm:"index"`
+	ServiceID    *uint               `gorm:"index"`
+	Status       ChangeRequestStatus `gorm:"index"`
+	ReviewedBy   string              // GitHub account of the officer who approved or rejected the request
+	ReviewedAt   *time.Time
+	Note         string // the officer's reason, or what approving the request did
+}
+
+// WebhookEvent is a change in the registry that WebhookSubscriptions can be sent.
+type WebhookEvent string
+
+const (
+	EventMaintainerAdded         WebhookEvent = "maintainer.added"
+	EventMaintainerRemoved       WebhookEvent = "maintainer.removed"
+	EventMaintainerStatusChanged WebhookEvent = "maintainer.status_changed"
+	EventProjectMaturityChanged  WebhookEvent = "project.maturity_changed"
+	EventServiceAccessGranted    WebhookEvent = "service.access_granted"
+)
+
+// WebhookEvents lists every WebhookEvent.
+var WebhookEvents = []WebhookEvent{
+	EventMaintainerAdded,
+	EventMaintainerRemoved,
+	EventMaintainerStatusChanged,
+	EventProjectMaturityChanged,
+	EventServiceAccessGranted,
+}
+
+func (e WebhookEvent) IsValid() bool {
+	for _, known := range WebhookEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription is an HTTP endpoint that is sent the Events, e.g. maintainer.added, recorded after it was
+// created. Deliveries are signed with Secret.
+type WebhookSubscription struct {
+	gorm.Model
+	URL       string
+	Secret    string
+	Events    string // comma separated event names
+	CreatedBy string // GitHub account of the officer who registered the subscription
+}
+
+// EventList returns the events s is sent.
+func (s WebhookSubscription) EventList() []WebhookEvent {
+	var events []WebhookEvent
+	for _, e := range strings.Split(s.Events, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			events = append(events, WebhookEvent(e))
+		}
+	}
+	return events
+}
+
+// Subscribes reports whether s is sent event.
+func (s WebhookSubscription) Subscribes(event WebhookEvent) bool {
+	for _, e := range s.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is where a WebhookDelivery is in being sent.
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed" // gave up after the last attempt
+)
+
+// WebhookDelivery is an event sent, or to be sent, to a WebhookSubscription. Payload is fixed when the delivery is
+// queued so that every attempt sends the same body.
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint   `gorm:"index"`
+	AuditLogID     uint   `gorm:"index"` // the audit log entry the event was read from
+	Event          string `gorm:"index"`
+	Payload        string
+	Status         WebhookDeliveryStatus `gorm:"index"`
+	Attempts       int
+	NextAttemptAt  *time.Time `gorm:"index"`
+	ResponseStatus int        // HTTP status of the last attempt, 0 when no response was received
+	LastError      string
+	DeliveredAt    *time.Time
+}
+
+// WebhookCursor is the last audit log entry read for webhook events.
+type WebhookCursor struct {
+	ID         uint `gorm:"primarykey"`
+	AuditLogID uint
+	UpdatedAt  time.Time
 }
 
 type ReconciliationResult struct {
@@ -186,21 +356,38 @@ type ReconciliationResult struct {
 	MissingMaintainerIDs []*uint
 }
 
-// ProjectInfo is an in-memory cache. TODO Review this
+// ProjectInfo is a Project with its Maintainers and Services, it is the entry type of the in-memory db.ProjectCache
 type ProjectInfo struct {
 	Project     Project
 	Maintainers []Maintainer
 	Services    []Service
 }
 
+// ProjectTree is a Project with its subprojects, and theirs, beneath it
+type ProjectTree struct {
+	Project     Project
+	Subprojects []ProjectTree
+}
+
 type AuditLog struct {
 	gorm.Model
-	ProjectID    uint   `gorm:"index"`
-	MaintainerID *uint  `gorm:"index"`
-	ServiceID    *uint  `gorm:"index"`
-	Action       string `gorm:"index"` // e.g. "ADD_MEMBER", "REMOVE_MEMBER", "INVITE_SENT"
-	Message      string // human-readable message, optional
-	Metadata     string // optional JSON blob for advanced inspection
+	ProjectID     uint   `gorm:"index"`
+	MaintainerID  *uint  `gorm:"index"`
+	ServiceID     *uint  `gorm:"index"`
+	Action        string `gorm:"index"` // e.g. "ADD_MEMBER", "REMOVE_MEMBER", "INVITE_SENT"
+	Actor         string `gorm:"index"` // who caused the change, e.g. a GitHub login or "system"
+	CorrelationID string `gorm:"index"` // ties together entries written for one request or run
+	Message       string // human-readable message, optional
+	Metadata      string // optional JSON blob for advanced inspection, holds "before" and "after" for mutations
+}
+
+// SheetSyncState is the value maintainerd last wrote to, or found in agreement with, a maintainer's cell in the
+// worksheet. It is the common ancestor used to tell a change made in maintainerd from one made in the sheet.
+type SheetSyncState struct {
+	gorm.Model
+	Email  string `gorm:"uniqueIndex:idx_sheet_sync_cell"`
+	Column string `gorm:"uniqueIndex:idx_sheet_sync_cell"`
+	Value  string
 }
 
 type OnboardingTask struct {
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// event is a webhook event read from an audit log entry, from and to are the old and new value of what changed.
+type event struct {
+	name     model.WebhookEvent
+	from, to string
+}
+
+// eventOf returns the webhook event recorded by entry, if it records one.
+func eventOf(entry model.AuditLog) (event, bool) {
+	switch entry.Action {
+	case "CREATE_MAINTAINER_PROJECTS":
+		return event{name: model.EventMaintainerAdded}, true
+	case "DELETE_MAINTAINER_PROJECTS":
+		return event{name: model.EventMaintainerRemoved}, true
+	case "UPDATE_MAINTAINERS":
+		return changed(entry, model.EventMaintainerStatusChanged, "maintainer_status", "MaintainerStatus")
+	case "UPDATE_PROJECTS":
+		return changed(entry, model.EventProjectMaturityChanged, "maturity", "Maturity")
+	case "INVITE_SENT":
+		return event{name: model.EventServiceAccessGranted}, true
+	}
+	return event{}, false
+}
+
+// changed returns name when entry changed a field. The database records the field by its column, the MemoryStore by
+// its Go name.
+func changed(entry model.AuditLog, name model.WebhookEvent, column, field string) (event, bool) {
+	var metadata struct {
+		Before map[string]any `json:"before"`
+		After  map[string]any `json:"after"`
+	}
+	if err := json.Unmarshal([]byte(entry.Metadata), &metadata); err != nil || metadata.Before == nil || metadata.After == nil {
+		return event{}, false
+	}
+	value := func(row map[string]any) string {
+		if v, ok := row[column]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		if v, ok := row[field]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	from, to := value(metadata.Before), value(metadata.After)
+	if from == to {
+		return event{}, false
+	}
+	return event{name: name, from: from, to: to}, true
+}
+
+// registryNames are the names of the projects, maintainers and services that audit log entries refer to by id.
+type registryNames struct {
+	projects    map[uint]string
+	maintainers map[uint]model.Maintainer
+	services    map[uint]string
+}
+
+func loadNames(ctx context.Context, store db.Store) (*registryNames, error) {
+	names := &registryNames{projects: map[uint]string{}, services: map[uint]string{}}
+	projects, err := store.GetProjectMapByName(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading projects: %w", err)
+	}
+	for name, p := range projects {
+		names.projects[p.ID] = name
+	}
+	// entries refer to maintainers by id, the one key every maintainer has
+	if names.maintainers, err = store.GetMaintainerMapByID(ctx); err != nil {
+		return nil, fmt.Errorf("loading maintainers: %w", err)
+	}
+	services, err := store.ListServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading services: %w", err)
+	}
+	for _, s := range services {
+		names.services[s.ID] = s.Name
+	}
+	return names, nil
+}
+
+// notification returns the body sent to subscriptions for e, read from entry.
+func (n *registryNames) notification(entry model.AuditLog, e event) v1.Notification {
+	notification := v1.Notification{
+		ID:            entry.ID,
+		Event:         string(e.name),
+		OccurredAt:    entry.CreatedAt.UTC(),
+		Actor:         entry.Actor,
+		CorrelationID: entry.CorrelationID,
+		Project:       n.projects[entry.ProjectID],
+		From:          e.from,
+		To:            e.to,
+	}
+	if entry.MaintainerID != nil {
+		m := n.maintainers[*entry.MaintainerID]
+		notification.MaintainerName = m.Name
+		if !db.MissingValue(m.GitHubAccount) {
+			notification.Maintainer = m.GitHubAccount
+		}
+	}
+	if entry.ServiceID != nil {
+		notification.Service = n.services[*entry.ServiceID]
+	}
+	return notification
+}
@@ -0,0 +1,291 @@
+// Package notify sends the changes recorded in the audit log to the HTTP endpoints of webhook subscriptions, so that
+// other systems learn when a roster changes. Each Notification is signed with the subscription's secret the way
+// GitHub signs the webhooks maintainerd receives, and is retried with backoff until the endpoint accepts it or
+// MaxAttempts is reached. Every attempt is recorded as a model.WebhookDelivery.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+const (
+	// DefaultInterval is how often the audit log is read for events and due deliveries are sent.
+	DefaultInterval = 10 * time.Second
+	// DefaultMaxAttempts is how many times a delivery is tried before it is failed, about an hour after the first.
+	DefaultMaxAttempts = 8
+	// DefaultTimeout bounds each attempt.
+	DefaultTimeout = 10 * time.Second
+
+	firstRetry = 30 * time.Second
+	maxRetry   = time.Hour
+	// batchSize is how many due deliveries are sent each interval
+	batchSize = 100
+)
+
+// Outcomes of a delivery attempt passed to Dispatcher.Observe.
+const (
+	OutcomeDelivered = "delivered"
+	OutcomeRetrying  = "retrying"
+	OutcomeFailed    = "failed"
+)
+
+// Dispatcher queues a delivery of every event in the audit log to the subscriptions that want it and sends them.
+type Dispatcher struct {
+	Store       db.Store
+	Client      *http.Client
+	Interval    time.Duration // DefaultInterval when zero
+	MaxAttempts int           // DefaultMaxAttempts when zero
+	// Observe, when set, is called with the event and outcome of every attempt
+	Observe func(event, outcome string)
+
+	cursorSaved bool // the cursor has been stored, so entries recorded since are not skipped
+}
+
+// NewDispatcher returns a Dispatcher sending the events recorded in store.
+func NewDispatcher(store db.Store) *Dispatcher {
+	return &Dispatcher{
+		Store: store,
+		Client: &http.Client{
+			Timeout: DefaultTimeout,
+			// a subscription's URL is where it is sent, redirects are answered as failures
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		Interval:    DefaultInterval,
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+// Sign returns the signature of body sent in v1.SignatureHeader, "sha256=" and the hex HMAC-SHA256 of body keyed with
+// secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Run polls every Interval until ctx is done. An attempt under way when ctx is done is allowed to finish so that its
+// outcome is recorded.
+func (d *Dispatcher) Run(ctx context.Context) {
+	interval := d.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := d.Poll(ctx); err != nil {
+			log.Printf("notify: ERR, %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll queues deliveries of the events recorded since the last poll and sends the deliveries that are due.
+func (d *Dispatcher) Poll(ctx context.Context) error {
+	if err := d.Enqueue(ctx); err != nil {
+		return err
+	}
+	return d.Deliver(ctx)
+}
+
+// Enqueue reads the audit log entries recorded since it last ran and queues a delivery of each event to every
+// subscription that wants it and existed when the event happened.
+func (d *Dispatcher) Enqueue(ctx context.Context) error {
+	cursor, err := d.Store.WebhookCursor(ctx)
+	if err != nil {
+		return fmt.Errorf("Enqueue: %w", err)
+	}
+	entries, err := d.Store.ListAuditLogs(ctx, db.AuditFilter{AfterID: cursor})
+	if err != nil {
+		return fmt.Errorf("Enqueue: %w", err)
+	}
+	if len(entries) == 0 {
+		if d.cursorSaved {
+			return nil
+		}
+		if err := d.Store.EnqueueWebhookDeliveries(ctx, cursor, nil); err != nil {
+			return fmt.Errorf("Enqueue: %w", err)
+		}
+		d.cursorSaved = true
+		return nil
+	}
+	subs, err := d.Store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("Enqueue: %w", err)
+	}
+
+	// entries are sent in the order they were recorded, and the cursor moves to the greatest id read, whatever the
+	// order of their timestamps
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	var names *registryNames
+	var deliveries []model.WebhookDelivery
+	for _, entry := range entries {
+		event, ok := eventOf(entry)
+		if !ok {
+			continue
+		}
+		var payload []byte
+		for _, sub := range subs {
+			if !sub.Subscribes(event.name) || entry.CreatedAt.Before(sub.CreatedAt) {
+				continue
+			}
+			if payload == nil {
+				if names == nil {
+					if names, err = loadNames(ctx, d.Store); err != nil {
+						return fmt.Errorf("Enqueue: %w", err)
+					}
+				}
+				if payload, err = json.Marshal(names.notification(entry, event)); err != nil {
+					return fmt.Errorf("Enqueue: encoding %s: %w", event.name, err)
+				}
+			}
+			deliveries = append(deliveries, model.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				AuditLogID:     entry.ID,
+				Event:          string(event.name),
+				Payload:        string(payload),
+			})
+		}
+	}
+	if err := d.Store.EnqueueWebhookDeliveries(ctx, entries[len(entries)-1].ID, deliveries); err != nil {
+		return fmt.Errorf("Enqueue: %w", err)
+	}
+	d.cursorSaved = true
+	if len(deliveries) > 0 {
+		log.Printf("notify: INF, queued %d deliveries", len(deliveries))
+	}
+	return nil
+}
+
+// Deliver sends the deliveries that are due, stopping early when ctx is done.
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	due, err := d.Store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{DueBy: time.Now(), Limit: batchSize})
+	if err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	subs, err := d.Store.ListWebhookSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("Deliver: %w", err)
+	}
+	byID := make(map[uint]model.WebhookSubscription, len(subs))
+	for _, sub := range subs {
+		byID[sub.ID] = sub
+	}
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return nil
+		}
+		sub, ok := byID[delivery.SubscriptionID]
+		if !ok {
+			// deleted since the list of due deliveries was read
+			continue
+		}
+		if err := d.attempt(context.WithoutCancel(ctx), sub, delivery); err != nil {
+			return fmt.Errorf("Deliver: %w", err)
+		}
+	}
+	return nil
+}
+
+// attempt sends delivery to sub once and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) error {
+	status, sendErr := d.send(ctx, sub, delivery)
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	outcome := OutcomeDelivered
+	switch {
+	case sendErr == nil:
+		delivery.Status = model.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		log.Printf("notify: INF, delivered %s %d to subscription %d (%d)", delivery.Event, delivery.ID, sub.ID, status)
+	case delivery.Attempts >= d.maxAttempts():
+		outcome = OutcomeFailed
+		delivery.Status = model.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = sendErr.Error()
+		log.Printf("notify: ERR, giving up on %s %d to subscription %d after %d attempts: %v",
+			delivery.Event, delivery.ID, sub.ID, delivery.Attempts, sendErr)
+	default:
+		outcome = OutcomeRetrying
+		next := now.Add(backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = sendErr.Error()
+		log.Printf("notify: WRN, attempt %d of %s %d to subscription %d failed, retrying at %s: %v",
+			delivery.Attempts, delivery.Event, delivery.ID, sub.ID, next.UTC().Format(time.RFC3339), sendErr)
+	}
+	if d.Observe != nil {
+		d.Observe(delivery.Event, outcome)
+	}
+	return d.Store.UpdateWebhookDelivery(ctx, delivery)
+}
+
+// send POSTs the payload of delivery to sub, returning the response status when there was a response.
+func (d *Dispatcher) send(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "maintainerd-webhook")
+	req.Header.Set(v1.EventHeader, delivery.Event)
+	req.Header.Set(v1.DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(v1.SignatureHeader, Sign([]byte(sub.Secret), body))
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) maxAttempts() int {
+	if d.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return d.MaxAttempts
+}
+
+// backoff returns how long to wait after the given number of failed attempts: 30s, 1m, 2m and so on up to an hour.
+func backoff(attempts int) time.Duration {
+	wait := firstRetry
+	for i := 1; i < attempts && wait < maxRetry; i++ {
+		wait *= 2
+	}
+	return min(wait, maxRetry)
+}
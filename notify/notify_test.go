@@ -0,0 +1,297 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/pkg/client"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// subscriber is a webhook endpoint that answers with the statuses it is given, then 204.
+type subscriber struct {
+	mu        sync.Mutex
+	statuses  []int
+	received  []*v1.Notification
+	events    []string
+	delivered []string
+}
+
+func (s *subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	n, err := client.VerifyNotification([]byte("s3cret"), body, r.Header.Get(v1.SignatureHeader))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+	s.received = append(s.received, n)
+	s.events = append(s.events, r.Header.Get(v1.EventHeader))
+	s.delivered = append(s.delivered, r.Header.Get(v1.DeliveryHeader))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestStore(t *testing.T) *db.MemoryStore {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	return store
+}
+
+func TestDispatcher(t *testing.T) {
+	store := newTestStore(t)
+	sub := &subscriber{}
+	srv := httptest.NewServer(sub)
+	t.Cleanup(srv.Close)
+	ctx := db.WithActor(context.Background(), "octocat")
+
+	// changes made before subscribing are not sent
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	jaeger := projects["Jaeger"]
+	_, err = store.AddMaintainerToProject(ctx, jaeger.ID, model.Maintainer{Name: "Early Bird", GitHubAccount: "early"}, "")
+	require.NoError(t, err)
+	d := NewDispatcher(store)
+	require.NoError(t, d.Poll(ctx))
+
+	_, err = store.CreateWebhookSubscription(ctx, model.WebhookSubscription{
+		URL:    srv.URL,
+		Secret: "s3cret",
+		Events: "maintainer.added,maintainer.status_changed,service.access_granted",
+	})
+	require.NoError(t, err)
+	_, err = store.CreateWebhookSubscription(ctx, model.WebhookSubscription{
+		URL:    srv.URL,
+		Secret: "another secret",
+		Events: "maintainer.removed",
+	})
+	require.NoError(t, err)
+
+	octo, err := store.AddMaintainerToProject(ctx, jaeger.ID, model.Maintainer{Name: "Octo Cat", GitHubAccount: "octocat"}, "GitHub")
+	require.NoError(t, err)
+	require.NoError(t, store.SetMaintainerCompany(ctx, octo.ID, "Example Inc"))
+	require.NoError(t, store.SetMaintainerStatus(ctx, octo.ID, model.EmeritusMaintainer))
+	fossa, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+	require.NoError(t, store.LogAuditEvent(ctx, zap.NewNop().Sugar(), model.AuditLog{
+		ProjectID: jaeger.ID, MaintainerID: &octo.ID, ServiceID: &fossa.ID, Action: "INVITE_SENT",
+	}))
+
+	var outcomes []string
+	d.Observe = func(event, outcome string) { outcomes = append(outcomes, event+" "+outcome) }
+	require.NoError(t, d.Poll(ctx))
+	require.Equal(t, []string{"maintainer.added", "maintainer.status_changed", "service.access_granted"}, sub.events)
+	require.Equal(t, []string{
+		"maintainer.added delivered", "maintainer.status_changed delivered", "service.access_granted delivered",
+	}, outcomes)
+
+	added := sub.received[0]
+	require.Equal(t, "maintainer.added", added.Event)
+	require.Equal(t, "octocat", added.Actor)
+	require.Equal(t, "Jaeger", added.Project)
+	require.Equal(t, "octocat", added.Maintainer)
+	require.Equal(t, "Octo Cat", added.MaintainerName)
+	status := sub.received[1]
+	require.Equal(t, "Active", status.From)
+	require.Equal(t, "Emeritus", status.To)
+	require.Equal(t, "FOSSA", sub.received[2].Service)
+
+	// nothing is sent twice
+	require.NoError(t, d.Poll(ctx))
+	require.Len(t, sub.received, 3)
+
+	// the removal goes to the second subscription, whose secret the subscriber does not hold, so it is retried
+	require.NoError(t, store.RemoveMaintainerFromProject(ctx, jaeger.ID, octo.ID))
+	d.MaxAttempts = 2
+	require.NoError(t, d.Poll(ctx))
+	pending, err := store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{Status: model.DeliveryPending})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	retry := pending[0]
+	require.Equal(t, 1, retry.Attempts)
+	require.Equal(t, http.StatusUnauthorized, retry.ResponseStatus)
+	require.Contains(t, retry.LastError, "401")
+	require.WithinDuration(t, time.Now().Add(firstRetry), *retry.NextAttemptAt, 5*time.Second)
+
+	now := time.Now()
+	retry.NextAttemptAt = &now
+	require.NoError(t, store.UpdateWebhookDelivery(ctx, retry))
+	require.NoError(t, d.Poll(ctx))
+	failed, err := store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{Status: model.DeliveryFailed})
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	require.Equal(t, 2, failed[0].Attempts)
+	require.Nil(t, failed[0].NextAttemptAt)
+	require.Equal(t, "maintainer.removed failed", outcomes[len(outcomes)-1])
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	store := newTestStore(t)
+	sub := &subscriber{statuses: []int{http.StatusBadGateway}}
+	srv := httptest.NewServer(sub)
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	d := NewDispatcher(store)
+	require.NoError(t, d.Poll(ctx))
+	_, err := store.CreateWebhookSubscription(ctx, model.WebhookSubscription{URL: srv.URL, Secret: "s3cret", Events: "maintainer.removed"})
+	require.NoError(t, err)
+	jane, err := store.GetMaintainerByGitHubAccount(ctx, "janedoe")
+	require.NoError(t, err)
+	require.NoError(t, store.RemoveMaintainerFromProject(ctx, jane.Projects[0].ID, jane.ID))
+
+	require.NoError(t, d.Poll(ctx))
+	require.Empty(t, sub.received)
+	pending, err := store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{Status: model.DeliveryPending})
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, http.StatusBadGateway, pending[0].ResponseStatus)
+
+	now := time.Now()
+	pending[0].NextAttemptAt = &now
+	require.NoError(t, store.UpdateWebhookDelivery(ctx, pending[0]))
+	require.NoError(t, d.Poll(ctx))
+	require.Len(t, sub.received, 1)
+	require.Equal(t, "janedoe", sub.received[0].Maintainer)
+	require.Equal(t, "Kubernetes", sub.received[0].Project)
+
+	all, err := store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	require.Equal(t, model.DeliveryDelivered, all[0].Status)
+	require.Equal(t, 2, all[0].Attempts)
+	require.Empty(t, all[0].LastError)
+	require.NotNil(t, all[0].DeliveredAt)
+	require.Equal(t, []string{"1"}, sub.delivered)
+}
+
+// newTestSQLStore returns a store on a SQLite file seeded with the fixtures, which unlike the memory store fills in
+// the column defaults for missing values.
+func newTestSQLStore(t *testing.T) *db.SQLStore {
+	t.Helper()
+	conn, err := db.OpenSQLite(filepath.Join(t.TempDir(), "maintainers.db"))
+	require.NoError(t, err)
+	fixtures, err := db.LoadFixtures("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	require.NoError(t, db.SeedFixtures(context.Background(), conn, fixtures))
+	return db.NewSQLStore(conn)
+}
+
+func TestDispatcherNamesMaintainersWithoutGitHub(t *testing.T) {
+	store := newTestSQLStore(t)
+	sub := &subscriber{}
+	srv := httptest.NewServer(sub)
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	d := NewDispatcher(store)
+	require.NoError(t, d.Poll(ctx))
+	_, err := store.CreateWebhookSubscription(ctx, model.WebhookSubscription{URL: srv.URL, Secret: "s3cret", Events: "maintainer.added"})
+	require.NoError(t, err)
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	// both get the same GitHub account placeholder
+	for _, name := range []string{"First Newcomer", "Second Newcomer"} {
+		_, err := store.AddMaintainerToProject(ctx, projects["Jaeger"].ID, model.Maintainer{Name: name}, "")
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, d.Poll(ctx))
+	require.Len(t, sub.received, 2)
+	require.Equal(t, "First Newcomer", sub.received[0].MaintainerName)
+	require.Equal(t, "Second Newcomer", sub.received[1].MaintainerName)
+}
+
+func TestDispatcherFollowsIDs(t *testing.T) {
+	store := newTestSQLStore(t)
+	sub := &subscriber{}
+	srv := httptest.NewServer(sub)
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	d := NewDispatcher(store)
+	require.NoError(t, d.Poll(ctx))
+	_, err := store.CreateWebhookSubscription(ctx, model.WebhookSubscription{URL: srv.URL, Secret: "s3cret", Events: "service.access_granted"})
+	require.NoError(t, err)
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	fossa, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+
+	// the entry with the greater id has the earlier timestamp, as a clock step back or a slow commit can leave them
+	now := time.Now()
+	for _, at := range []time.Time{now.Add(time.Minute), now} {
+		entry := model.AuditLog{ProjectID: projects["Jaeger"].ID, ServiceID: &fossa.ID, Action: "INVITE_SENT"}
+		entry.CreatedAt = at
+		require.NoError(t, store.LogAuditEvent(ctx, zap.NewNop().Sugar(), entry))
+	}
+	entries, err := store.ListAuditLogs(ctx, db.AuditFilter{Action: "INVITE_SENT"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Less(t, entries[0].ID, entries[1].ID, "newest first is not greatest id first")
+
+	require.NoError(t, d.Poll(ctx))
+	require.NoError(t, d.Poll(ctx))
+	require.Len(t, sub.received, 2, "each entry is sent once")
+	require.Equal(t, entries[0].ID, sub.received[0].ID, "entries are sent in id order")
+	cursor, err := store.WebhookCursor(ctx)
+	require.NoError(t, err)
+	require.Equal(t, entries[1].ID, cursor)
+}
+
+func TestEventOf(t *testing.T) {
+	for _, tc := range []struct {
+		action, metadata string
+		want             event
+		ok               bool
+	}{
+		{action: "CREATE_MAINTAINER_PROJECTS", want: event{name: model.EventMaintainerAdded}, ok: true},
+		{action: "CREATE_MAINTAINERS"},
+		{
+			action:   "UPDATE_PROJECTS",
+			metadata: `{"table":"projects","before":{"maturity":"Incubating"},"after":{"maturity":"Graduated"},"changed":["maturity"]}`,
+			want:     event{name: model.EventProjectMaturityChanged, from: "Incubating", to: "Graduated"},
+			ok:       true,
+		},
+		{
+			action:   "UPDATE_PROJECTS",
+			metadata: `{"table":"projects","before":{"maturity":"Graduated","maintainer_ref":"a"},"after":{"maturity":"Graduated","maintainer_ref":"b"}}`,
+		},
+		{
+			action:   "UPDATE_MAINTAINERS",
+			metadata: `{"table":"maintainers","before":{"MaintainerStatus":"Active"},"after":{"MaintainerStatus":"Retired"}}`,
+			want:     event{name: model.EventMaintainerStatusChanged, from: "Active", to: "Retired"},
+			ok:       true,
+		},
+		{action: "UPDATE_MAINTAINERS", metadata: `{"statement":"UPDATE maintainers SET ...","before":null,"after":null}`},
+		{action: "INVITE_SENT", want: event{name: model.EventServiceAccessGranted}, ok: true},
+	} {
+		got, ok := eventOf(model.AuditLog{Action: tc.action, Metadata: tc.metadata})
+		require.Equal(t, tc.ok, ok, "%s %s", tc.action, tc.metadata)
+		require.Equal(t, tc.want, got, "%s %s", tc.action, tc.metadata)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, backoff(1))
+	require.Equal(t, time.Minute, backoff(2))
+	require.Equal(t, 32*time.Minute, backoff(7))
+	require.Equal(t, time.Hour, backoff(20))
+}
@@ -0,0 +1,104 @@
+package onboarding
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maintainerd/db"
+)
+
+const defaultAuditLimit = 100
+
+// requireAdmin only serves h to requests that carry AdminToken as a bearer token.
+func (s *EventListener) requireAdmin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.AdminToken) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), s.AdminToken) != 1 {
+			http.Error(w, "requireAdmin: missing or invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// handleAuditLog serves the audit log as JSON. Entries can be filtered with the project, maintainer (GitHub account),
+// service, action, actor, correlation_id, since and until query parameters and are capped by limit.
+func (s *EventListener) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "handleAuditLog: method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	params := r.URL.Query()
+	query := db.AuditQuery{
+		Project:       params.Get("project"),
+		Maintainer:    params.Get("maintainer"),
+		Service:       params.Get("service"),
+		Action:        params.Get("action"),
+		Actor:         params.Get("actor"),
+		CorrelationID: params.Get("correlation_id"),
+		Since:         params.Get("since"),
+		Until:         params.Get("until"),
+		Limit:         defaultAuditLimit,
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			http.Error(w, "handleAuditLog: limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		query.Limit = n
+	}
+
+	filter, err := query.Filter(r.Context(), s.Store)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := s.Store.ListAuditLogs(r.Context(), filter)
+	if err != nil {
+		log.Printf("handleAuditLog: ERR, %v", err)
+		http.Error(w, "handleAuditLog: failed to read audit log", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		log.Printf("handleAuditLog: WRN, failed to write response: %v", err)
+	}
+}
+
+// handleRefreshProjects reloads the project cache so that projects added or changed by a bootstrap run are used
+// straight away.
+func (s *EventListener) handleRefreshProjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "handleRefreshProjects: method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := s.Projects.Refresh(r.Context()); err != nil {
+		log.Printf("handleRefreshProjects: ERR, %v", err)
+		http.Error(w, "handleRefreshProjects: failed to refresh projects", http.StatusInternalServerError)
+		return
+	}
+	projects, err := s.Projects.Projects(r.Context())
+	if err != nil {
+		http.Error(w, "handleRefreshProjects: failed to read projects", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("handleRefreshProjects: INF, project cache reloaded with %d projects", len(projects))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"projects":  len(projects),
+		"loaded_at": s.Projects.LoadedAt(),
+	}); err != nil {
+		log.Printf("handleRefreshProjects: WRN, failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	readyCheckTimeout = 5 * time.Second
+	// pluginCheckInterval is how long a plugin's readiness is remembered for, so that probes do not call its API
+	// every few seconds
+	pluginCheckInterval = time.Minute
+)
+
+// fossaBuckets are the upper bounds, in seconds, of the FOSSA request latency histogram.
+var fossaBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// serverMetrics are the metrics served on /metrics.
+type serverMetrics struct {
+	registry      *prometheus.Registry
+	webhooks      *prometheus.CounterVec
+	fossaRequests *prometheus.HistogramVec
+	fossaErrors   *prometheus.CounterVec
+	notifications *prometheus.CounterVec
+	jobs          prometheus.Gauge
+}
+
+// serverMetrics returns the EventListener's metrics, registering them on first use.
+func (s *EventListener) serverMetrics() *serverMetrics {
+	s.metricsOnce.Do(func() {
+		r := prometheus.NewRegistry()
+		m := &serverMetrics{
+			registry: r,
+			webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "maintainerd_webhook_deliveries_total",
+				Help: "GitHub webhook deliveries by event and outcome.",
+			}, []string{"event", "outcome"}),
+			fossaRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Name:    "maintainerd_fossa_request_duration_seconds",
+				Help:    "Latency of FOSSA API requests.",
+				Buckets: fossaBuckets,
+			}, []string{"method", "endpoint"}),
+			fossaErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "maintainerd_fossa_request_errors_total",
+				Help: "FOSSA API requests that failed, by status code or transport for requests without a response.",
+			}, []string{"method", "endpoint", "code"}),
+			notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Name: "maintainerd_notification_attempts_total",
+				Help: "Attempts to deliver change notifications to webhook subscriptions, by event and outcome.",
+			}, []string{"event", "outcome"}),
+			jobs: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "maintainerd_onboarding_jobs_in_flight",
+				Help: "Onboarding jobs started by GitHub webhooks that are still running.",
+			}),
+		}
+		r.MustRegister(
+			m.webhooks, m.fossaRequests, m.fossaErrors, m.notifications, m.jobs,
+			&storeCollector{listener: s},
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+		s.metrics = m
+	})
+	return s.metrics
+}
+
+// handler serves the metrics to Prometheus. Gauges that cannot be read from the store are left out of the scrape
+// and logged.
+func (m *serverMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{
+		ErrorLog:      metricsLog{},
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+type metricsLog struct{}
+
+func (metricsLog) Println(v ...any) {
+	log.Printf("metrics: WRN, %s", fmt.Sprint(v...))
+}
+
+var (
+	pendingChangesDesc = prometheus.NewDesc("maintainerd_change_requests_pending",
+		"Maintainer change requests waiting in the approval queue.", nil, nil)
+	pendingDeliveriesDesc = prometheus.NewDesc("maintainerd_notification_deliveries_pending",
+		"Change notifications waiting to be delivered or retried.", nil, nil)
+	rosterSizeDesc = prometheus.NewDesc("maintainerd_project_maintainers",
+		"Registered maintainers of each project.", []string{"project"}, nil)
+)
+
+// storeCollector reads the gauges that are kept in the store on every scrape.
+type storeCollector struct {
+	listener *EventListener
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pendingChangesDesc
+	ch <- pendingDeliveriesDesc
+	ch <- rosterSizeDesc
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), readyCheckTimeout)
+	defer cancel()
+	s := c.listener
+
+	if pending, err := s.Store.ListChangeRequests(ctx, db.ChangeRequestFilter{Status: model.ChangePending}); err != nil {
+		ch <- prometheus.NewInvalidMetric(pendingChangesDesc, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(pendingChangesDesc, prometheus.GaugeValue, float64(len(pending)))
+	}
+	if pending, err := s.Store.ListWebhookDeliveries(ctx, db.WebhookDeliveryFilter{Status: model.DeliveryPending}); err != nil {
+		ch <- prometheus.NewInvalidMetric(pendingDeliveriesDesc, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(pendingDeliveriesDesc, prometheus.GaugeValue, float64(len(pending)))
+	}
+	// roster sizes are read from the project cache, scrapes do not query the database while it is fresh
+	projects, err := s.Projects.Projects(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(rosterSizeDesc, err)
+		return
+	}
+	for name, info := range projects {
+		ch <- prometheus.MustNewConstMetric(rosterSizeDesc, prometheus.GaugeValue, float64(len(info.Maintainers)), name)
+	}
+}
+
+// countWebhook records the outcome of a webhook delivery.
+func (s *EventListener) countWebhook(event, outcome string) {
+	if event == "" {
+		event = "unknown"
+	}
+	s.serverMetrics().webhooks.WithLabelValues(event, outcome).Inc()
+}
+
+// countNotification records the outcome of an attempt to deliver a change notification.
+func (s *EventListener) countNotification(event, outcome string) {
+	s.serverMetrics().notifications.WithLabelValues(event, outcome).Inc()
+}
+
+// instrumentFossa times the FossaClient's requests and counts its errors.
+func (s *EventListener) instrumentFossa() {
+	if s.FossaClient == nil {
+		return
+	}
+	if s.FossaClient.HTTPClient == nil {
+		s.FossaClient.HTTPClient = &http.Client{}
+	}
+	if _, ok := s.FossaClient.HTTPClient.Transport.(*fossaTransport); ok {
+		return
+	}
+	base := s.FossaClient.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	m := s.serverMetrics()
+	s.FossaClient.HTTPClient.Transport = &fossaTransport{base: base, requests: m.fossaRequests, errors: m.fossaErrors}
+}
+
+type fossaTransport struct {
+	base     http.RoundTripper
+	requests *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func (t *fossaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	endpoint := fossaEndpoint(req.URL.Path)
+	t.requests.WithLabelValues(req.Method, endpoint).Observe(time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		t.errors.WithLabelValues(req.Method, endpoint, "transport").Inc()
+	case resp.StatusCode >= 400:
+		t.errors.WithLabelValues(req.Method, endpoint, fmt.Sprint(resp.StatusCode)).Inc()
+	}
+	return resp, err
+}
+
+// fossaEndpoint returns path with the ids in it replaced, so that /api/teams/12/users is counted as
+// /api/teams/{id}/users.
+func fossaEndpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.Atoi(segment); err == nil {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// readyCheck is something that must work for maintainerd to serve requests.
+type readyCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// cachedCheck remembers the result of check for ttl.
+type cachedCheck struct {
+	check   func(ctx context.Context) error
+	ttl     time.Duration
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+func (c *cachedCheck) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.checked.IsZero() || time.Since(c.checked) > c.ttl {
+		c.err = c.check(ctx)
+		c.checked = time.Now()
+	}
+	return c.err
+}
+
+// readyChecks returns the checks /readyz runs: the database and every configured plugin.
+func (s *EventListener) readyChecks() []readyCheck {
+	checks := []readyCheck{{name: "database", check: s.Store.Ping}}
+	if s.FossaClient != nil {
+		fossaCheck := &cachedCheck{check: s.FossaClient.Ping, ttl: pluginCheckInterval}
+		checks = append(checks, readyCheck{name: "fossa", check: fossaCheck.Check})
+	}
+	return checks
+}
+
+// handleHealthz reports that the process is up, it does not check anything it depends on.
+func (s *EventListener) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz runs checks and reports each one, answering 503 when any fail.
+func handleReadyz(checks []readyCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+		defer cancel()
+
+		type result struct {
+			name string
+			err  error
+		}
+		results := make([]result, len(checks))
+		var wg sync.WaitGroup
+		for i, c := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = result{name: c.name, err: c.check(ctx)}
+			}()
+		}
+		wg.Wait()
+		sort.Slice(results, func(i, j int) bool { return results[i].name < results[j].name })
+
+		var b strings.Builder
+		status := http.StatusOK
+		for _, res := range results {
+			if res.err != nil {
+				status = http.StatusServiceUnavailable
+				fmt.Fprintf(&b, "[-]%s failed: %v\n", res.name, res.err)
+			} else {
+				fmt.Fprintf(&b, "[+]%s ok\n", res.name)
+			}
+		}
+		if status == http.StatusOK {
+			b.WriteString("ready\n")
+		} else {
+			b.WriteString("not ready\n")
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		fmt.Fprint(w, b.String())
+	}
+}
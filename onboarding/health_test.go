@@ -0,0 +1,107 @@
+package onboarding
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"maintainerd/db"
+	"maintainerd/model"
+	"maintainerd/plugins/fossa"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthAndMetrics(t *testing.T) {
+	fossaUp := true
+	fossaAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fossaUp || r.URL.Path != "/users" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "[]")
+	}))
+	defer fossaAPI.Close()
+
+	fixtures, err := db.ParseFixtures([]byte(testFixtures))
+	require.NoError(t, err)
+	store := db.NewMemoryStore()
+	require.NoError(t, store.Load(fixtures))
+	ada, err := store.GetMaintainerByGitHubAccount(context.Background(), "ada")
+	require.NoError(t, err)
+	_, err = store.RequestChange(context.Background(), model.ChangeRequest{MaintainerID: ada.ID, Kind: model.ChangeCompany, Value: "Analytical Engines"})
+	require.NoError(t, err)
+
+	fc := fossa.NewClient("test-token")
+	fc.APIBase = fossaAPI.URL
+	listener := &EventListener{Store: store, FossaClient: fc, Secret: []byte("secret")}
+	handler, err := listener.Handler()
+	require.NoError(t, err)
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/healthz")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "ok\n", body)
+
+	status, body = get("/readyz")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "[+]database ok\n[+]fossa ok\nready\n", body)
+
+	resp, err := http.Post(srv.URL+"/webhook", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	fossaUp = false
+	status, _ = get("/readyz")
+	require.Equal(t, http.StatusOK, status, "FOSSA's readiness is remembered")
+	require.Error(t, fc.Ping(context.Background()))
+
+	require.NoError(t, store.EnqueueWebhookDeliveries(context.Background(), 0, []model.WebhookDelivery{
+		{SubscriptionID: 1, Event: "maintainer.added", Status: model.DeliveryPending},
+	}))
+	release := make(chan struct{})
+	require.True(t, listener.startJob(func() { <-release }))
+	defer close(release)
+
+	status, body = get("/metrics")
+	require.Equal(t, http.StatusOK, status)
+	for _, line := range []string{
+		`maintainerd_webhook_deliveries_total{event="unknown",outcome="invalid_signature"} 1`,
+		`maintainerd_fossa_request_duration_seconds_count{endpoint="/users",method="GET"} 2`,
+		`maintainerd_fossa_request_errors_total{code="503",endpoint="/users",method="GET"} 1`,
+		`maintainerd_change_requests_pending 1`,
+		`maintainerd_notification_deliveries_pending 1`,
+		`maintainerd_onboarding_jobs_in_flight 1`,
+		`maintainerd_project_maintainers{project="Jaeger"} 1`,
+	} {
+		require.Contains(t, body, line+"\n")
+	}
+	require.Contains(t, body, "\ngo_goroutines ", "the Go runtime is reported")
+
+	handler, err = (&EventListener{Store: store, FossaClient: fc}).Handler()
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Contains(t, rec.Body.String(), "[-]fossa failed: Ping failed: 503 Service Unavailable\n")
+}
+
+func TestFossaEndpoint(t *testing.T) {
+	require.Equal(t, "/api/teams/{id}/users", fossaEndpoint("/api/teams/12/users"))
+	require.Equal(t, "/api/organizations/{id}/invite", fossaEndpoint("/api/organizations/162/invite"))
+	require.Equal(t, "/api/users", fossaEndpoint("/api/users"))
+}
@@ -0,0 +1,172 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"maintainerd/notify"
+)
+
+// DefaultShutdownTimeout is how long Run waits for in-flight requests and webhook jobs once it is asked to stop.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// Timeouts of the HTTP server. Writes are allowed long enough for the admin UI and API to onboard a project to a
+// service, which waits on the service's API.
+const (
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+	writeTimeout      = 2 * time.Minute
+	idleTimeout       = 2 * time.Minute
+)
+
+// Run serves on addr and sends change notifications to webhook subscriptions until ctx is done. It then stops
+// accepting connections and waits up to ShutdownTimeout for in-flight requests, webhook jobs and notifications to
+// finish before returning.
+func (s *EventListener) Run(ctx context.Context, addr string) error {
+	handler, err := s.Handler()
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	return s.serve(ctx, srv, listener)
+}
+
+func (s *EventListener) serve(ctx context.Context, srv *http.Server, listener net.Listener) error {
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(listener) }()
+	notified := make(chan struct{})
+	go func() {
+		defer close(notified)
+		s.notifier().Run(ctx)
+	}()
+
+	select {
+	case err := <-served:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	log.Printf("Run: INF, shutting down, waiting up to %s for requests and webhook jobs", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	if err != nil {
+		err = fmt.Errorf("shutdown: %w", err)
+	}
+	if jobsErr := s.drainJobs(shutdownCtx); jobsErr != nil {
+		err = errors.Join(err, jobsErr)
+	}
+	select {
+	case <-notified:
+	case <-shutdownCtx.Done():
+		err = errors.Join(err, fmt.Errorf("webhook notifications still being sent: %w", shutdownCtx.Err()))
+	}
+	if served := <-served; !errors.Is(served, http.ErrServerClosed) {
+		err = errors.Join(err, served)
+	}
+	if err == nil {
+		log.Printf("Run: INF, shut down cleanly")
+	}
+	return err
+}
+
+// notifier returns the Notifier, or a dispatcher sending the events recorded in Store when there is none.
+func (s *EventListener) notifier() *notify.Dispatcher {
+	if s.Notifier == nil {
+		s.Notifier = notify.NewDispatcher(s.Store)
+	}
+	if s.Notifier.Observe == nil {
+		s.Notifier.Observe = s.countNotification
+	}
+	return s.Notifier
+}
+
+// startJob runs job in the background unless the EventListener is shutting down, in which case it returns false.
+func (s *EventListener) startJob(job func()) bool {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if s.draining {
+		return false
+	}
+	s.jobs.Add(1)
+	inFlight := s.serverMetrics().jobs
+	inFlight.Inc()
+	go func() {
+		defer s.jobs.Done()
+		defer inFlight.Dec()
+		job()
+	}()
+	return true
+}
+
+// drainJobs stops new jobs from starting and waits for the running ones to finish or ctx to be done.
+func (s *EventListener) drainJobs(ctx context.Context) error {
+	s.jobsMu.Lock()
+	s.draining = true
+	s.jobsMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("webhook jobs still running: %w", ctx.Err())
+	}
+}
+
+// ValidateAddr checks that addr is a host:port the server can listen on, such as :2525 or 127.0.0.1:2525.
+func ValidateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		if _, convErr := strconv.Atoi(addr); convErr == nil {
+			return fmt.Errorf("invalid listen address %q, did you mean %q?", addr, ":"+addr)
+		}
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q in listen address %q", port, addr)
+	}
+	return nil
+}
+
+var (
+	gitHubOrg  = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,37}[A-Za-z0-9])?$`)
+	gitHubRepo = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+)
+
+// ValidateGitHubRepo checks that org and repo are valid GitHub organization and repository names.
+func ValidateGitHubRepo(org, repo string) error {
+	if !gitHubOrg.MatchString(org) {
+		return fmt.Errorf("invalid GitHub organization %q", org)
+	}
+	if !gitHubRepo.MatchString(repo) || repo == "." || repo == ".." {
+		return fmt.Errorf("invalid GitHub repository %q", repo)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package onboarding
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/db"
+	"maintainerd/plugins/fossa"
+)
+
+func TestWebhookJobsDrainOnShutdown(t *testing.T) {
+	release := make(chan struct{})
+	fossaAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/teams":
+			<-release // onboarding is still running when the server is told to stop
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(fossa.Team{ID: 99, Name: "Jaeger"})
+		case r.Method == http.MethodPost && r.URL.Path == "/organizations/162/invite":
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fossaAPI.Close()
+	comments := make(chan string, 1)
+	gitHubAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var comment github.IssueComment
+		_ = json.NewDecoder(r.Body).Decode(&comment)
+		comments <- r.URL.Path + "\n" + comment.GetBody()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer gitHubAPI.Close()
+
+	fixtures, err := db.ParseFixtures([]byte(testFixtures))
+	require.NoError(t, err)
+	store := db.NewMemoryStore()
+	require.NoError(t, store.Load(fixtures))
+	fc := fossa.NewClient("test-token")
+	fc.APIBase = fossaAPI.URL
+	gh := github.NewClient(nil)
+	gh.BaseURL, err = url.Parse(gitHubAPI.URL + "/")
+	require.NoError(t, err)
+	s := &EventListener{Store: store, FossaClient: fc, GitHubClient: gh, Secret: []byte("secret"), ShutdownTimeout: 5 * time.Second}
+
+	handler, err := s.Handler()
+	require.NoError(t, err)
+	srv := &http.Server{Handler: handler}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, stop := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.serve(ctx, srv, listener) }()
+
+	payload := `{"action":"labeled","issue":{"number":7,"title":"[PROJECT ONBOARDING] Jaeger","labels":[{"name":"fossa"}]},` +
+		`"repository":{"name":"sandbox","owner":{"login":"cncf"}},"sender":{"login":"octocat"}}`
+	mac := hmac.New(sha256.New, s.Secret)
+	mac.Write([]byte(payload))
+	req, err := http.NewRequest(http.MethodPost, "http://"+listener.Addr().String()+"/webhook", strings.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("X-GitHub-Delivery", "delivery-1")
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	stop()
+	require.Eventually(t, func() bool {
+		s.jobsMu.Lock()
+		defer s.jobsMu.Unlock()
+		return s.draining
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, s.startJob(func() {}), "no jobs start while draining")
+	require.Equal(t, float64(1), testutil.ToFloat64(s.serverMetrics().jobs), "the webhook job is in flight")
+	select {
+	case err := <-stopped:
+		t.Fatalf("Run returned before the webhook job finished: %v", err)
+	default:
+	}
+
+	close(release)
+	require.NoError(t, <-stopped)
+	comment := <-comments
+	require.True(t, strings.HasPrefix(comment, "/repos/cncf/sandbox/issues/7/comments\n"))
+	require.Contains(t, comment, "has been created in FOSSA")
+	require.Equal(t, float64(1), testutil.ToFloat64(s.serverMetrics().webhooks.WithLabelValues("issues", "onboarded")))
+	require.Zero(t, testutil.ToFloat64(s.serverMetrics().jobs))
+
+	entries, err := store.ListAuditLogs(context.Background(), db.AuditFilter{CorrelationID: "delivery-1"})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.Equal(t, "octocat", entries[0].Actor)
+}
+
+func TestShutdownTimesOut(t *testing.T) {
+	s := &EventListener{Store: db.NewMemoryStore(), ShutdownTimeout: 50 * time.Millisecond}
+	stuck := make(chan struct{})
+	defer close(stuck)
+	require.True(t, s.startJob(func() { <-stuck }))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, stop := context.WithCancel(context.Background())
+	stop()
+	err = s.serve(ctx, &http.Server{Handler: http.NotFoundHandler()}, listener)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "webhook jobs still running")
+}
+
+func TestValidateFlags(t *testing.T) {
+	require.NoError(t, ValidateAddr(":2525"))
+	require.NoError(t, ValidateAddr("127.0.0.1:2525"))
+	require.NoError(t, ValidateAddr("[::1]:8080"))
+	require.ErrorContains(t, ValidateAddr("2525"), `did you mean ":2525"`)
+	require.Error(t, ValidateAddr("localhost"))
+	require.Error(t, ValidateAddr(":http-alt"))
+	require.Error(t, ValidateAddr(":70000"))
+
+	require.NoError(t, ValidateGitHubRepo("cncf", "sandbox"))
+	require.NoError(t, ValidateGitHubRepo("cncf", "foundation.github.io"))
+	require.Error(t, ValidateGitHubRepo("sandbox/cncf", "sandbox"))
+	require.Error(t, ValidateGitHubRepo("-cncf", "sandbox"))
+	require.Error(t, ValidateGitHubRepo("cncf", ""))
+	require.Error(t, ValidateGitHubRepo("cncf", "a/b"))
+}
@@ -0,0 +1,39 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"maintainerd/admin"
+	"maintainerd/api"
+	"maintainerd/model"
+)
+
+var (
+	_ api.Onboarder    = (*EventListener)(nil)
+	_ admin.TeamLister = (*EventListener)(nil)
+)
+
+// Onboard onboards the maintainers of project to service as labelling the project's onboarding issue would, for the
+// API. FOSSA is the only service supported.
+func (s *EventListener) Onboard(ctx context.Context, project model.Project, service string) ([]string, error) {
+	if !strings.EqualFold(service, "FOSSA") {
+		return nil, fmt.Errorf("%w: %s", api.ErrUnsupportedService, service)
+	}
+	teamProject, err := teamProjectFor(ctx, s.Store, project, s.SubprojectTeams)
+	if err != nil {
+		log.Printf("Onboard: WRN, using %s's own team: %v", project.Name, err)
+	}
+	return signProjectUpForFOSSA(ctx, s.Store, s.FossaClient, s.Logger, project, teamProject, s.SubprojectTeams)
+}
+
+// TeamMemberEmails lists the email addresses of the members of a team on service, for the admin UI's drift page. FOSSA
+// is the only service supported.
+func (s *EventListener) TeamMemberEmails(ctx context.Context, service string, teamID int) ([]string, error) {
+	if !strings.EqualFold(service, "FOSSA") {
+		return nil, fmt.Errorf("%w: %s", api.ErrUnsupportedService, service)
+	}
+	return s.FossaClient.FetchTeamUserEmails(ctx, teamID)
+}
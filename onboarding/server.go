@@ -2,53 +2,90 @@ package onboarding
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"maintainerd/model"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 	"google.golang.org/api/sourcerepo/v1"
-	"gorm.io/driver/sqlite"
-	"gorm.io/gorm"
 
 	"github.com/google/go-github/v55/github"
+	"go.uber.org/zap"
 
+	"maintainerd/admin"
+	"maintainerd/api"
 	"maintainerd/db"
+	"maintainerd/directory"
+	"maintainerd/notify"
 	"maintainerd/plugins/fossa"
+	"maintainerd/portal"
 )
 
 // EventListener server that handles GitHub webhook events and triggers onboarding processes using the maintainerd db and
 // known services such as FOSSA.
 type EventListener struct {
-	Store        *db.SQLStore
-	FossaClient  *fossa.Client
-	Secret       []byte
-	Projects     map[string]model.Project
-	Repo         sourcerepo.Repo
-	GitHubClient *github.Client
+	Store       db.Store
+	FossaClient *fossa.Client
+	Secret      []byte
+	Projects    *db.ProjectCache
+	ProjectTTL  time.Duration // how long Projects are cached for, db.DefaultProjectCacheTTL when zero
+	// SubprojectTeams chooses between a service team per subproject and sharing the parent project's team
+	SubprojectTeams SubprojectTeams
+	Repo            sourcerepo.Repo
+	GitHubClient    *github.Client
+	AdminToken      []byte // bearer token for the /admin endpoints, they are disabled when empty
+	// PortalOAuth is the GitHub OAuth app maintainers sign in to the /portal with, the portal is disabled when nil
+	PortalOAuth      *oauth2.Config
+	PortalSessionKey []byte // signs portal sessions, a random key is used when empty
+	Logger           *zap.SugaredLogger
+	// ShutdownTimeout bounds how long Run waits for requests and webhook jobs when stopping, DefaultShutdownTimeout
+	// when zero
+	ShutdownTimeout time.Duration
+	// Notifier sends change notifications to webhook subscriptions while Run serves, one reading Store is used when
+	// nil
+	Notifier *notify.Dispatcher
+
+	metricsOnce sync.Once
+	metrics     *serverMetrics
+	jobsMu      sync.Mutex
+	jobs        sync.WaitGroup
+	draining    bool // no more webhook jobs are started once Run is stopping
 }
 
 func (s *EventListener) Init(dbPath, fossaAPItokenEnvVar, ghToken, repo, org string) error {
-	dbConn, err := gorm.Open(sqlite.Open(dbPath))
+	dbConn, err := db.OpenSQLite(dbPath)
 	if err != nil {
 		log.Printf("error: failed to connect to db: %v", err)
 		return fmt.Errorf("connect to db: %w", err)
 	}
 	s.Store = db.NewSQLStore(dbConn)
+	if s.Logger == nil {
+		logger, err := zap.NewProduction()
+		if err != nil {
+			return fmt.Errorf("create logger: %w", err)
+		}
+		s.Logger = logger.Sugar()
+	}
 
-	projectMap, err := s.Store.GetProjectMapByName()
+	s.Projects = db.NewProjectCache(s.Store, s.ProjectTTL)
+	if err := s.Projects.InvalidateOn(dbConn); err != nil {
+		return fmt.Errorf("watch project changes: %w", err)
+	}
+	projects, err := s.Projects.Projects(context.Background())
 	if err != nil {
-		log.Printf("error: failed to get project map: %v", err)
-		return fmt.Errorf("get project map: %w", err)
+		log.Printf("error: failed to load project cache: %v", err)
+		return fmt.Errorf("load project cache: %w", err)
 	}
-	s.Projects = projectMap
-	log.Printf("Init: DBG, project map has %d entries", len(s.Projects))
+	log.Printf("Init: DBG, project cache has %d entries", len(projects))
 	log.Printf("Init: DBG, they are...")
-	for _, project := range s.Projects {
-		log.Printf("info: project: %s (%d)", project.Name, project.ID)
+	for _, info := range projects {
+		log.Printf("info: project: %s (%d)", info.Project.Name, info.Project.ID)
 	}
 	token := os.Getenv(fossaAPItokenEnvVar)
 	if token == "" {
@@ -64,126 +101,241 @@ func (s *EventListener) Init(dbPath, fossaAPItokenEnvVar, ghToken, repo, org str
 	return nil
 }
 
-// Run starts an HTTP server listening on the given address.
-func (s *EventListener) Run(addr string) error {
-	http.HandleFunc("/webhook", s.handleWebhook)
-	return http.ListenAndServe(addr, nil)
+// Handler returns the mux serving the webhook, the admin endpoints and UI, the REST API, the public directory, the
+// portal when configured, and the /healthz, /readyz and /metrics endpoints.
+func (s *EventListener) Handler() (http.Handler, error) {
+	if s.Logger == nil {
+		s.Logger = zap.NewNop().Sugar()
+	}
+	if s.Projects == nil {
+		s.Projects = db.NewProjectCache(s.Store, s.ProjectTTL)
+	}
+	s.instrumentFossa()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", s.handleWebhook)
+	mux.HandleFunc("/admin/audit", s.requireAdmin(s.handleAuditLog))
+	mux.HandleFunc("/admin/projects/refresh", s.requireAdmin(s.handleRefreshProjects))
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(s.readyChecks()))
+	mux.Handle("GET /metrics", s.serverMetrics().handler())
+	apiServer := api.NewServer(s.Store)
+	apiServer.Onboarder = s
+	apiServer.Logger = s.Logger
+	apiServer.Register(mux)
+	directory.NewServer(s.Projects).Register(mux)
+	adminUI := admin.NewServer(s.Store)
+	adminUI.Onboarder = s
+	adminUI.Teams = s
+	adminUI.Logger = s.Logger
+	adminUI.Register(mux)
+	if s.PortalOAuth != nil {
+		maintainerPortal, err := portal.NewServer(s.Store, s.PortalOAuth, s.PortalSessionKey)
+		if err != nil {
+			return nil, err
+		}
+		maintainerPortal.Logger = s.Logger
+		maintainerPortal.Register(mux)
+	}
+	return mux, nil
 }
 
+// handleWebhook onboards projects to FOSSA when their onboarding issue is labelled fossa. Onboarding runs as a job
+// after the delivery is acknowledged, GitHub gives up on deliveries that take more than a few seconds. Each delivery
+// is counted by event and outcome: invalid_signature, unparseable, ignored, rejected, or once its job finishes
+// onboarded, not_registered or failed.
 func (s *EventListener) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	eventType, outcome := "", "ignored"
+	defer func() {
+		if outcome != "" {
+			s.countWebhook(eventType, outcome)
+		}
+	}()
+
 	payload, err := github.ValidatePayload(r, s.Secret)
 	if err != nil {
+		outcome = "invalid_signature"
 		http.Error(w, "handleWebhook: github.ValidatePayload, invalid signature", http.StatusUnauthorized)
 		return
 	}
 
-	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	eventType = github.WebHookType(r)
+	event, err := github.ParseWebHook(eventType, payload)
 	if err != nil {
+		outcome = "unparseable"
 		http.Error(w, "handleWebhook: could not parse event", http.StatusBadRequest)
 		return
 	}
 
 	switch e := event.(type) {
 	case *github.IssuesEvent:
-		if e.GetAction() != "labeled" {
+		if e.GetAction() != "labeled" || !hasLabel(e.Issue, "fossa") {
 			break
 		}
-		issueTitle := e.Issue.GetTitle()
-		issueUrl := e.Issue.GetURL()
-		for _, label := range e.Issue.Labels {
-			name := label.GetName()
-			if name == "fossa" {
-				log.Printf("handleWebhook: DBG, [%s](%s) lbl fossa", issueUrl, issueTitle)
-				projectName, err := GetProjectNameFromProjectTitle(e.Issue.GetTitle())
-				if err != nil {
-					log.Printf("handleWebhook: WRN, could not parse project name [%s](%s) : %v",
-						issueUrl, issueTitle, err)
-					continue
-				}
+		// Changes made while handling this delivery are audited against its sender and delivery id. The job outlives
+		// the request so it must not be cancelled with it.
+		ctx := db.WithCorrelationID(db.WithActor(context.WithoutCancel(r.Context()), e.GetSender().GetLogin()), github.DeliveryID(r))
+		started := s.startJob(func() {
+			s.countWebhook(eventType, s.onboardFromIssue(ctx, e))
+		})
+		if !started {
+			outcome = "rejected"
+			http.Error(w, "handleWebhook: shutting down, redeliver later", http.StatusServiceUnavailable)
+			return
+		}
+		outcome = ""
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
 
-				log.Printf("handleWebhook: DBG, %s", projectName)
+func hasLabel(issue *github.Issue, name string) bool {
+	for _, label := range issue.Labels {
+		if label.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
 
-				// Get Project from db
-				var project model.Project
-				project = s.Projects[projectName]
-				actions, err := signProjectUpForFOSSA(r.Context(), s.Store, s.FossaClient, project)
-				if err != nil {
-					log.Printf("handleWebhook: ERR, failed to send FOSSA invitations: %v", err)
-				}
+// onboardFromIssue onboards the project named in the title of the issue e was sent for to FOSSA and reports what was
+// done in a comment on the issue. It returns the outcome counted for the delivery.
+func (s *EventListener) onboardFromIssue(ctx context.Context, e *github.IssuesEvent) string {
+	outcome := "ignored"
+	issueTitle := e.Issue.GetTitle()
+	issueUrl := e.Issue.GetURL()
+	for _, label := range e.Issue.Labels {
+		name := label.GetName()
+		if name == "fossa" {
+			log.Printf("handleWebhook: DBG, [%s](%s) lbl fossa", issueUrl, issueTitle)
+			projectName, err := GetProjectNameFromProjectTitle(e.Issue.GetTitle())
+			if err != nil {
+				log.Printf("handleWebhook: WRN, could not parse project name [%s](%s) : %v",
+					issueUrl, issueTitle, err)
+				continue
+			}
 
-				// Format the steps as a Markdown comment
-				var comment string
-				comment += "###  🧪 maintainerd - CNCF FOSSA Onboarding Report\n\n" +
-					"#### :spiral_notepad: Actions taken during onboarding...\n\n"
-				for _, action := range actions {
-					comment += fmt.Sprintf("- %s\n", action)
-				}
-				if err != nil {
-					comment += fmt.Sprintf("\n❌ Onboarding encountered some problems: `%s`\n", err)
-				} else {
-					comment += "---\n\n" +
-						"Once accepted:\n\n" +
-						"- 👤 The CNCF Projects Team *must first* add you to the " + projectName + " team as a **Team Admin** ([FOSSA RBAC](https://docs.fossa.com/docs/role-based-access-control#team-roles)).\n\n" +
-						"- 📦 Then, _and only then_, can you start importing your code and documentation repositories into FOSSA: [Getting Started Guide](https://docs.fossa.com/docs/getting-started#importing-a-project).\n\n"
-				}
-				err = s.updateIssue(r.Context(), e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(), comment)
-				if err != nil {
+			log.Printf("handleWebhook: DBG, %s", projectName)
+
+			// Get Project from db
+			info, err := s.Projects.Get(ctx, projectName)
+			if errors.Is(err, db.ErrProjectNotFound) {
+				log.Printf("handleWebhook: WRN, [%s](%s) %s is not registered", issueUrl, issueTitle, projectName)
+				outcome = "not_registered"
+				comment := "###  🧪 maintainerd - CNCF FOSSA Onboarding Report\n\n" +
+					fmt.Sprintf(":x: **%s** is not a registered project in maintainerd, so no FOSSA onboarding was done. ", projectName) +
+					"Please check the project name in the issue title or ask the CNCF Projects Team to register the project.\n"
+				if err := s.updateIssue(ctx, e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(), comment); err != nil {
 					log.Printf("handleWebhook: WRN, failed to update GitHub issue: %v", err)
-				} else {
-					log.Printf("handleWebhook: INF, fossa comment added [%s](%s)", issueTitle, issueUrl)
 				}
+				continue
+			} else if err != nil {
+				log.Printf("handleWebhook: ERR, failed to look up project %s: %v", projectName, err)
+				outcome = "failed"
+				continue
+			}
+			project := info.Project
+			teamProject, err := teamProjectFor(ctx, s.Store, project, s.SubprojectTeams)
+			if err != nil {
+				log.Printf("handleWebhook: WRN, using %s's own team: %v", projectName, err)
+			}
+			actions, err := signProjectUpForFOSSA(ctx, s.Store, s.FossaClient, s.Logger, project, teamProject, s.SubprojectTeams)
+			if err != nil {
+				log.Printf("handleWebhook: ERR, failed to send FOSSA invitations: %v", err)
+				outcome = "failed"
+			} else {
+				outcome = "onboarded"
+			}
+
+			// Format the steps as a Markdown comment
+			var comment string
+			comment += "###  🧪 maintainerd - CNCF FOSSA Onboarding Report\n\n" +
+				"#### :spiral_notepad: Actions taken during onboarding...\n\n"
+			for _, action := range actions {
+				comment += fmt.Sprintf("- %s\n", action)
+			}
+			if err != nil {
+				comment += fmt.Sprintf("\n❌ Onboarding encountered some problems: `%s`\n", err)
+			} else {
+				comment += "---\n\n" +
+					"Once accepted:\n\n" +
+					"- 👤 The CNCF Projects Team *must first* add you to the " + teamProject.Name + " team as a **Team Admin** ([FOSSA RBAC](https://docs.fossa.com/docs/role-based-access-control#team-roles)).\n\n" +
+					"- 📦 Then, _and only then_, can you start importing your code and documentation repositories into FOSSA: [Getting Started Guide](https://docs.fossa.com/docs/getting-started#importing-a-project).\n\n"
+			}
+			err = s.updateIssue(ctx, e.GetRepo().GetOwner().GetLogin(), e.GetRepo().GetName(), e.GetIssue().GetNumber(), comment)
+			if err != nil {
+				log.Printf("handleWebhook: WRN, failed to update GitHub issue: %v", err)
+			} else {
+				log.Printf("handleWebhook: INF, fossa comment added [%s](%s)", issueTitle, issueUrl)
 			}
 		}
 	}
-	w.WriteHeader(http.StatusOK)
+	return outcome
 }
 
 // signProjectUpForFOSSA using @store, gets the maintainers registered for @project, uses @fc to email them FOSSA invites
 // to their registered email addresses. As invitations are sent, we build up a list of actions that were taken by the
 // process so that the client can report steps taken and their results; in actions we reference maintainers using their
-// public GitHub account keeping their registered email addresses private.
-func signProjectUpForFOSSA(context context.Context, store *db.SQLStore, fc *fossa.Client, project model.Project) ([]string, error) {
+// public GitHub account keeping their registered email addresses private. Teams created and invitations sent are
+// recorded in the audit log. The FOSSA team used is the one for @teamProject, which is @project itself unless
+// @strategy has a subproject share its parent's team.
+func signProjectUpForFOSSA(ctx context.Context, store db.Store, fc *fossa.Client, logger *zap.SugaredLogger,
+	project, teamProject model.Project, strategy SubprojectTeams) ([]string, error) {
 	var actions []string
+	var fossaServiceID *uint
+	if svc, err := store.GetServiceByName(ctx, "FOSSA"); err == nil {
+		fossaServiceID = &svc.ID
+	}
 
 	// Check for maintainers registered for this project
-	maintainers, err := store.GetMaintainersByProject(project.ID)
+	maintainers, err := maintainersToOnboard(ctx, store, project, strategy)
 	if err != nil {
 		actions = append(actions, fmt.Sprintf(":x: %s maintainers are not yet registered.", project.Name))
 		return actions, fmt.Errorf("signProjectUpForFOSSA: no maintainers found for project %v, project ID", project)
 	}
 
 	actions = append(actions, fmt.Sprintf("✅  %s has %d registered maintainers", project.Name, len(maintainers)))
+	if teamProject.ID != project.ID {
+		actions = append(actions, fmt.Sprintf("🌳 %s is a subproject of %s and shares its FOSSA team", project.Name, teamProject.Name))
+	}
 
-	// Do we have a team already in FOSSA for @project?
-	serviceTeams, err := store.GetProjectServiceTeamMap("FOSSA")
+	// Do we have a team already in FOSSA for @teamProject?
+	serviceTeams, err := store.GetProjectServiceTeamMap(ctx, "FOSSA")
 	if err != nil {
 		actions = append(actions, fmt.Sprintf(":warning: Problem retrieving serviceTeams.  %v", err))
 	}
-	st, ok := serviceTeams[project.ID]
+	st, ok := serviceTeams[teamProject.ID]
 	if ok {
 		actions = append(
 			actions,
 			fmt.Sprintf("👥 [%s team](https://app.fossa.com/account/settings/organization/teams/%d) was already in FOSSA",
-				project.Name,
+				teamProject.Name,
 				st.ServiceTeamID))
 	} else {
 		// create the team on FOSSA, add the team to the ServiceTeams
-		team, err := fc.CreateTeam(project.Name)
+		team, err := fc.CreateTeam(ctx, teamProject.Name)
 
 		if err != nil {
-			actions = append(actions, fmt.Sprintf(":x: Problem creating team on FOSSA for %s: %v", project.Name, err))
+			actions = append(actions, fmt.Sprintf(":x: Problem creating team on FOSSA for %s: %v", teamProject.Name, err))
 		} else {
 			log.Printf("team created: %s", team.Name)
 			actions = append(actions,
 				fmt.Sprintf("👥  [%s team](https://app.fossa.com/account/settings/organization/teams/%d) has been created in FOSSA",
 					team.Name, team.ID))
-			_, err := store.CreateServiceTeam(project.ID, project.Name, team.ID, team.Name)
+			auditSideEffect(ctx, store, logger, model.AuditLog{
+				ProjectID: teamProject.ID,
+				ServiceID: fossaServiceID,
+				Action:    "SERVICE_TEAM_CREATED",
+				Message:   fmt.Sprintf("created FOSSA team %s (%d)", team.Name, team.ID),
+			}, map[string]any{"team_id": team.ID, "team_name": team.Name})
+			_, err := store.CreateServiceTeam(ctx, teamProject.ID, teamProject.Name, team.ID, team.Name)
 			if err != nil {
 				fmt.Printf("handleWebhook: WRN, failed to create service team: %v", err)
 			}
 		}
 		if err != nil {
-			fmt.Printf("signProjectUpForFOSSA: Error creating team on FOSSA for %s: %v", project.Name, err)
+			fmt.Printf("signProjectUpForFOSSA: Error creating team on FOSSA for %s: %v", teamProject.Name, err)
 		}
 	}
 	if len(maintainers) == 0 {
@@ -191,7 +343,7 @@ func signProjectUpForFOSSA(context context.Context, store *db.SQLStore, fc *foss
 		return actions, fmt.Errorf(":x: no maintainers found for project %d", project.ID)
 	}
 	for _, maintainer := range maintainers {
-		err := fc.SendUserInvitation(maintainer.Email) // TODO See if I can Name the User on FOSSA!
+		err := fc.SendUserInvitation(ctx, maintainer.Email) // TODO See if I can Name the User on FOSSA!
 
 		if errors.Is(err, fossa.ErrInviteAlreadyExists) {
 			actions = append(actions, fmt.Sprintf("@%s : you have a pending invitation to join CNCF FOSSA. Please check your registered email and accept the invitation within 48 hours.", maintainer.GitHubAccount))
@@ -201,6 +353,14 @@ func signProjectUpForFOSSA(context context.Context, store *db.SQLStore, fc *foss
 		} else if err != nil {
 			log.Printf("error sending invite: %v", err)
 			actions = append(actions, fmt.Sprintf("@%s : there was a problem sending a CNCF FOSSA invitation to you.", maintainer.GitHubAccount))
+		} else {
+			auditSideEffect(ctx, store, logger, model.AuditLog{
+				ProjectID:    project.ID,
+				MaintainerID: &maintainer.ID,
+				ServiceID:    fossaServiceID,
+				Action:       "INVITE_SENT",
+				Message:      fmt.Sprintf("FOSSA invitation sent to @%s", maintainer.GitHubAccount),
+			}, map[string]any{"github_account": maintainer.GitHubAccount})
 		}
 	}
 
@@ -217,3 +377,16 @@ func (s *EventListener) updateIssue(ctx context.Context, owner, repo string, iss
 	}
 	return err
 }
+
+// auditSideEffect records an action taken on an external service in the audit log, attributing it to the actor and
+// correlation id carried by ctx. Failures are logged rather than returned as the action has already happened.
+func auditSideEffect(ctx context.Context, store db.Store, logger *zap.SugaredLogger, entry model.AuditLog, metadata map[string]any) {
+	if metadata != nil {
+		if blob, err := json.Marshal(metadata); err == nil {
+			entry.Metadata = string(blob)
+		}
+	}
+	if err := store.LogAuditEvent(ctx, logger, entry); err != nil {
+		log.Printf("auditSideEffect: WRN, failed to audit %s: %v", entry.Action, err)
+	}
+}
@@ -0,0 +1,75 @@
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maintainerd/db"
+	"maintainerd/plugins/fossa"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testFixtures = `
+services:
+  - name: FOSSA
+projects:
+  - name: Jaeger
+    maturity: Graduated
+maintainers:
+  - name: Ada Lovelace
+    email: ada@example.org
+    github: ada
+    projects: [Jaeger]
+`
+
+func TestSignProjectUpForFOSSA(t *testing.T) {
+	var invited []string
+	fossaAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/teams":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(fossa.Team{ID: 99, Name: "Jaeger"})
+		case r.Method == http.MethodPost && r.URL.Path == "/organizations/162/invite":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			invited = append(invited, body["email"])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer fossaAPI.Close()
+
+	fixtures, err := db.ParseFixtures([]byte(testFixtures))
+	require.NoError(t, err)
+	store := db.NewMemoryStore()
+	require.NoError(t, store.Load(fixtures))
+	fc := fossa.NewClient("test-token")
+	fc.APIBase = fossaAPI.URL
+
+	ctx := db.WithActor(context.Background(), "octocat")
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	jaeger := projects["Jaeger"]
+
+	actions, err := signProjectUpForFOSSA(ctx, store, fc, zap.NewNop().Sugar(), jaeger, jaeger, TeamPerSubproject)
+	require.NoError(t, err)
+	require.NotEmpty(t, actions)
+	require.Equal(t, []string{"ada@example.org"}, invited)
+
+	fossaService, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+	st, err := store.GetServiceTeamByProject(ctx, jaeger.ID, fossaService.ID)
+	require.NoError(t, err)
+	require.NotNil(t, st)
+	require.Equal(t, 99, st.ServiceTeamID)
+
+	invites, err := store.ListAuditLogs(ctx, db.AuditFilter{Action: "INVITE_SENT"})
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	require.Equal(t, "octocat", invites[0].Actor)
+}
@@ -0,0 +1,50 @@
+package onboarding
+
+import (
+	"context"
+	"fmt"
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// SubprojectTeams says which service team the maintainers of a subproject are onboarded into.
+type SubprojectTeams string
+
+const (
+	// TeamPerSubproject gives every subproject a service team of its own.
+	TeamPerSubproject SubprojectTeams = "per-subproject"
+	// InheritParentTeam onboards a subproject's maintainers into the team of the top-most project in its hierarchy.
+	InheritParentTeam SubprojectTeams = "inherit-parent"
+)
+
+// ParseSubprojectTeams returns the SubprojectTeams named by s, an empty s selects TeamPerSubproject.
+func ParseSubprojectTeams(s string) (SubprojectTeams, error) {
+	switch SubprojectTeams(s) {
+	case "", TeamPerSubproject:
+		return TeamPerSubproject, nil
+	case InheritParentTeam:
+		return InheritParentTeam, nil
+	}
+	return "", fmt.Errorf("unknown subproject team strategy %q, use %q or %q", s, TeamPerSubproject, InheritParentTeam)
+}
+
+// teamProjectFor returns the project whose service team the maintainers of project join.
+func teamProjectFor(ctx context.Context, store db.Store, project model.Project, strategy SubprojectTeams) (model.Project, error) {
+	if strategy != InheritParentTeam || project.ParentProjectID == nil {
+		return project, nil
+	}
+	root, err := store.GetRootProject(ctx, project.ID)
+	if err != nil {
+		return project, fmt.Errorf("teamProjectFor: %s: %w", project.Name, err)
+	}
+	return *root, nil
+}
+
+// maintainersToOnboard returns the maintainers invited when project is onboarded. When subprojects inherit their
+// parent's team the maintainers of project's subprojects share its team, so they are included.
+func maintainersToOnboard(ctx context.Context, store db.Store, project model.Project, strategy SubprojectTeams) ([]model.Maintainer, error) {
+	if strategy == InheritParentTeam {
+		return store.GetRolledUpMaintainers(ctx, project.ID)
+	}
+	return store.GetMaintainersByProject(ctx, project.ID)
+}
@@ -0,0 +1,145 @@
+package onboarding
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+const hierarchyFixtures = `
+services:
+  - name: FOSSA
+projects:
+  - name: Kubernetes
+    maturity: Graduated
+  - name: kubectl
+    maturity: Graduated
+    parent: Kubernetes
+  - name: kubeadm
+    maturity: Graduated
+    parent: Kubernetes
+  - name: Jaeger
+    maturity: Graduated
+maintainers:
+  - name: Jane Doe
+    email: jane@example.org
+    github: janedoe
+    projects: [Kubernetes]
+  - name: John Roe
+    email: john@example.org
+    github: johnroe
+    projects: [kubectl]
+  - name: Sam Poe
+    email: sam@example.org
+    github: sampoe
+    projects: [kubeadm]
+  - name: Ada Lovelace
+    email: ada@example.org
+    github: ada
+    projects: [Jaeger]
+service_teams:
+  - project: Kubernetes
+    service: FOSSA
+    team_id: 42
+  - project: kubeadm
+    service: FOSSA
+    team_id: 43
+`
+
+func TestSubprojectTeams(t *testing.T) {
+	ctx := context.Background()
+	conn, err := db.OpenSQLite(filepath.Join(t.TempDir(), "maintainers.db"))
+	require.NoError(t, err)
+	fixtures, err := db.ParseFixtures([]byte(hierarchyFixtures))
+	require.NoError(t, err)
+	require.NoError(t, db.SeedFixtures(ctx, conn, fixtures))
+	// a subproject whose parent was removed
+	missing := uint(999)
+	orphan := model.Project{Name: "orphan", Maturity: model.Sandbox, ParentProjectID: &missing}
+	require.NoError(t, conn.Create(&orphan).Error)
+	store := db.NewSQLStore(conn)
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		project     string
+		strategy    SubprojectTeams
+		teamProject string
+		maintainers []string
+		err         error
+	}{
+		{
+			name:        "subproject with its own team",
+			project:     "kubeadm",
+			strategy:    TeamPerSubproject,
+			teamProject: "kubeadm",
+			maintainers: []string{"sam@example.org"},
+		},
+		{
+			name:        "subproject without a team inherits its parent's",
+			project:     "kubectl",
+			strategy:    InheritParentTeam,
+			teamProject: "Kubernetes",
+			maintainers: []string{"john@example.org"},
+		},
+		{
+			name:        "subproject with its own team still joins its parent's when inheriting",
+			project:     "kubeadm",
+			strategy:    InheritParentTeam,
+			teamProject: "Kubernetes",
+			maintainers: []string{"sam@example.org"},
+		},
+		{
+			name:        "top level project",
+			project:     "Jaeger",
+			strategy:    InheritParentTeam,
+			teamProject: "Jaeger",
+			maintainers: []string{"ada@example.org"},
+		},
+		{
+			name:        "top level project with a team per subproject",
+			project:     "Kubernetes",
+			strategy:    TeamPerSubproject,
+			teamProject: "Kubernetes",
+			maintainers: []string{"jane@example.org"},
+		},
+		{
+			name:        "top level project onboarding its subprojects' maintainers",
+			project:     "Kubernetes",
+			strategy:    InheritParentTeam,
+			teamProject: "Kubernetes",
+			maintainers: []string{"jane@example.org", "john@example.org", "sam@example.org"},
+		},
+		{
+			name:     "missing parent",
+			project:  "orphan",
+			strategy: InheritParentTeam,
+			err:      db.ErrProjectNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			project := projects[tc.project]
+			teamProject, err := teamProjectFor(ctx, store, project, tc.strategy)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.teamProject, teamProject.Name)
+
+			maintainers, err := maintainersToOnboard(ctx, store, project, tc.strategy)
+			require.NoError(t, err)
+			var emails []string
+			for _, m := range maintainers {
+				emails = append(emails, m.Email)
+			}
+			require.ElementsMatch(t, tc.maintainers, emails)
+		})
+	}
+}
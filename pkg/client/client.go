@@ -0,0 +1,314 @@
+// Package client calls the maintainerd API described in api/openapi.yaml, returning the wire types of api/v1. Tools
+// should use it rather than reading maintainerd's database.
+//
+//	c := client.New("https://maintainerd.cncf.io")
+//	projects, err := c.ListProjects(ctx, client.ProjectFilter{Maturity: []string{"Graduated"}})
+//
+// Writes need a foundation officer's API token in Token.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	v1 "maintainerd/api/v1"
+)
+
+// Client calls the API of the maintainerd at BaseURL.
+type Client struct {
+	BaseURL    string
+	Token      string // a foundation officer's API token, only needed for writes
+	HTTPClient *http.Client
+}
+
+// New returns a Client for the maintainerd at baseURL, such as https://maintainerd.cncf.io.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// Error is returned for a response with a 4xx or 5xx status.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("maintainerd: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsNotFound reports whether err is the API answering that a project or maintainer does not exist.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Page chooses a page of a list, zero values leave the server's defaults.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+func (p Page) query(q url.Values) url.Values {
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	return q
+}
+
+// ProjectFilter narrows ListProjects, zero values match every project.
+type ProjectFilter struct {
+	Maturity []string
+	Parent   string // only subprojects of the named project
+	TopLevel bool   // only projects that are not subprojects
+	Service  string // only projects with a team on the named service
+	Q        string // only projects whose name contains Q
+	Page
+}
+
+// MaintainerFilter narrows ListProjectMaintainers, zero values match every maintainer of the project.
+type MaintainerFilter struct {
+	Rollup  bool // include the maintainers of subprojects
+	Status  string
+	Company string
+	Page
+}
+
+// ListProjects lists projects ordered by name.
+func (c *Client) ListProjects(ctx context.Context, filter ProjectFilter) (*v1.List[v1.Project], error) {
+	q := filter.query(url.Values{})
+	if len(filter.Maturity) > 0 {
+		q.Set("maturity", strings.Join(filter.Maturity, ","))
+	}
+	if filter.Parent != "" {
+		q.Set("parent", filter.Parent)
+	}
+	if filter.TopLevel {
+		q.Set("top_level", "true")
+	}
+	if filter.Service != "" {
+		q.Set("service", filter.Service)
+	}
+	if filter.Q != "" {
+		q.Set("q", filter.Q)
+	}
+	var list v1.List[v1.Project]
+	if err := c.do(ctx, http.MethodGet, "/projects", q, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetProject returns the project called name.
+func (c *Client) GetProject(ctx context.Context, name string) (*v1.Project, error) {
+	var project v1.Project
+	if err := c.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(name), nil, nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListProjectMaintainers lists the maintainers of the project called project.
+func (c *Client) ListProjectMaintainers(ctx context.Context, project string, filter MaintainerFilter) (*v1.List[v1.Maintainer], error) {
+	q := filter.query(url.Values{})
+	if filter.Rollup {
+		q.Set("rollup", "true")
+	}
+	if filter.Status != "" {
+		q.Set("status", filter.Status)
+	}
+	if filter.Company != "" {
+		q.Set("company", filter.Company)
+	}
+	var list v1.List[v1.Maintainer]
+	if err := c.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(project)+"/maintainers", q, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListProjectServices lists the teams that hold the maintainers of the project called project on each service.
+func (c *Client) ListProjectServices(ctx context.Context, project string, page Page) (*v1.List[v1.ServiceTeam], error) {
+	var list v1.List[v1.ServiceTeam]
+	if err := c.do(ctx, http.MethodGet, "/projects/"+url.PathEscape(project)+"/services", page.query(url.Values{}), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetMaintainer returns the maintainer with GitHub handle github.
+func (c *Client) GetMaintainer(ctx context.Context, github string) (*v1.Maintainer, error) {
+	var maintainer v1.Maintainer
+	if err := c.do(ctx, http.MethodGet, "/maintainers/"+url.PathEscape(github), nil, nil, &maintainer); err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// ListServices lists services ordered by name.
+func (c *Client) ListServices(ctx context.Context, page Page) (*v1.List[v1.Service], error) {
+	var list v1.List[v1.Service]
+	if err := c.do(ctx, http.MethodGet, "/services", page.query(url.Values{}), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// AddMaintainer adds a maintainer to the project called project, returning the maintainer as registered.
+func (c *Client) AddMaintainer(ctx context.Context, project string, req v1.AddMaintainer) (*v1.Maintainer, error) {
+	var maintainer v1.Maintainer
+	if err := c.do(ctx, http.MethodPost, "/projects/"+url.PathEscape(project)+"/maintainers", nil, req, &maintainer); err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// RemoveMaintainer removes the maintainer with GitHub handle github from the project called project.
+func (c *Client) RemoveMaintainer(ctx context.Context, project, github string) error {
+	return c.do(ctx, http.MethodDelete, "/projects/"+url.PathEscape(project)+"/maintainers/"+url.PathEscape(github), nil, nil, nil)
+}
+
+// UpdateMaintainer changes the status or company of the maintainer with GitHub handle github.
+func (c *Client) UpdateMaintainer(ctx context.Context, github string, req v1.UpdateMaintainer) (*v1.Maintainer, error) {
+	var maintainer v1.Maintainer
+	if err := c.do(ctx, http.MethodPatch, "/maintainers/"+url.PathEscape(github), nil, req, &maintainer); err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// Onboard onboards the maintainers of the project called project to service. Onboarding that did not complete is
+// reported in the result's Error rather than as an error.
+func (c *Client) Onboard(ctx context.Context, project, service string) (*v1.Onboarding, error) {
+	var result v1.Onboarding
+	path := "/projects/" + url.PathEscape(project) + "/services/" + url.PathEscape(service) + "/onboard"
+	if err := c.do(ctx, http.MethodPost, path, nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateWebhook subscribes an endpoint to events, returning the subscription with its secret.
+func (c *Client) CreateWebhook(ctx context.Context, req v1.CreateWebhookSubscription) (*v1.WebhookSubscription, error) {
+	var sub v1.WebhookSubscription
+	if err := c.do(ctx, http.MethodPost, "/webhooks", nil, req, &sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// ListWebhooks lists webhook subscriptions, oldest first.
+func (c *Client) ListWebhooks(ctx context.Context, page Page) (*v1.List[v1.WebhookSubscription], error) {
+	var list v1.List[v1.WebhookSubscription]
+	if err := c.do(ctx, http.MethodGet, "/webhooks", page.query(url.Values{}), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// DeleteWebhook deletes the webhook subscription identified by id.
+func (c *Client) DeleteWebhook(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, "/webhooks/"+strconv.FormatUint(uint64(id), 10), nil, nil, nil)
+}
+
+// ListWebhookDeliveries lists the deliveries to the webhook subscription identified by id, newest first.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id uint, page Page) (*v1.List[v1.WebhookDelivery], error) {
+	var list v1.List[v1.WebhookDelivery]
+	path := "/webhooks/" + strconv.FormatUint(uint64(id), 10) + "/deliveries"
+	if err := c.do(ctx, http.MethodGet, path, page.query(url.Values{}), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ErrInvalidSignature is returned by VerifyNotification for a body that was not signed with the secret.
+var ErrInvalidSignature = errors.New("maintainerd: invalid notification signature")
+
+// VerifyNotification checks that body, delivered to a webhook subscription with the given v1.SignatureHeader, was
+// signed with the subscription's secret and decodes it.
+func VerifyNotification(secret, body []byte, signature string) (*v1.Notification, error) {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(sum)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+	var n v1.Notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("maintainerd: decoding notification: %w", err)
+	}
+	return &n, nil
+}
+
+// do makes a request to path under /api/v1 with body encoded as JSON, decoding the response into out.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.BaseURL + "/api/v1" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("maintainerd: %s %s: %w", method, path, err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("maintainerd: %s %s: %w", method, path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("maintainerd: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var e v1.Error
+		if json.NewDecoder(resp.Body).Decode(&e) == nil {
+			apiErr.Message = e.Error
+		}
+		return apiErr
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("maintainerd: %s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"maintainerd/api"
+	v1 "maintainerd/api/v1"
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+	store.AddAPIToken(model.FoundationOfficer{GitHubAccount: "octocat"}, "mdt_test", nil)
+	srv := httptest.NewServer(api.NewServer(store).Handler())
+	t.Cleanup(srv.Close)
+	return New(srv.URL + "/")
+}
+
+func TestClientReads(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	projects, err := c.ListProjects(ctx, ProjectFilter{Maturity: []string{"Graduated"}, Service: "FOSSA"})
+	require.NoError(t, err)
+	require.Equal(t, 1, projects.Total)
+	require.Equal(t, "Kubernetes", projects.Items[0].Name)
+
+	projects, err = c.ListProjects(ctx, ProjectFilter{Page: Page{Limit: 1, Offset: 2}})
+	require.NoError(t, err)
+	require.Equal(t, 3, projects.Total)
+	require.Equal(t, "kubectl", projects.Items[0].Name)
+
+	project, err := c.GetProject(ctx, "kubernetes")
+	require.NoError(t, err)
+	require.Equal(t, []string{"kubectl"}, project.Subprojects)
+
+	maintainers, err := c.ListProjectMaintainers(ctx, "Kubernetes", MaintainerFilter{Rollup: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, maintainers.Total)
+
+	teams, err := c.ListProjectServices(ctx, "Kubernetes", Page{})
+	require.NoError(t, err)
+	require.Len(t, teams.Items, 1)
+	require.Equal(t, "FOSSA", teams.Items[0].Service)
+	require.Equal(t, 42, teams.Items[0].TeamID)
+
+	services, err := c.ListServices(ctx, Page{})
+	require.NoError(t, err)
+	require.Equal(t, "FOSSA", services.Items[0].Name)
+
+	maintainer, err := c.GetMaintainer(ctx, "janedoe")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Kubernetes"}, maintainer.Projects)
+
+	maintainer, err = c.GetMaintainer(ctx, "nobody")
+	require.True(t, IsNotFound(err))
+	require.Nil(t, maintainer, "nothing is returned with an error")
+	projects, err = c.ListProjects(ctx, ProjectFilter{Service: "Nope"})
+	require.Nil(t, projects)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	require.Contains(t, apiErr.Message, "Nope")
+}
+
+func TestClientWrites(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	added, err := c.AddMaintainer(ctx, "Jaeger", v1.AddMaintainer{Name: "Octo Cat", GitHub: "octocat"})
+	require.Nil(t, added)
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	c.Token = "mdt_test"
+	added, err = c.AddMaintainer(ctx, "Jaeger", v1.AddMaintainer{Name: "Octo Cat", GitHub: "octocat", Company: "GitHub"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Jaeger"}, added.Projects)
+	require.Equal(t, "GitHub", added.Company)
+
+	emeritus := string(model.EmeritusMaintainer)
+	updated, err := c.UpdateMaintainer(ctx, "octocat", v1.UpdateMaintainer{Status: &emeritus})
+	require.NoError(t, err)
+	require.Equal(t, emeritus, updated.Status)
+
+	require.NoError(t, c.RemoveMaintainer(ctx, "Jaeger", "octocat"))
+	require.True(t, IsNotFound(c.RemoveMaintainer(ctx, "Jaeger", "octocat")))
+
+	_, err = c.Onboard(ctx, "Jaeger", "FOSSA")
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotImplemented, apiErr.StatusCode)
+}
+
+func TestClientWebhooks(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+	c.Token = "mdt_test"
+
+	sub, err := c.CreateWebhook(ctx, v1.CreateWebhookSubscription{URL: "https://example.org/hook", Events: []string{"maintainer.added"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, sub.Secret)
+	subs, err := c.ListWebhooks(ctx, Page{})
+	require.NoError(t, err)
+	require.Equal(t, 1, subs.Total)
+	deliveries, err := c.ListWebhookDeliveries(ctx, sub.ID, Page{})
+	require.NoError(t, err)
+	require.Empty(t, deliveries.Items)
+	require.NoError(t, c.DeleteWebhook(ctx, sub.ID))
+	require.True(t, IsNotFound(c.DeleteWebhook(ctx, sub.ID)))
+}
+
+func TestVerifyNotification(t *testing.T) {
+	body := []byte(`{"id":3,"event":"maintainer.added","project":"Jaeger"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	n, err := VerifyNotification([]byte("s3cret"), body, signature)
+	require.NoError(t, err)
+	require.Equal(t, "Jaeger", n.Project)
+	for _, bad := range []string{"", "sha1=" + signature[7:], "sha256=zz", signature[:len(signature)-2] + "00"} {
+		_, err = VerifyNotification([]byte("s3cret"), body, bad)
+		require.ErrorIs(t, err, ErrInvalidSignature, bad)
+	}
+	_, err = VerifyNotification([]byte("other"), body, signature)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
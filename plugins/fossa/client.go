@@ -2,6 +2,7 @@ package fossa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 
 const (
 	apiBase                    = "https://app.fossa.com/api"
+	defaultTimeout             = 30 * time.Second
 	ErrCodeInviteAlreadyExists = 2011
 	ErrCodeUserAlreadyMember   = 2001
 )
@@ -26,24 +28,26 @@ var (
 )
 
 type Client struct {
-	APIKey  string
-	APIBase string
+	APIKey     string
+	APIBase    string
+	HTTPClient *http.Client
 }
 
 func NewClient(token string) *Client {
 	return &Client{
-		APIKey:  token,
-		APIBase: apiBase,
+		APIKey:     token,
+		APIBase:    apiBase,
+		HTTPClient: &http.Client{Timeout: defaultTimeout},
 	}
 }
 
 // FetchFirstPageOfUsers returns an array of User or an error
-func (c *Client) FetchFirstPageOfUsers() ([]User, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/users", nil)
+func (c *Client) FetchFirstPageOfUsers(ctx context.Context) ([]User, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", c.APIBase+"/users", nil)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +67,7 @@ func (c *Client) FetchFirstPageOfUsers() ([]User, error) {
 	}
 	return users, nil
 }
-func (c *Client) FetchUsers() ([]User, error) {
+func (c *Client) FetchUsers(ctx context.Context) ([]User, error) {
 	var allUsers []User
 	page := 1
 	count := 100 // Adjust this value as per FOSSA API limits
@@ -72,14 +76,14 @@ func (c *Client) FetchUsers() ([]User, error) {
 		// Construct paginated URL
 		url := fmt.Sprintf("%s/users?count=%d&page=%d", c.APIBase, count, page)
 
-		req, err := http.NewRequest("GET", url, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 		req.Header.Set("Authorization", "Bearer "+c.APIKey)
 		req.Header.Set("Accept", "application/json")
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("request failed: %w", err)
 		}
@@ -112,14 +116,35 @@ func (c *Client) FetchUsers() ([]User, error) {
 	return allUsers, nil
 }
 
+// Ping checks that the FOSSA API can be reached and accepts the client's API key by fetching a single user.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.APIBase+"/users?count=1&page=1", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Ping failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Ping failed: %s", resp.Status)
+	}
+	return nil
+}
+
 // FetchUserInvitations GETs /api/user-invitations - Retrieves all active (non-expired) user invitations for an
 // organization
-func (c *Client) FetchUserInvitations() (string, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/user-invitations", nil)
+func (c *Client) FetchUserInvitations(ctx context.Context) (string, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", c.APIBase+"/user-invitations", nil)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return fmt.Sprintf("FetchUserInvitations failed %s\n", err), err
 	}
@@ -138,7 +163,7 @@ func (c *Client) FetchUserInvitations() (string, error) {
 }
 
 // SendUserInvitation uses email to send an invitation to join this org of FOSSA
-func (c *Client) SendUserInvitation(email string) error {
+func (c *Client) SendUserInvitation(ctx context.Context, email string) error {
 	payload := map[string]string{"email": email}
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
@@ -146,7 +171,7 @@ func (c *Client) SendUserInvitation(email string) error {
 	}
 
 	// TODO - orgId hard coded write GetOrg
-	req, err := http.NewRequest("POST", c.APIBase+"/organizations/"+"162"+"/invite", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.APIBase+"/organizations/"+"162"+"/invite", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -154,7 +179,7 @@ func (c *Client) SendUserInvitation(email string) error {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("FetchUserInvitations failed %w\n", err)
 	}
@@ -186,8 +211,8 @@ func (c *Client) SendUserInvitation(email string) error {
 }
 
 // FetchTeam retrieves a team by its name from the list of all teams or returns an error if the team is not found.
-func (c *Client) FetchTeam(name string) (*Team, error) {
-	teams, _ := c.FetchTeams()
+func (c *Client) FetchTeam(ctx context.Context, name string) (*Team, error) {
+	teams, _ := c.FetchTeams(ctx)
 	for _, team := range teams {
 		if team.Name == name {
 			return &team, nil
@@ -197,12 +222,12 @@ func (c *Client) FetchTeam(name string) (*Team, error) {
 }
 
 // FetchTeams calls GET /api/teams
-func (c *Client) FetchTeams() ([]Team, error) {
-	req, _ := http.NewRequest("GET", c.APIBase+"/teams", nil)
+func (c *Client) FetchTeams(ctx context.Context) ([]Team, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", c.APIBase+"/teams", nil)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -226,13 +251,13 @@ func (c *Client) FetchTeams() ([]Team, error) {
 }
 
 // FetchTeamUserEmails calls GET /api/teams/{id}/members
-func (c *Client) FetchTeamUserEmails(teamID int) ([]string, error) {
+func (c *Client) FetchTeamUserEmails(ctx context.Context, teamID int) ([]string, error) {
 	var url = fmt.Sprintf("%s/teams/%d/members", c.APIBase, teamID)
-	req, _ := http.NewRequest("GET", url, nil)
+	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -272,8 +297,8 @@ func (c *Client) GetTeamId(teams []Team, name string) (int, error) {
 }
 
 // FetchTeamsMap returns a map of FOSSA Teams keyed by the name of the team
-func (c *Client) FetchTeamsMap() (map[string]Team, error) {
-	ta, err := c.FetchTeams()
+func (c *Client) FetchTeamsMap(ctx context.Context) (map[string]Team, error) {
+	ta, err := c.FetchTeams(ctx)
 	if err != nil {
 		log.Printf("FOSSA client, FetchTeamsMap:Error fetching teams: %v", err)
 		return nil, err
@@ -288,14 +313,14 @@ func (c *Client) FetchTeamsMap() (map[string]Team, error) {
 
 // GetTeam returns a *@Team object for the team called @name if it can be retrieved and exists on FOSSA or
 // a nil Team and an error if FOSSA cannot find the team.
-func (c *Client) GetTeam(name string) (*Team, error) {
+func (c *Client) GetTeam(ctx context.Context, name string) (*Team, error) {
 	payload := map[string]string{"name": name}
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode body: %w", err)
 	}
 
-	req, err := http.NewRequest("GET", c.APIBase+"/teams", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "GET", c.APIBase+"/teams", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -303,7 +328,7 @@ func (c *Client) GetTeam(name string) (*Team, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("FetchTeams failed %w\n", err)
 	}
@@ -317,14 +342,14 @@ func (c *Client) GetTeam(name string) (*Team, error) {
 	return &team, nil
 }
 
-func (c *Client) CreateTeam(name string) (*Team, error) {
+func (c *Client) CreateTeam(ctx context.Context, name string) (*Team, error) {
 	payload := map[string]string{"name": name}
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.APIBase+"/teams", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.APIBase+"/teams", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -332,7 +357,7 @@ func (c *Client) CreateTeam(name string) (*Team, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -349,7 +374,7 @@ func (c *Client) CreateTeam(name string) (*Team, error) {
 				if err := json.Unmarshal(body, &team); err != nil {
 					return nil, fmt.Errorf("failed to decode response: %w", err)
 				}
-				team, err = c.FetchTeam(name)
+				team, err = c.FetchTeam(ctx, name)
 				if team != nil {
 					return team, nil // We disregard the team-already-exists error
 				}
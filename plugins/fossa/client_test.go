@@ -1,6 +1,7 @@
 package fossa_test
 
 import (
+	"context"
 	"maintainerd/plugins/fossa"
 	"os"
 	"testing"
@@ -14,7 +15,7 @@ func TestFetchUserInvitations_Live(t *testing.T) {
 
 	client := fossa.NewClient(apiKey)
 
-	body, err := client.FetchUserInvitations()
+	body, err := client.FetchUserInvitations(context.Background())
 	if err != nil {
 		t.Fatalf("FetchUserInvitations returned error: %v", err)
 	}
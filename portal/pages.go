@@ -0,0 +1,193 @@
+package portal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+// projectAccess is a project the maintainer maintains with its team, if any, on each service.
+type projectAccess struct {
+	Project model.Project
+	Teams   []serviceTeam
+}
+
+type serviceTeam struct {
+	Service model.Service
+	Team    *model.ServiceTeam
+	Pending bool // the maintainer has asked for the project to be onboarded to the service
+}
+
+// handleHome shows a signed in maintainer their record, projects, service access and requests, and everyone else a
+// button to sign in with GitHub.
+func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
+	login, csrf, ok := s.sessionLogin(r, time.Now())
+	if !ok {
+		s.render(w, r, http.StatusOK, "signin", nil)
+		return
+	}
+	ctx := context.WithValue(r.Context(), sessionKey{}, &session{Login: login, CSRF: csrf})
+	r = r.WithContext(ctx)
+
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, login)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	requests, err := s.Store.ListChangeRequests(ctx, db.ChangeRequestFilter{MaintainerID: &maintainer.ID})
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	services, err := s.Store.ListServices(ctx)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	pending := map[[2]uint]bool{}
+	for _, req := range requests {
+		if req.Kind == model.ChangeServiceAccess && req.Status == model.ChangePending && req.ProjectID != nil && req.ServiceID != nil {
+			pending[[2]uint{*req.ProjectID, *req.ServiceID}] = true
+		}
+	}
+	access := make([]projectAccess, 0, len(maintainer.Projects))
+	for _, p := range maintainer.Projects {
+		pa := projectAccess{Project: p}
+		for _, svc := range services {
+			st, err := s.Store.GetServiceTeamByProject(ctx, p.ID, svc.ID)
+			if err != nil {
+				s.renderError(w, r, err)
+				return
+			}
+			pa.Teams = append(pa.Teams, serviceTeam{Service: svc, Team: st, Pending: pending[[2]uint{p.ID, svc.ID}]})
+		}
+		access = append(access, pa)
+	}
+
+	s.render(w, r, http.StatusOK, "me", map[string]any{
+		"Maintainer": maintainer,
+		"Access":     access,
+		"Requests":   describeRequests(requests, maintainer.Projects, services),
+	})
+}
+
+// request is a change request as a maintainer reads it.
+type request struct {
+	model.ChangeRequest
+	Description string
+}
+
+func describeRequests(requests []model.ChangeRequest, projects []model.Project, services []model.Service) []request {
+	projectNames, serviceNames := map[uint]string{}, map[uint]string{}
+	for _, p := range projects {
+		projectNames[p.ID] = p.Name
+	}
+	for _, svc := range services {
+		serviceNames[svc.ID] = svc.Name
+	}
+	described := make([]request, len(requests))
+	for i, req := range requests {
+		described[i] = request{ChangeRequest: req}
+		switch req.Kind {
+		case model.ChangeEmail:
+			described[i].Description = "Change email to " + req.Value
+		case model.ChangeCompany:
+			described[i].Description = "Change company to " + req.Value
+		case model.ChangeServiceAccess:
+			project, service := "a project", "a service"
+			if req.ProjectID != nil && projectNames[*req.ProjectID] != "" {
+				project = projectNames[*req.ProjectID]
+			}
+			if req.ServiceID != nil && serviceNames[*req.ServiceID] != "" {
+				service = serviceNames[*req.ServiceID]
+			}
+			described[i].Description = fmt.Sprintf("Access to %s for %s", service, project)
+		}
+	}
+	return described
+}
+
+// profileErrors are the codes of the messages shown when a profile change cannot be queued, the error itself is
+// logged rather than shown.
+var profileErrors = map[model.ChangeRequestKind]string{
+	model.ChangeEmail:   "email-invalid",
+	model.ChangeCompany: "company-invalid",
+}
+
+// handleProfile queues the email and company changes typed into the profile form, fields left as they are ask for
+// nothing.
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, sessionFrom(ctx).Login)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	var queued []string
+	for _, change := range []struct {
+		kind    model.ChangeRequestKind
+		value   string
+		current string
+	}{
+		{model.ChangeEmail, r.PostFormValue("email"), maintainer.Email},
+		{model.ChangeCompany, r.PostFormValue("company"), maintainer.Company.Name},
+	} {
+		value := strings.TrimSpace(change.value)
+		if value == "" || value == change.current {
+			continue
+		}
+		_, err := s.Store.RequestChange(ctx, model.ChangeRequest{MaintainerID: maintainer.ID, Kind: change.kind, Value: value})
+		if err != nil {
+			log.Printf("portal: WRN, @%s requesting a %s change: %v", sessionFrom(ctx).Login, change.kind, err)
+			redirect(w, r, profileErrors[change.kind])
+			return
+		}
+		queued = append(queued, string(change.kind))
+	}
+	if len(queued) == 0 {
+		redirect(w, r, "nothing-changed")
+		return
+	}
+	redirect(w, r, strings.Join(queued, "-")+"-queued")
+}
+
+// handleAccess queues a request for one of the maintainer's projects to be onboarded to a service.
+func (s *Server) handleAccess(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	maintainer, err := s.Store.GetMaintainerByGitHubAccount(ctx, sessionFrom(ctx).Login)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	projectID, err1 := strconv.ParseUint(r.PostFormValue("project"), 10, 0)
+	serviceID, err2 := strconv.ParseUint(r.PostFormValue("service"), 10, 0)
+	if err := errors.Join(err1, err2); err != nil {
+		s.render(w, r, http.StatusBadRequest, "error", map[string]any{"Message": "choose a project and a service"})
+		return
+	}
+	project, service := uint(projectID), uint(serviceID)
+	_, err = s.Store.RequestChange(ctx, model.ChangeRequest{
+		MaintainerID: maintainer.ID,
+		Kind:         model.ChangeServiceAccess,
+		ProjectID:    &project,
+		ServiceID:    &service,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrNotProjectMaintainer) {
+			s.renderError(w, r, err)
+			return
+		}
+		log.Printf("portal: WRN, @%s requesting access: %v", sessionFrom(ctx).Login, err)
+		redirect(w, r, "access-invalid")
+		return
+	}
+	redirect(w, r, "access-queued")
+}
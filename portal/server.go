@@ -0,0 +1,308 @@
+// Package portal serves a self-service portal for maintainers under /portal. Maintainers sign in with GitHub and are
+// matched to the registry by their GitHub account. They can see their projects and the services those projects are on,
+// and ask for their email or company to be changed or for access to another service. Requests wait in an approval
+// queue for a foundation officer, see the admin UI.
+package portal
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"maintainerd/db"
+)
+
+// Prefix is the path under which the portal is served.
+const Prefix = "/portal"
+
+const (
+	sessionCookie = "maintainerd_portal"
+	stateCookie   = "maintainerd_oauth_state"
+	sessionMaxAge = 12 * time.Hour
+	stateMaxAge   = 10 * time.Minute
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// Server serves the portal from Store. Maintainers sign in through OAuth, a GitHub OAuth app whose callback URL is
+// the portal's /portal/callback.
+type Server struct {
+	Store      db.Store
+	OAuth      *oauth2.Config
+	SessionKey []byte // signs session cookies, sessions end when it changes
+	// GitHubLogin returns the login of the GitHub user token was issued to, it asks the GitHub API when nil
+	GitHubLogin func(ctx context.Context, token *oauth2.Token) (string, error)
+	Logger      *zap.SugaredLogger
+	pages       map[string]*template.Template
+}
+
+// NewServer returns a Server reading from and writing to store. A random session key is used when sessionKey is empty.
+func NewServer(store db.Store, oauth *oauth2.Config, sessionKey []byte) (*Server, error) {
+	if len(sessionKey) == 0 {
+		sessionKey = make([]byte, 32)
+		if _, err := rand.Read(sessionKey); err != nil {
+			return nil, fmt.Errorf("portal: generating a session key: %w", err)
+		}
+	}
+	return &Server{Store: store, OAuth: oauth, SessionKey: sessionKey, Logger: zap.NewNop().Sugar(), pages: parsePages()}, nil
+}
+
+func parsePages() map[string]*template.Template {
+	funcs := template.FuncMap{
+		"missing": db.MissingValue,
+		"time":    func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+		"prefix":  func() string { return Prefix },
+	}
+	layout := template.Must(template.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html"))
+	pages := map[string]*template.Template{}
+	for _, name := range []string{"signin", "me", "error"} {
+		page := template.Must(layout.Clone())
+		pages[name] = template.Must(page.ParseFS(templateFS, "templates/"+name+".html"))
+	}
+	return pages
+}
+
+// Register mounts the portal on mux.
+func (s *Server) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+Prefix+"/{$}", s.handleHome)
+	mux.HandleFunc("GET "+Prefix+"/login", s.handleLogin)
+	mux.HandleFunc("GET "+Prefix+"/callback", s.handleCallback)
+	mux.HandleFunc("POST "+Prefix+"/logout", s.handleLogout)
+	mux.HandleFunc("POST "+Prefix+"/profile", s.requireSession(s.handleProfile))
+	mux.HandleFunc("POST "+Prefix+"/access", s.requireSession(s.handleAccess))
+}
+
+// Handler returns an http.Handler serving only the portal.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.Register(mux)
+	return mux
+}
+
+// sign returns the signature of value under the session key.
+func (s *Server) sign(value string) string {
+	mac := hmac.New(sha256.New, s.SessionKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSession returns a signed session cookie value for the GitHub user login.
+func (s *Server) newSession(login string, now time.Time) string {
+	value := login + "|" + strconv.FormatInt(now.Add(sessionMaxAge).Unix(), 10)
+	return value + "|" + s.sign(value)
+}
+
+// sessionLogin returns the GitHub login held by the session cookie of r, or false when there is no valid session.
+func (s *Server) sessionLogin(r *http.Request, now time.Time) (string, string, bool) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return "", "", false
+	}
+	i := strings.LastIndex(cookie.Value, "|")
+	if i < 0 || !hmac.Equal([]byte(cookie.Value[i+1:]), []byte(s.sign(cookie.Value[:i]))) {
+		return "", "", false
+	}
+	login, expires, ok := strings.Cut(cookie.Value[:i], "|")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if !ok || err != nil || now.Unix() > unix {
+		return "", "", false
+	}
+	return login, s.sign("csrf|" + cookie.Value)[:32], true
+}
+
+type sessionKey struct{}
+
+// session is the maintainer signed in to the portal.
+type session struct {
+	Login string
+	CSRF  string
+}
+
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// requireSession only serves h to signed in maintainers posting the session's CSRF token. Changes made by h are
+// audited against the maintainer's GitHub account.
+func (s *Server) requireSession(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		login, csrf, ok := s.sessionLogin(r, time.Now())
+		if !ok {
+			http.Redirect(w, r, Prefix+"/", http.StatusSeeOther)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(r.PostFormValue("csrf")), []byte(csrf)) != 1 {
+			s.render(w, r, http.StatusForbidden, "error", map[string]any{"Message": "the form has expired, go back and try again"})
+			return
+		}
+		ctx := context.WithValue(r.Context(), sessionKey{}, &session{Login: login, CSRF: csrf})
+		ctx = db.WithCorrelationID(db.WithActor(ctx, login), db.NewCorrelationID())
+		h(w, r.WithContext(ctx))
+	}
+}
+
+// handleLogin sends the browser to GitHub to sign in, with a state checked on the way back.
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	state := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     Prefix,
+		MaxAge:   int(stateMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, s.OAuth.AuthCodeURL(state), http.StatusFound)
+}
+
+// handleCallback finishes signing in with GitHub and starts a session for the maintainer with the user's GitHub
+// account.
+func (s *Server) handleCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	state, err := r.Cookie(stateCookie)
+	if err != nil || state.Value == "" || subtle.ConstantTimeCompare([]byte(state.Value), []byte(r.URL.Query().Get("state"))) != 1 {
+		s.render(w, r, http.StatusBadRequest, "error", map[string]any{"Message": "signing in took too long or was started elsewhere, please try again"})
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: stateCookie, Path: Prefix, MaxAge: -1})
+	if msg := r.URL.Query().Get("error_description"); msg != "" {
+		s.render(w, r, http.StatusUnauthorized, "error", map[string]any{"Message": "GitHub did not sign you in: " + msg})
+		return
+	}
+
+	token, err := s.OAuth.Exchange(ctx, r.URL.Query().Get("code"))
+	if err != nil {
+		log.Printf("portal: WRN, exchanging an OAuth code: %v", err)
+		s.render(w, r, http.StatusUnauthorized, "error", map[string]any{"Message": "GitHub did not sign you in, please try again"})
+		return
+	}
+	login, err := s.gitHubLogin(ctx, token)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+	if _, err := s.Store.GetMaintainerByGitHubAccount(ctx, login); err != nil {
+		if errors.Is(err, db.ErrMaintainerNotFound) {
+			s.render(w, r, http.StatusForbidden, "error", map[string]any{
+				"Message": fmt.Sprintf("@%s is not registered as a maintainer of a CNCF project. Ask your project to add you.", login),
+			})
+			return
+		}
+		s.renderError(w, r, err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    s.newSession(login, time.Now()),
+		Path:     Prefix,
+		MaxAge:   int(sessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	log.Printf("portal: INF, @%s signed in", login)
+	http.Redirect(w, r, Prefix+"/", http.StatusSeeOther)
+}
+
+func (s *Server) gitHubLogin(ctx context.Context, token *oauth2.Token) (string, error) {
+	if s.GitHubLogin != nil {
+		return s.GitHubLogin(ctx, token)
+	}
+	user, _, err := github.NewClient(s.OAuth.Client(ctx, token)).Users.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("portal: fetching the signed in GitHub user: %w", err)
+	}
+	return user.GetLogin(), nil
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: Prefix, MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	http.Redirect(w, r, Prefix+"/", http.StatusSeeOther)
+}
+
+func secure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// render writes the named page with data, which is made available as .Data next to the maintainer's .Session. Forms
+// on the page find the session's CSRF token in .CSRF of data.
+func (s *Server) render(w http.ResponseWriter, r *http.Request, status int, name string, data map[string]any) {
+	sess := sessionFrom(r.Context())
+	if sess != nil {
+		if data == nil {
+			data = map[string]any{}
+		}
+		data["CSRF"] = sess.CSRF
+	}
+	var buf bytes.Buffer
+	err := s.pages[name].ExecuteTemplate(&buf, "layout.html", map[string]any{
+		"Session": sess,
+		"Message": messages[r.URL.Query().Get("msg")],
+		"Data":    data,
+	})
+	if err != nil {
+		log.Printf("portal: ERR, rendering %s: %v", name, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		log.Printf("portal: WRN, failed to write response: %v", err)
+	}
+}
+
+// renderError answers a request whose Store query failed, not found errors are the maintainer's, others are logged.
+func (s *Server) renderError(w http.ResponseWriter, r *http.Request, err error) {
+	status, message := http.StatusInternalServerError, "internal error"
+	switch {
+	case errors.Is(err, db.ErrProjectNotFound), errors.Is(err, db.ErrMaintainerNotFound), errors.Is(err, db.ErrNotProjectMaintainer):
+		status, message = http.StatusNotFound, err.Error()
+	default:
+		log.Printf("portal: ERR, %s %s: %v", r.Method, r.URL.Path, err)
+	}
+	s.render(w, r, status, "error", map[string]any{"Message": message})
+}
+
+// messages are shown at the top of the home page by their code in its msg parameter. Only these fixed texts are shown,
+// so that a link sent to a maintainer cannot show words of someone else's choosing on the portal.
+var messages = map[string]string{
+	"email-invalid":        "Your email change was not requested, enter a single address such as you@example.org.",
+	"company-invalid":      "Your company change was not requested, enter the name of your employer.",
+	"nothing-changed":      "Nothing was changed.",
+	"email-queued":         "Your email change is waiting for the CNCF projects team to approve it.",
+	"company-queued":       "Your company change is waiting for the CNCF projects team to approve it.",
+	"email-company-queued": "Your email and company changes are waiting for the CNCF projects team to approve them.",
+	"access-invalid":       "Your access request was not made, choose one of your projects and a service.",
+	"access-queued":        "Your access request is waiting for the CNCF projects team to approve it.",
+}
+
+// redirect sends the browser back to the portal's home page with the message of code shown at the top.
+func redirect(w http.ResponseWriter, r *http.Request, code string) {
+	http.Redirect(w, r, Prefix+"/?msg="+template.URLQueryEscaper(code), http.StatusSeeOther)
+}
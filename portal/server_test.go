@@ -0,0 +1,165 @@
+package portal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newPortal returns a client with a cookie jar and a portal whose GitHub OAuth app signs in the user named by the
+// code it is given.
+func newPortal(t *testing.T) (*http.Client, *httptest.Server, *db.MemoryStore) {
+	t.Helper()
+	store, err := db.NewMemoryStoreFromFile("../db/testdata/fixtures.yaml")
+	require.NoError(t, err)
+
+	gitHub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"gho_`+r.PostForm.Get("code")+`","token_type":"bearer"}`)
+	}))
+	t.Cleanup(gitHub.Close)
+
+	server, err := NewServer(store, &oauth2.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Endpoint:     oauth2.Endpoint{AuthURL: gitHub.URL + "/authorize", TokenURL: gitHub.URL + "/token"},
+	}, []byte("key"))
+	require.NoError(t, err)
+	server.GitHubLogin = func(ctx context.Context, token *oauth2.Token) (string, error) {
+		return token.AccessToken[len("gho_"):], nil
+	}
+	srv := httptest.NewServer(server.Handler())
+	t.Cleanup(srv.Close)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Jar: jar, CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != srv.Listener.Addr().String() {
+			return http.ErrUseLastResponse // stop at GitHub
+		}
+		return nil
+	}}
+	return client, srv, store
+}
+
+func fetch(t *testing.T, client *http.Client, method, u string, form url.Values) (int, string) {
+	t.Helper()
+	var resp *http.Response
+	var err error
+	if method == http.MethodPost {
+		resp, err = client.PostForm(u, form)
+	} else {
+		resp, err = client.Get(u)
+	}
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// signIn signs in as the GitHub user login, returning the status and page the browser ends on.
+func signIn(t *testing.T, client *http.Client, srv *httptest.Server, login string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(srv.URL + Prefix + "/login")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	authURL, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	state := authURL.Query().Get("state")
+	require.NotEmpty(t, state)
+	return fetch(t, client, http.MethodGet, srv.URL+Prefix+"/callback?code="+login+"&state="+state, nil)
+}
+
+var csrfField = regexp.MustCompile(`name="csrf" value="([0-9a-f]+)"`)
+
+func TestSignIn(t *testing.T) {
+	client, srv, _ := newPortal(t)
+
+	status, page := fetch(t, client, http.MethodGet, srv.URL+Prefix+"/", nil)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, page, "Sign in with GitHub")
+
+	status, _ = fetch(t, client, http.MethodGet, srv.URL+Prefix+"/callback?code=janedoe&state=forged", nil)
+	require.Equal(t, http.StatusBadRequest, status)
+
+	status, page = signIn(t, client, srv, "stranger")
+	require.Equal(t, http.StatusForbidden, status)
+	require.Contains(t, page, "@stranger is not registered")
+
+	status, page = signIn(t, client, srv, "JaneDoe")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, page, "<h1>Jane Doe</h1>")
+	require.Contains(t, page, "jane@example.org")
+	require.Contains(t, page, "team #42")
+
+	u, err := url.Parse(srv.URL + Prefix)
+	require.NoError(t, err)
+	cookies := client.Jar.Cookies(u)
+	require.Len(t, cookies, 1)
+	client.Jar.SetCookies(u, []*http.Cookie{{Name: sessionCookie, Value: "janedoe|99999999999|forged", Path: Prefix}})
+	_, page = fetch(t, client, http.MethodGet, srv.URL+Prefix+"/", nil)
+	require.Contains(t, page, "Sign in with GitHub")
+}
+
+func TestRequests(t *testing.T) {
+	client, srv, store := newPortal(t)
+	status, page := signIn(t, client, srv, "ada")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, page, "Request access")
+	csrf := csrfField.FindStringSubmatch(page)[1]
+
+	status, _ = fetch(t, client, http.MethodPost, srv.URL+Prefix+"/profile", url.Values{"company": {"Analytical Engines"}})
+	require.Equal(t, http.StatusForbidden, status)
+
+	_, page = fetch(t, client, http.MethodPost, srv.URL+Prefix+"/profile", url.Values{
+		"csrf": {csrf}, "email": {"ada@example.org"}, "company": {"Analytical Engines"},
+	})
+	require.Contains(t, page, "Your company change is waiting")
+	require.Contains(t, page, "Change company to Analytical Engines")
+
+	_, page = fetch(t, client, http.MethodPost, srv.URL+Prefix+"/profile", url.Values{"csrf": {csrf}, "email": {"not an email"}})
+	require.Contains(t, page, "Your email change was not requested")
+	require.NotContains(t, page, "not an email address", "errors are not shown")
+
+	ctx := context.Background()
+	projects, err := store.GetProjectMapByName(ctx)
+	require.NoError(t, err)
+	fossa, err := store.GetServiceByName(ctx, "FOSSA")
+	require.NoError(t, err)
+	id := func(id uint) string { return strconv.FormatUint(uint64(id), 10) }
+	access := url.Values{"csrf": {csrf}, "project": {id(projects["Jaeger"].ID)}, "service": {id(fossa.ID)}}
+	_, page = fetch(t, client, http.MethodPost, srv.URL+Prefix+"/access", access)
+	require.Contains(t, page, "Your access request is waiting")
+	require.Contains(t, page, "Access to FOSSA for Jaeger")
+	require.NotContains(t, page, "Request access")
+
+	access.Set("project", id(projects["Kubernetes"].ID))
+	status, _ = fetch(t, client, http.MethodPost, srv.URL+Prefix+"/access", access)
+	require.Equal(t, http.StatusNotFound, status)
+
+	pending, err := store.ListChangeRequests(ctx, db.ChangeRequestFilter{Status: model.ChangePending})
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	entries, err := store.ListAuditLogs(ctx, db.AuditFilter{Actor: "ada", Action: "CREATE_CHANGE_REQUESTS"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	_, page = fetch(t, client, http.MethodGet, srv.URL+Prefix+"/?msg="+url.QueryEscape("Your access was revoked, email evil@example.org"), nil)
+	require.NotContains(t, page, "evil@example.org", "only the portal's own messages are shown")
+	require.NotContains(t, page, `class="flash"`)
+}